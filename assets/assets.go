@@ -3,19 +3,32 @@ package assets
 import "embed"
 
 const (
+	// MailTemplatesDir has a set of the mail templates per locale, e.g. templates/mail/uk
 	MailTemplatesDir  = "templates/mail"
 	TemplateIndexHTML = "static/index.html"
+	// IconsDir has the weather condition icons attached to the emails inline, e.g. icons/rain.png
+	IconsDir = "icons"
 
-	TemplateConfirmation        = "confirmation.html"
-	TemplateNotification        = "notification.html"
-	TemplateConfirmationSuccess = "confirmation_success.html"
+	// every mail template has the HTML version and the plain-text alternative, e.g. confirmation.html and confirmation.txt
+	TemplateExtHTML = ".html"
+	TemplateExtText = ".txt"
+
+	TemplateConfirmation        = "confirmation"
+	TemplateNotification        = "notification"
+	TemplateConfirmationSuccess = "confirmation_success"
+	TemplateDailyDigest         = "daily_digest"
+	TemplateAlert               = "alert"
+	TemplateMerged              = "merged"
 )
 
 //go:embed migrations/*.sql
 var Migrations embed.FS
 
-//go:embed templates/mail/*.html
+//go:embed templates/mail/*/*.html templates/mail/*/*.txt
 var MailTemplates embed.FS
 
 //go:embed static/index.html
 var IndexHTML embed.FS
+
+//go:embed icons/*.png
+var Icons embed.FS
@@ -0,0 +1,21 @@
+-- +migrate Up
+
+-- canonical location resolved on subscribe, empty location_id means a legacy subscription tracked by the city name only
+ALTER TABLE subscriptions
+    ADD COLUMN location_id VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN region VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN country VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN latitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN longitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
+
+
+
+-- +migrate Down
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS location_id,
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS country,
+    DROP COLUMN IF EXISTS latitude,
+    DROP COLUMN IF EXISTS longitude,
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,34 @@
+-- +migrate Up
+
+-- outbox of the rendered notifications, the scheduler enqueues them and the dispatcher delivers them
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    -- kept as a delivery history after unsubscribing
+    subscription_id BIGINT REFERENCES subscriptions(id) ON DELETE SET NULL,
+    -- one notification per subscription and period, so rescheduling the same period is a no-op
+    idempotency_key VARCHAR(128) NOT NULL,
+
+    email VARCHAR(320) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+
+    status VARCHAR(7) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT unique_idempotency_key UNIQUE (idempotency_key)
+);
+
+CREATE INDEX idx_notifications_pending ON notifications(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_notifications_subscription ON notifications(subscription_id);
+
+
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_notifications_subscription;
+DROP INDEX IF EXISTS idx_notifications_pending;
+DROP TABLE IF EXISTS notifications;
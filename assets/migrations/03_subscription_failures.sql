@@ -0,0 +1,21 @@
+-- +migrate Up
+
+-- failure tracking of the notification deliveries, the subscription is skipped until next_retry_at
+-- and is not notified at all once suspended
+ALTER TABLE subscriptions
+    ADD COLUMN consecutive_failures INT NOT NULL DEFAULT 0,
+    ADD COLUMN next_retry_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN last_error TEXT,
+    ADD COLUMN suspended_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_subscriptions_suspended ON subscriptions(suspended_at) WHERE suspended_at IS NOT NULL;
+
+
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_subscriptions_suspended;
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS consecutive_failures,
+    DROP COLUMN IF EXISTS next_retry_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS suspended_at;
@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- claim of the subscription by one of the notificator instances, expired leases are claimed again,
+-- so a crashed instance does not block the subscription
+ALTER TABLE subscriptions ADD COLUMN lease_expires_at TIMESTAMP WITH TIME ZONE;
+
+
+
+-- +migrate Down
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,38 @@
+-- +migrate Up
+
+-- preferred hour of the daily delivery in the subscription timezone (empty timezone means UTC)
+ALTER TABLE subscriptions
+    ADD COLUMN delivery_hour SMALLINT NOT NULL DEFAULT 8 CHECK (delivery_hour BETWEEN 0 AND 23);
+
+-- last_delivery_slot returns the latest scheduled delivery moment not later than ts:
+-- the start of the UTC hour for the hourly subscriptions, and the delivery hour of the local day for the daily ones.
+-- The local wall-clock time is converted back for the particular date, so the slot follows the DST shifts:
+-- a nonexistent local time (spring forward) is moved forward by the shift, an ambiguous one (fall back)
+-- is resolved to a single moment, so exactly one slot exists per local day.
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION last_delivery_slot(frequency VARCHAR, tz VARCHAR, delivery_hour SMALLINT, ts TIMESTAMPTZ)
+RETURNS TIMESTAMPTZ AS $$
+DECLARE
+    zone       TEXT := COALESCE(NULLIF(tz, ''), 'UTC');
+    local_date DATE := (ts AT TIME ZONE zone)::DATE;
+    slot       TIMESTAMPTZ;
+BEGIN
+    IF frequency = 'hourly' THEN
+        RETURN date_trunc('hour', ts AT TIME ZONE 'UTC') AT TIME ZONE 'UTC';
+    END IF;
+
+    slot := (local_date + make_interval(hours => delivery_hour)) AT TIME ZONE zone;
+    IF slot > ts THEN
+        slot := (local_date - 1 + make_interval(hours => delivery_hour)) AT TIME ZONE zone;
+    END IF;
+
+    RETURN slot;
+END
+$$ LANGUAGE plpgsql STABLE;
+-- +migrate StatementEnd
+
+
+
+-- +migrate Down
+DROP FUNCTION IF EXISTS last_delivery_slot(VARCHAR, VARCHAR, SMALLINT, TIMESTAMPTZ);
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS delivery_hour;
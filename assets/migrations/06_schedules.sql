@@ -0,0 +1,40 @@
+-- +migrate Up
+
+-- weekly and custom (cron) frequencies, every subscription stores its cron schedule,
+-- next_notify_at is the next activation computed by the application in the subscription timezone
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_frequency_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_frequency_check CHECK (frequency IN ('daily', 'hourly', 'weekly', 'custom')),
+    ADD COLUMN schedule VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN next_notify_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE subscriptions SET schedule = CASE frequency
+    WHEN 'hourly' THEN '0 * * * *'
+    ELSE '0 ' || delivery_hour || ' * * *'
+END;
+
+-- the slot computed by the previous scheduling is kept if it is not notified yet, otherwise the next one is taken
+-- (the last slot not later than 25 hours after the current one is the next day's one, even across the DST shifts)
+UPDATE subscriptions SET next_notify_at = CASE
+    WHEN COALESCE(last_notified_at, created_at, '-infinity') < last_delivery_slot(frequency, timezone, delivery_hour, CURRENT_TIMESTAMP)
+        THEN last_delivery_slot(frequency, timezone, delivery_hour, CURRENT_TIMESTAMP)
+    WHEN frequency = 'hourly'
+        THEN last_delivery_slot(frequency, timezone, delivery_hour, CURRENT_TIMESTAMP) + INTERVAL '1 hour'
+    ELSE last_delivery_slot(frequency, timezone, delivery_hour,
+        last_delivery_slot(frequency, timezone, delivery_hour, CURRENT_TIMESTAMP) + INTERVAL '25 hours')
+END;
+
+-- last_delivery_slot is not used anymore, but is kept for the rollback of this migration
+
+CREATE INDEX idx_subscriptions_next_notify ON subscriptions(next_notify_at) WHERE confirmed AND suspended_at IS NULL;
+
+
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_subscriptions_next_notify;
+UPDATE subscriptions SET frequency = 'daily' WHERE frequency IN ('weekly', 'custom');
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_frequency_check;
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_frequency_check CHECK (frequency IN ('daily', 'hourly')),
+    DROP COLUMN IF EXISTS schedule,
+    DROP COLUMN IF EXISTS next_notify_at;
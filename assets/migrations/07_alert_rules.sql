@@ -0,0 +1,45 @@
+-- +migrate Up
+
+-- conditional alerts, e.g. "rain chance above 70% tomorrow", confirmed and deleted with the same token flow as subscriptions
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(320) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    location_id VARCHAR(64) NOT NULL DEFAULT '',
+    region VARCHAR(100) NOT NULL DEFAULT '',
+    country VARCHAR(100) NOT NULL DEFAULT '',
+    latitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    longitude DOUBLE PRECISION NOT NULL DEFAULT 0,
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+
+    metric VARCHAR(16) NOT NULL CHECK (metric IN ('rain_chance', 'min_temperature', 'max_temperature', 'max_wind')),
+    operator VARCHAR(5) NOT NULL CHECK (operator IN ('above', 'below')),
+    threshold DOUBLE PRECISION NOT NULL,
+    -- 0 is today, 1 is tomorrow (in the location timezone)
+    day_offset SMALLINT NOT NULL DEFAULT 0 CHECK (day_offset BETWEEN 0 AND 1),
+
+    confirmed BOOLEAN DEFAULT FALSE,
+    -- confirmation token, replaced by the unsubscribe one once confirmed (see subscriptions.token)
+    token VARCHAR(32) NOT NULL,
+
+    -- the rule fires once the condition becomes true and is not fired again until it turns false (de-duplication)
+    triggered BOOLEAN NOT NULL DEFAULT FALSE,
+    last_checked_at TIMESTAMP WITH TIME ZONE,
+    lease_expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_alert_rule UNIQUE (email, city, location_id, metric, operator, threshold, day_offset),
+    CONSTRAINT unique_alert_rule_token UNIQUE (token)
+);
+
+CREATE INDEX idx_alert_rules_check ON alert_rules(last_checked_at) WHERE confirmed;
+
+-- alerts are delivered through the outbox as well
+ALTER TABLE notifications ADD COLUMN alert_rule_id BIGINT REFERENCES alert_rules(id) ON DELETE SET NULL;
+
+
+
+-- +migrate Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS alert_rule_id;
+DROP INDEX IF EXISTS idx_alert_rules_check;
+DROP TABLE IF EXISTS alert_rules;
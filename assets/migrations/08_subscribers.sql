@@ -0,0 +1,42 @@
+-- +migrate Up
+
+-- subscribers are the email identities verified once, a subscriber may follow several locations,
+-- while every subscription keeps its own token to be confirmed and unsubscribed separately
+CREATE TABLE IF NOT EXISTS subscribers (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(320) NOT NULL,
+    -- set once any of the subscriptions is confirmed, the new subscriptions of the verified address are confirmed right away
+    confirmed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT unique_subscriber_email UNIQUE (email)
+);
+
+-- the emails were unique, so every existing subscription gets its own subscriber
+INSERT INTO subscribers (email, confirmed, created_at)
+SELECT email, COALESCE(confirmed, FALSE), created_at FROM subscriptions;
+
+ALTER TABLE subscriptions ADD COLUMN subscriber_id BIGINT REFERENCES subscribers(id) ON DELETE CASCADE;
+UPDATE subscriptions SET subscriber_id = subscribers.id FROM subscribers WHERE subscribers.email = subscriptions.email;
+ALTER TABLE subscriptions
+    ALTER COLUMN subscriber_id SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS unique_email,
+    DROP COLUMN email,
+    ADD CONSTRAINT unique_subscription_location UNIQUE (subscriber_id, city, location_id);
+
+
+
+-- +migrate Down
+-- only the earliest subscription of every subscriber is kept, as the email is unique again
+DELETE FROM subscriptions WHERE id NOT IN (SELECT MIN(id) FROM subscriptions GROUP BY subscriber_id);
+
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS unique_subscription_location,
+    ADD COLUMN email VARCHAR(320);
+UPDATE subscriptions SET email = subscribers.email FROM subscribers WHERE subscribers.id = subscriptions.subscriber_id;
+ALTER TABLE subscriptions
+    ALTER COLUMN email SET NOT NULL,
+    ADD CONSTRAINT unique_email UNIQUE (email),
+    DROP COLUMN subscriber_id;
+
+DROP TABLE IF EXISTS subscribers;
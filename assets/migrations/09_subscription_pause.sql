@@ -0,0 +1,16 @@
+-- +migrate Up
+
+-- the subscription paused by its owner is kept, but not notified until resumed
+ALTER TABLE subscriptions ADD COLUMN paused_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_subscriptions_next_notify;
+CREATE INDEX idx_subscriptions_next_notify ON subscriptions(next_notify_at)
+    WHERE confirmed AND suspended_at IS NULL AND paused_at IS NULL;
+
+
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_subscriptions_next_notify;
+CREATE INDEX idx_subscriptions_next_notify ON subscriptions(next_notify_at) WHERE confirmed AND suspended_at IS NULL;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS paused_at;
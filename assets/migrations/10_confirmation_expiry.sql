@@ -0,0 +1,14 @@
+-- +migrate Up
+
+-- the confirmation tokens expire (the TTL is configured in the application) and are reissued on request,
+-- the unconfirmed subscriptions are purged some time after the last token was sent
+ALTER TABLE subscriptions ADD COLUMN confirmation_sent_at TIMESTAMP WITH TIME ZONE;
+UPDATE subscriptions SET confirmation_sent_at = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE NOT confirmed;
+
+CREATE INDEX idx_subscriptions_unconfirmed ON subscriptions(confirmation_sent_at) WHERE NOT confirmed;
+
+
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_subscriptions_unconfirmed;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS confirmation_sent_at;
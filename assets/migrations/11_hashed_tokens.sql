@@ -0,0 +1,52 @@
+-- +migrate Up
+
+-- the confirmation and unsubscribe tokens are separate and stored as SHA-256 hashes, so a leaked database
+-- does not expose the links; the owner may have several unsubscribe tokens, e.g. one per alert email
+CREATE TABLE IF NOT EXISTS tokens (
+    hash CHAR(64) PRIMARY KEY,
+    purpose VARCHAR(16) NOT NULL CHECK (purpose IN ('confirmation', 'unsubscribe')),
+    subscription_id BIGINT REFERENCES subscriptions(id) ON DELETE CASCADE,
+    alert_rule_id BIGINT REFERENCES alert_rules(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT token_owner CHECK ((subscription_id IS NULL) <> (alert_rule_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tokens_subscription ON tokens (subscription_id) WHERE subscription_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tokens_alert_rule ON tokens (alert_rule_id) WHERE alert_rule_id IS NOT NULL;
+
+-- the sent links keep working: the single token is the confirmation one until it is used, and the unsubscribe one after
+INSERT INTO tokens (hash, purpose, subscription_id, created_at)
+SELECT encode(sha256(convert_to(token, 'UTF8')), 'hex'),
+       CASE WHEN confirmed THEN 'unsubscribe' ELSE 'confirmation' END,
+       id,
+       COALESCE(confirmation_sent_at, created_at)
+FROM subscriptions;
+
+INSERT INTO tokens (hash, purpose, alert_rule_id, created_at)
+SELECT encode(sha256(convert_to(token, 'UTF8')), 'hex'),
+       CASE WHEN confirmed THEN 'unsubscribe' ELSE 'confirmation' END,
+       id,
+       created_at
+FROM alert_rules;
+
+ALTER TABLE subscriptions DROP COLUMN token;
+ALTER TABLE alert_rules DROP COLUMN token;
+
+
+
+-- +migrate Down
+-- the plaintext tokens cannot be restored, so every row gets a new random token and the sent links stop working
+ALTER TABLE subscriptions ADD COLUMN token VARCHAR(32);
+UPDATE subscriptions SET token = md5(random()::text || id::text);
+ALTER TABLE subscriptions
+    ALTER COLUMN token SET NOT NULL,
+    ADD CONSTRAINT unique_token UNIQUE (token);
+
+ALTER TABLE alert_rules ADD COLUMN token VARCHAR(32);
+UPDATE alert_rules SET token = md5(random()::text || id::text);
+ALTER TABLE alert_rules
+    ALTER COLUMN token SET NOT NULL,
+    ADD CONSTRAINT unique_alert_rule_token UNIQUE (token);
+
+DROP TABLE IF EXISTS tokens;
@@ -0,0 +1,9 @@
+-- +migrate Up
+
+-- the custom email headers rendered along with the body, e.g. the one-click unsubscribe ones
+ALTER TABLE notifications ADD COLUMN headers JSONB;
+
+
+
+-- +migrate Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS headers;
@@ -0,0 +1,9 @@
+-- +migrate Up
+
+-- the plain-text alternative of the body, the notifications enqueued before are sent as HTML only
+ALTER TABLE notifications ADD COLUMN text_body TEXT NOT NULL DEFAULT '';
+
+
+
+-- +migrate Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS text_body;
@@ -0,0 +1,11 @@
+-- +migrate Up
+
+-- the language of the emails (ISO 639-1 code), the existing records keep getting English ones
+ALTER TABLE subscriptions ADD COLUMN language VARCHAR(8) NOT NULL DEFAULT 'en';
+ALTER TABLE alert_rules ADD COLUMN language VARCHAR(8) NOT NULL DEFAULT 'en';
+
+
+
+-- +migrate Down
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS language;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS language;
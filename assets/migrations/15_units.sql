@@ -0,0 +1,9 @@
+-- +migrate Up
+
+-- the unit system of the emails (metric or imperial), the existing subscriptions keep getting metric ones
+ALTER TABLE subscriptions ADD COLUMN units VARCHAR(16) NOT NULL DEFAULT 'metric';
+
+
+
+-- +migrate Down
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS units;
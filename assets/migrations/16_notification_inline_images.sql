@@ -0,0 +1,9 @@
+-- +migrate Up
+
+-- the content IDs of the embedded images attached inline, e.g. the weather condition icons
+ALTER TABLE notifications ADD COLUMN inline_images JSONB;
+
+
+
+-- +migrate Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS inline_images;
@@ -0,0 +1,13 @@
+-- +migrate Up
+
+-- the confirmation tokens of the alert rules expire, are reissued and purged the same way as the subscription ones
+ALTER TABLE alert_rules ADD COLUMN confirmation_sent_at TIMESTAMP WITH TIME ZONE;
+UPDATE alert_rules SET confirmation_sent_at = COALESCE(created_at, CURRENT_TIMESTAMP) WHERE NOT confirmed;
+
+CREATE INDEX idx_alert_rules_unconfirmed ON alert_rules(confirmation_sent_at) WHERE NOT confirmed;
+
+
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_alert_rules_unconfirmed;
+ALTER TABLE alert_rules DROP COLUMN IF EXISTS confirmation_sent_at;
@@ -0,0 +1,10 @@
+-- +migrate Up
+
+-- the merged email is delivered for several subscriptions of the address, the ones besides subscription_id
+-- are listed here, so the delivery outcome is tracked by all of them
+ALTER TABLE notifications ADD COLUMN merged_subscription_ids JSONB;
+
+
+
+-- +migrate Down
+ALTER TABLE notifications DROP COLUMN IF EXISTS merged_subscription_ids;
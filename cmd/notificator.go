@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/signal"
+	"syscall"
+
+	"github.com/slbmax/ses-weather-app/internal/config"
+	"github.com/spf13/cobra"
+	"gitlab.com/distributed_lab/kit/kv"
+	"golang.org/x/sync/errgroup"
+)
+
+func init() {
+	notificatorCmd.Flags().BoolVar(&useMocks, "mocks", false, "Use mock APIs for testing purposes")
+}
+
+// notificatorCmd runs the notificator only, any number of its instances
+// can be deployed alongside the api ones started with "run --notificator=false"
+var notificatorCmd = &cobra.Command{
+	Use:   "notificator",
+	Short: "Run the Weather App notificator without the server",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		getter, err := kv.FromEnv()
+		if err != nil {
+			return fmt.Errorf("failed to get configer key-value getter: %w", err)
+		}
+
+		cfg := config.New(getter)
+
+		stopCtx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+		defer cancel()
+
+		var (
+			eg, ctx = errgroup.WithContext(stopCtx)
+			logger  = cfg.Log()
+		)
+
+		svc, err := newServices(ctx, eg, cfg, logger)
+		if err != nil {
+			return err
+		}
+
+		runNotificator(ctx, eg, cfg, svc, logger)
+
+		return eg.Wait()
+	},
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/config"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+// newWeatherProvider builds the provider chain selected in the weather_providers config section
+func newWeatherProvider(cfg *config.Config) (weatherapi.WeatherProvider, error) {
+	providersCfg := cfg.WeatherProvidersConfig()
+	providersOpts := weatherapi.ClientOpts{
+		RequestTimeout: providersCfg.RequestTimeout,
+		MaxRetries:     providersCfg.MaxRetries,
+		BackoffBase:    providersCfg.BackoffBase,
+		BackoffMax:     providersCfg.BackoffMax,
+	}
+
+	registry := weatherapi.NewRegistry()
+	registry.Register(weatherapi.ProviderWeatherAPI, func() (weatherapi.WeatherProvider, error) {
+		weatherApiCfg := cfg.WeatherAPIConfig()
+		return weatherapi.NewClient(weatherApiCfg.APIKey, weatherapi.ClientOpts{
+			RequestTimeout: weatherApiCfg.RequestTimeout,
+			MaxRetries:     weatherApiCfg.MaxRetries,
+			BackoffBase:    weatherApiCfg.BackoffBase,
+			BackoffMax:     weatherApiCfg.BackoffMax,
+		}), nil
+	})
+	registry.Register(weatherapi.ProviderOpenMeteo, func() (weatherapi.WeatherProvider, error) {
+		return weatherapi.NewOpenMeteoClient(providersOpts), nil
+	})
+	registry.Register(weatherapi.ProviderOpenWeatherMap, func() (weatherapi.WeatherProvider, error) {
+		if providersCfg.OpenWeatherMapAPIKey == "" {
+			return nil, errors.New("openweathermap_api_key is not set")
+		}
+		return weatherapi.NewOpenWeatherMapClient(providersCfg.OpenWeatherMapAPIKey, providersOpts), nil
+	})
+
+	return registry.Build(providersCfg.Priority, weatherapi.FailoverOpts{
+		FailureThreshold: providersCfg.FailureThreshold,
+		Cooldown:         providersCfg.Cooldown,
+	})
+}
+
+// logCacheStats periodically reports the weather cache efficiency
+func logCacheStats(ctx context.Context, cache *weatherapi.CachedProvider, interval time.Duration, logger *logan.Entry) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := cache.Stats()
+			logger.
+				WithField("hits", stats.Hits).
+				WithField("misses", stats.Misses).
+				WithField("evictions", stats.Evictions).
+				WithField("entries", stats.Entries).
+				Info("weather cache stats")
+		}
+	}
+}
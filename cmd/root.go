@@ -12,7 +12,7 @@ func Execute() {
 		Short: "Weather App CLI",
 	}
 
-	root.AddCommand(migrateCmd, runCmd)
+	root.AddCommand(migrateCmd, runCmd, notificatorCmd)
 
 	if err := root.Execute(); err != nil {
 		os.Exit(1)
@@ -10,19 +10,20 @@ import (
 	"github.com/slbmax/ses-weather-app/internal/api"
 	"github.com/slbmax/ses-weather-app/internal/config"
 	"github.com/slbmax/ses-weather-app/internal/database/pg"
-	"github.com/slbmax/ses-weather-app/internal/mailer"
-	"github.com/slbmax/ses-weather-app/internal/notificator"
-	"github.com/slbmax/ses-weather-app/pkg/mailjet"
-	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 	"github.com/spf13/cobra"
 	"gitlab.com/distributed_lab/kit/kv"
 	"golang.org/x/sync/errgroup"
 )
 
-var useMocks bool
+var (
+	useMocks        bool
+	withNotificator bool
+)
 
 func init() {
 	runCmd.Flags().BoolVar(&useMocks, "mocks", false, "Use mock APIs for testing purposes")
+	runCmd.Flags().BoolVar(&withNotificator, "notificator", true,
+		"Run the notificator in the same process, disable it when it is deployed with the separate command")
 }
 
 var runCmd = &cobra.Command{
@@ -40,51 +41,47 @@ var runCmd = &cobra.Command{
 		defer cancel()
 
 		var (
-			eg, ctx    = errgroup.WithContext(stopCtx)
-			mail       mailer.Mailer
-			weatherApi weatherapi.WeatherProvider
-			logger     = cfg.Log()
+			eg, ctx = errgroup.WithContext(stopCtx)
+			logger  = cfg.Log()
 		)
 
-		if useMocks {
-			mail = mailer.NewMockMailer()
-			weatherApi = weatherapi.NewMockWeatherProvider()
-		} else {
-			mailjetCfg := cfg.MailjetConfig()
-			mailjetClient := mailjet.NewClient(
-				mailjetCfg.ApiKey,
-				mailjetCfg.SecretKey,
-				mailjet.From{
-					Name:  mailjetCfg.FromName,
-					Email: mailjetCfg.FromEmail,
-				},
-			)
-			mail = mailer.NewMailer(mailjetClient)
-			weatherApi = weatherapi.NewClient(cfg.WeatherAPIConfig().APIKey)
+		svc, err := newServices(ctx, eg, cfg, logger)
+		if err != nil {
+			return err
 		}
 
+		rateLimitsCfg := cfg.RateLimitsConfig()
+		confirmationCfg := cfg.ConfirmationConfig()
 		eg.Go(func() error {
 			server := api.NewServer(
 				cfg.Listener(),
-				weatherApi,
+				svc.weatherApi,
 				pg.NewDatabase(cfg.DB()),
-				mail,
+				svc.mail,
 				logger.WithField("component", "api"),
+				api.ServerOpts{
+					AdminApiKeys: cfg.AdminConfig().Keys(),
+					CitySearchRateLimit: api.RateLimit{
+						Rate:  rateLimitsCfg.CitySearchRate,
+						Burst: rateLimitsCfg.CitySearchBurst,
+					},
+					ResendRateLimit: api.RateLimit{
+						Rate:  rateLimitsCfg.ResendRate,
+						Burst: rateLimitsCfg.ResendBurst,
+					},
+					TrustProxy:                 rateLimitsCfg.TrustProxy,
+					ConfirmationTokenTTL:       confirmationCfg.TokenTTL,
+					ConfirmationResendInterval: confirmationCfg.ResendInterval,
+					UnsubscribeLinks:           svc.unsubscribe,
+				},
 			)
 
 			return server.Run(ctx)
 		})
 
-		eg.Go(func() error {
-			notificator.New(
-				pg.NewDatabase(cfg.DB()),
-				weatherApi,
-				mail,
-				logger.WithField("component", "notificator"),
-			).Run(ctx)
-
-			return nil
-		})
+		if withNotificator {
+			runNotificator(ctx, eg, cfg, svc, logger)
+		}
 
 		serveStaticCfg := cfg.ServeStaticConfig()
 		if serveStaticCfg.Enabled {
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/slbmax/ses-weather-app/internal/config"
+	"github.com/slbmax/ses-weather-app/internal/database/pg"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/internal/notificator"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
+	"github.com/slbmax/ses-weather-app/pkg/mailjet"
+	"github.com/slbmax/ses-weather-app/pkg/smtp"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	"gitlab.com/distributed_lab/logan/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+// services are shared by the api and the notificator within one process
+type services struct {
+	mail        mailer.Mailer
+	weatherApi  weatherapi.WeatherProvider
+	unsubscribe *unsubscribe.Links
+}
+
+// newServices builds the mailer and the cached weather provider, starting the cache stats reporting
+func newServices(ctx context.Context, eg *errgroup.Group, cfg *config.Config, logger *logan.Entry) (*services, error) {
+	var (
+		mail       mailer.Mailer
+		weatherApi weatherapi.WeatherProvider
+		err        error
+	)
+
+	if useMocks {
+		mail = mailer.NewMockMailer()
+		weatherApi = weatherapi.NewMockWeatherProvider()
+	} else {
+		mail = mailer.NewMailer(newMailTransport(ctx, eg, cfg))
+		if weatherApi, err = newWeatherProvider(cfg); err != nil {
+			return nil, fmt.Errorf("failed to configure weather provider: %w", err)
+		}
+	}
+
+	cacheCfg := cfg.WeatherCacheConfig()
+	weatherCache := weatherapi.NewCachedProvider(weatherApi, weatherapi.CacheOpts{
+		TTL:         cacheCfg.TTL,
+		NegativeTTL: cacheCfg.NegativeTTL,
+		MaxEntries:  cacheCfg.MaxEntries,
+	})
+
+	eg.Go(func() error {
+		logCacheStats(ctx, weatherCache, cacheCfg.StatsInterval, logger.WithField("component", "weather_cache"))
+		return nil
+	})
+
+	unsubscribeCfg := cfg.UnsubscribeConfig()
+
+	return &services{
+		mail:        mail,
+		weatherApi:  weatherCache,
+		unsubscribe: unsubscribe.NewLinks(unsubscribeCfg.SigningKey, unsubscribeCfg.BaseURL),
+	}, nil
+}
+
+// newMailTransport builds the configured transport, the pooled smtp connections are closed on shutdown
+func newMailTransport(ctx context.Context, eg *errgroup.Group, cfg *config.Config) mailer.Transport {
+	if cfg.MailConfig().Transport == config.MailTransportSMTP {
+		smtpCfg := cfg.SMTPConfig()
+		smtpClient := smtp.NewClient(
+			smtp.From{
+				Name:  smtpCfg.FromName,
+				Email: smtpCfg.FromEmail,
+			},
+			smtp.ClientOpts{
+				Host:        smtpCfg.Host,
+				Port:        smtpCfg.Port,
+				Security:    smtp.Security(smtpCfg.Security),
+				Username:    smtpCfg.Username,
+				Password:    smtpCfg.Password,
+				Auth:        smtp.AuthMechanism(smtpCfg.Auth),
+				PoolSize:    smtpCfg.PoolSize,
+				IdleTimeout: smtpCfg.IdleTimeout,
+				DialTimeout: smtpCfg.DialTimeout,
+				SendTimeout: smtpCfg.SendTimeout,
+			},
+		)
+		eg.Go(func() error {
+			<-ctx.Done()
+			return smtpClient.Close()
+		})
+
+		return mailer.NewSMTPTransport(smtpClient)
+	}
+
+	mailjetCfg := cfg.MailjetConfig()
+	return mailer.NewMailjetTransport(mailjet.NewClient(
+		mailjetCfg.ApiKey,
+		mailjetCfg.SecretKey,
+		mailjet.From{
+			Name:  mailjetCfg.FromName,
+			Email: mailjetCfg.FromEmail,
+		},
+	))
+}
+
+// runNotificator starts the notification scheduler, the alert evaluator, the dispatcher and the purger
+func runNotificator(ctx context.Context, eg *errgroup.Group, cfg *config.Config, svc *services, logger *logan.Entry) {
+	failuresCfg := cfg.SubscriptionFailuresConfig()
+	failurePolicy := notificator.FailurePolicy{
+		MaxFailures:     failuresCfg.MaxFailures,
+		RetryBackoff:    failuresCfg.RetryBackoff,
+		MaxRetryBackoff: failuresCfg.MaxRetryBackoff,
+	}
+
+	notificatorCfg := cfg.NotificatorConfig()
+	eg.Go(func() error {
+		notificator.New(
+			pg.NewDatabase(cfg.DB()),
+			svc.weatherApi,
+			svc.mail,
+			logger.WithField("component", "notificator"),
+			notificator.Opts{
+				Interval:           notificatorCfg.Interval,
+				BatchSize:          notificatorCfg.BatchSize,
+				Lease:              notificatorCfg.Lease,
+				FailurePolicy:      failurePolicy,
+				MergeSubscriptions: notificatorCfg.MergeSubscriptions,
+				Unsubscribe:        svc.unsubscribe,
+			},
+		).Run(ctx)
+
+		return nil
+	})
+
+	alertsCfg := cfg.AlertsConfig()
+	eg.Go(func() error {
+		notificator.NewAlertEvaluator(
+			pg.NewDatabase(cfg.DB()),
+			svc.weatherApi,
+			svc.mail,
+			logger.WithField("component", "alerts"),
+			notificator.AlertEvaluatorOpts{
+				Interval:      alertsCfg.Interval,
+				CheckInterval: alertsCfg.CheckInterval,
+				BatchSize:     alertsCfg.BatchSize,
+				Lease:         alertsCfg.Lease,
+				Unsubscribe:   svc.unsubscribe,
+			},
+		).Run(ctx)
+
+		return nil
+	})
+
+	dispatcherCfg := cfg.NotificationDispatcherConfig()
+	eg.Go(func() error {
+		notificator.NewDispatcher(
+			pg.NewDatabase(cfg.DB()),
+			svc.mail,
+			logger.WithField("component", "dispatcher"),
+			notificator.DispatcherOpts{
+				Interval:      dispatcherCfg.Interval,
+				BatchSize:     dispatcherCfg.BatchSize,
+				Lease:         dispatcherCfg.Lease,
+				MaxAttempts:   dispatcherCfg.MaxAttempts,
+				RetryBackoff:  dispatcherCfg.RetryBackoff,
+				FailurePolicy: failurePolicy,
+			},
+		).Run(ctx)
+
+		return nil
+	})
+
+	confirmationCfg := cfg.ConfirmationConfig()
+	eg.Go(func() error {
+		notificator.NewPurger(
+			pg.NewDatabase(cfg.DB()),
+			logger.WithField("component", "purger"),
+			notificator.PurgerOpts{
+				Interval:   confirmationCfg.PurgeInterval,
+				PurgeAfter: confirmationCfg.PurgeAfter,
+			},
+		).Run(ctx)
+
+		return nil
+	})
+}
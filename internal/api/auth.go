@@ -0,0 +1,37 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+const headerApiKey = "X-API-Key"
+
+// adminAuth requires one of the configured API keys, the admin routes are not exposed at all without them
+func adminAuth(apiKeys []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(apiKeys) == 0 {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			if !validApiKey(apiKeys, r.Header.Get(headerApiKey)) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// validApiKey compares the key with every configured one, so the time taken does not tell which one is matched
+func validApiKey(apiKeys []string, key string) bool {
+	matched := 0
+	for _, apiKey := range apiKeys {
+		matched |= subtle.ConstantTimeCompare([]byte(key), []byte(apiKey))
+	}
+
+	return key != "" && matched == 1
+}
@@ -3,9 +3,11 @@ package ctx
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/slbmax/ses-weather-app/internal/database"
 	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
 	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 	"gitlab.com/distributed_lab/logan/v3"
 )
@@ -17,8 +19,17 @@ const (
 	ctxKeyWeatherApi
 	ctxKeyDatabase
 	ctxKeyMailer
+	ctxKeyConfirmation
+	ctxKeyUnsubscribeLinks
 )
 
+// Confirmation is the policy of the subscription confirmation tokens
+type Confirmation struct {
+	TokenTTL time.Duration
+	// ResendInterval is the minimal time between the confirmation emails of the same subscription
+	ResendInterval time.Duration
+}
+
 func LoggerProvider(l *logan.Entry) func(context.Context) context.Context {
 	return func(ctx context.Context) context.Context {
 		return context.WithValue(ctx, ctxKeyLogger, l)
@@ -57,3 +68,24 @@ func MailerProvider(mailer mailer.Mailer) func(context.Context) context.Context
 func GetMailer(r *http.Request) mailer.Mailer {
 	return r.Context().Value(ctxKeyMailer).(mailer.Mailer)
 }
+
+func ConfirmationProvider(confirmation Confirmation) func(context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, ctxKeyConfirmation, confirmation)
+	}
+}
+
+func GetConfirmation(r *http.Request) Confirmation {
+	return r.Context().Value(ctxKeyConfirmation).(Confirmation)
+}
+
+func UnsubscribeLinksProvider(links *unsubscribe.Links) func(context.Context) context.Context {
+	return func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, ctxKeyUnsubscribeLinks, links)
+	}
+}
+
+// GetUnsubscribeLinks returns nil if the one-click unsubscribe links are not configured
+func GetUnsubscribeLinks(r *http.Request) *unsubscribe.Links {
+	return r.Context().Value(ctxKeyUnsubscribeLinks).(*unsubscribe.Links)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/api/ctx"
+	"github.com/slbmax/ses-weather-app/internal/api/requests"
+	"github.com/slbmax/ses-weather-app/internal/api/responses"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"gitlab.com/distributed_lab/ape"
+)
+
+// adminDeliveriesLimit is the number of the latest deliveries shown along with the subscription
+const adminDeliveriesLimit = 20
+
+// ErrSubscriptionInactive is returned if the subscription is not notified, as it is unconfirmed, suspended or paused
+var ErrSubscriptionInactive = errors.New("subscription is not active")
+
+// AdminListSuspended lists the subscriptions suspended after repeated notification failures
+func AdminListSuspended(w http.ResponseWriter, r *http.Request) {
+	subs, err := ctx.GetDatabase(r).SubscriptionsQ().SelectSuspended()
+	if err != nil {
+		ctx.GetLogger(r).WithError(err).Error("failed to select suspended subscriptions")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ape.Render(w, responses.NewAdminSubscriptionsResponse(subs))
+}
+
+// AdminListSubscriptions lists the page of the subscriptions matching the search and the status filters
+func AdminListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewAdminListSubscriptionsRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		logger         = ctx.GetLogger(r)
+		subscriptionsQ = ctx.GetDatabase(r).SubscriptionsQ()
+	)
+
+	subs, err := subscriptionsQ.Select(request.Filter, request.Limit, request.Offset)
+	if err != nil {
+		logger.WithError(err).Error("failed to select subscriptions")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	total, err := subscriptionsQ.Count(request.Filter)
+	if err != nil {
+		logger.WithError(err).Error("failed to count subscriptions")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ape.Render(w, responses.NewAdminSubscriptionsPageResponse(subs, total, request.Limit, request.Offset))
+}
+
+// AdminGetSubscription shows the subscription along with its latest deliveries
+func AdminGetSubscription(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewAdminSubscriptionRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		logger = ctx.GetLogger(r)
+		db     = ctx.GetDatabase(r)
+	)
+
+	sub, err := db.SubscriptionsQ().GetById(request.Id)
+	if err != nil {
+		logger.WithError(err).Error("failed to get subscription")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	} else if sub == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	notifications, err := db.NotificationsQ().SelectBySubscription(sub.Id, adminDeliveriesLimit)
+	if err != nil {
+		logger.WithError(err).Error("failed to select subscription notifications")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ape.Render(w, responses.NewAdminSubscriptionDetailsResponse(*sub, notifications))
+}
+
+// AdminConfirmSubscription confirms the subscription without its confirmation token, even the expired one.
+// The subscriber gets the confirmation success email with the unsubscribe token as usual.
+func AdminConfirmSubscription(w http.ResponseWriter, r *http.Request) {
+	adminUpdateSubscription(w, r, http.StatusNoContent, func(db database.Database, sub database.Subscription) error {
+		if sub.Confirmed {
+			return ErrSubscriptionConfirmed
+		}
+
+		return confirmSubscription(db, ctx.GetMailer(r), sub)
+	})
+}
+
+// AdminSuspendSubscription stops the notifications until the subscription is resumed
+func AdminSuspendSubscription(w http.ResponseWriter, r *http.Request) {
+	adminUpdateSubscription(w, r, http.StatusNoContent, func(db database.Database, sub database.Subscription) error {
+		if sub.SuspendedAt != nil {
+			// the original suspension time is kept
+			return nil
+		}
+
+		now := time.Now()
+		if err := db.SubscriptionsQ().UpdateSuspended(sub.Id, &now); err != nil {
+			return fmt.Errorf("failed to suspend subscription: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// AdminResumeSubscription lifts the suspension, the failures are forgotten, so the subscription is not suspended
+// again right after the next failure
+func AdminResumeSubscription(w http.ResponseWriter, r *http.Request) {
+	adminUpdateSubscription(w, r, http.StatusNoContent, func(db database.Database, sub database.Subscription) error {
+		if err := db.SubscriptionsQ().UpdateSuspended(sub.Id, nil); err != nil {
+			return fmt.Errorf("failed to resume subscription: %w", err)
+		}
+		if err := db.SubscriptionsQ().ResetFailures(sub.Id); err != nil {
+			return fmt.Errorf("failed to reset subscription failures: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// AdminDeleteSubscription deletes the subscription, the address is forgotten with its last subscription
+func AdminDeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	adminUpdateSubscription(w, r, http.StatusNoContent, func(db database.Database, sub database.Subscription) error {
+		if err := db.SubscriptionsQ().Delete(sub.Id); err != nil {
+			return fmt.Errorf("failed to delete subscription: %w", err)
+		}
+		if err := db.SubscribersQ().DeleteUnused(sub.SubscriberId); err != nil {
+			return fmt.Errorf("failed to delete subscriber: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// AdminNotifySubscription makes the active subscription due right away. The notification is rendered
+// and sent by the notificator on its next run, so 202 is returned.
+func AdminNotifySubscription(w http.ResponseWriter, r *http.Request) {
+	adminUpdateSubscription(w, r, http.StatusAccepted, func(db database.Database, sub database.Subscription) error {
+		if !sub.Confirmed || sub.SuspendedAt != nil || sub.PausedAt != nil {
+			return ErrSubscriptionInactive
+		}
+
+		if err := db.SubscriptionsQ().UpdateNotifyNow(sub.Id); err != nil {
+			return fmt.Errorf("failed to schedule notification: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// adminUpdateSubscription runs the action on the subscription of the request within one transaction,
+// the successful one is answered with the status given
+func adminUpdateSubscription(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	action func(db database.Database, sub database.Subscription) error,
+) {
+	request, err := requests.NewAdminSubscriptionRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	db := ctx.GetDatabase(r)
+	txErr := db.Transaction(func() error {
+		sub, err := db.SubscriptionsQ().GetById(request.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		} else if sub == nil {
+			return ErrSubscriptionNotFound
+		}
+
+		return action(db, *sub)
+	})
+
+	switch {
+	case txErr == nil:
+		w.WriteHeader(status)
+	case errors.Is(txErr, ErrSubscriptionNotFound),
+		errors.Is(txErr, database.ErrNoRowsAffected):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(txErr, ErrSubscriptionConfirmed),
+		errors.Is(txErr, ErrSubscriptionInactive):
+		w.WriteHeader(http.StatusConflict)
+	default:
+		ctx.GetLogger(r).WithError(txErr).Error("failed to execute transaction")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
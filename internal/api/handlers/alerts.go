@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/api/ctx"
+	"github.com/slbmax/ses-weather-app/internal/api/requests"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+// alertFrequency describes the alert rules in the confirmation emails
+const alertFrequency = "weather alert"
+
+// CreateAlert creates the unconfirmed alert rule, it is confirmed and deleted with
+// the same token endpoints as subscriptions (see Confirm and Unsubscribe). The repeated request
+// for the unconfirmed rule sends a new confirmation link, throttled the same way as Subscribe.
+func CreateAlert(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewCreateAlertRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		logger       = ctx.GetLogger(r)
+		db           = ctx.GetDatabase(r)
+		mail         = ctx.GetMailer(r)
+		confirmation = ctx.GetConfirmation(r)
+	)
+
+	txErr := db.Transaction(func() error {
+		location, err := ctx.GetWeatherClient(r).ResolveLocation(r.Context(), request.LocationQuery())
+		if err != nil {
+			return fmt.Errorf("failed to resolve location: %w", err)
+		}
+
+		now := time.Now()
+		rule := database.AlertRule{
+			Email:              request.Email,
+			City:               location.Name,
+			LocationId:         location.Id,
+			Region:             location.Region,
+			Country:            location.Country,
+			Latitude:           location.Latitude,
+			Longitude:          location.Longitude,
+			Timezone:           subscriptionTimezone("", location.Timezone),
+			Metric:             request.Metric,
+			Operator:           request.Operator,
+			Threshold:          *request.Threshold,
+			DayOffset:          request.DayOffset(),
+			Language:           request.Language,
+			ConfirmationSentAt: &now,
+			CreatedAt:          now,
+		}
+
+		// the unconfirmed rule is taken over by the repeated request, e.g. if the confirmation email is lost
+		existing, err := db.AlertRulesQ().GetByCondition(rule)
+		var token string
+		switch {
+		case err != nil:
+			return fmt.Errorf("failed to get existing alert rule: %w", err)
+		case existing == nil:
+			if rule.Id, err = db.AlertRulesQ().Insert(rule); err != nil {
+				return fmt.Errorf("failed to insert alert rule: %w", err)
+			}
+			token, err = issueAlertRuleToken(db, rule.Id, database.TokenPurposeConfirmation)
+		case existing.Confirmed:
+			return database.ErrAlertRuleExists
+		case resendThrottled(existing.ConfirmationSentAt, confirmation.ResendInterval, now):
+			return ErrConfirmationThrottled
+		default:
+			rule.Id = existing.Id
+			if err = db.AlertRulesQ().UpdateConfirmationSent(rule.Id, now); err != nil {
+				return fmt.Errorf("failed to update alert rule confirmation sending time: %w", err)
+			}
+			// the links sent before are replaced with the new one
+			token, err = reissueAlertRuleConfirmationToken(db, rule.Id)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err = mail.SendConfirmationEmail(rule.Email, mailer.ConfirmationEmail{
+			Language:  rule.Language,
+			Token:     token,
+			City:      rule.City,
+			Frequency: alertFrequency,
+		}); err != nil {
+			return fmt.Errorf("failed to send confirmation email: %w", err)
+		}
+
+		return nil
+	})
+
+	switch {
+	case txErr == nil:
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(txErr, database.ErrAlertRuleExists):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Is(txErr, ErrConfirmationThrottled):
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Is(txErr, weatherapi.ErrCityNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		logger.WithError(txErr).Error("failed to execute transaction")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// confirmAlertRule is the Confirm fallback for the tokens not matching any subscription,
+// the token expires after the ttl the same way as the subscription one
+func confirmAlertRule(db database.Database, mail mailer.Mailer, token string, ttl time.Duration) error {
+	rule, err := db.AlertRulesQ().GetByToken(token, database.TokenPurposeConfirmation)
+	if err != nil {
+		return fmt.Errorf("failed to get alert rule: %w", err)
+	} else if rule == nil {
+		return database.ErrNoRowsAffected
+	} else if rule.Confirmed {
+		return ErrSubscriptionConfirmed
+	} else if rule.ConfirmationExpired(ttl, time.Now()) {
+		return ErrConfirmationExpired
+	}
+
+	if err = db.AlertRulesQ().UpdateConfirmed(rule.Id); err != nil {
+		return fmt.Errorf("failed to confirm alert rule: %w", err)
+	}
+	unsubToken, err := issueAlertRuleToken(db, rule.Id, database.TokenPurposeUnsubscribe)
+	if err != nil {
+		return err
+	}
+
+	if err = mail.SendConfirmationSuccessEmail(rule.Email, mailer.ConfirmationSuccessEmail{
+		Language:  rule.Language,
+		Token:     unsubToken,
+		City:      rule.City,
+		Frequency: alertFrequency,
+	}); err != nil {
+		return fmt.Errorf("failed to send confirmation success email: %w", err)
+	}
+
+	return nil
+}
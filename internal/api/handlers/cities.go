@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/slbmax/ses-weather-app/internal/api/ctx"
+	"github.com/slbmax/ses-weather-app/internal/api/requests"
+	"github.com/slbmax/ses-weather-app/internal/api/responses"
+	"gitlab.com/distributed_lab/ape"
+)
+
+func SearchCities(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewCitiesSearchRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	locations, err := ctx.GetWeatherClient(r).SearchLocations(r.Context(), request.Query, request.Limit)
+	if err != nil {
+		ctx.GetLogger(r).WithError(err).Error("failed to search cities")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ape.Render(w, responses.NewCitiesSearchResponse(locations))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/slbmax/ses-weather-app/internal/api/ctx"
 	"github.com/slbmax/ses-weather-app/internal/api/requests"
@@ -14,6 +15,7 @@ import (
 
 var (
 	ErrSubscriptionConfirmed = errors.New("subscription already confirmed")
+	ErrConfirmationExpired   = errors.New("confirmation token expired")
 )
 
 func Confirm(w http.ResponseWriter, r *http.Request) {
@@ -30,26 +32,22 @@ func Confirm(w http.ResponseWriter, r *http.Request) {
 	)
 
 	txErr := db.Transaction(func() error {
-		subscription, err := db.SubscriptionsQ().GetByToken(request.Token)
+		subscription, err := db.SubscriptionsQ().GetByToken(request.Token, database.TokenPurposeConfirmation)
 		if err != nil {
 			return fmt.Errorf("failed to get subcription: %w", err)
 		} else if subscription == nil {
-			return sql.ErrNoRows
+			// the token flow is shared with the alert rules
+			return confirmAlertRule(db, mail, request.Token, ctx.GetConfirmation(r).TokenTTL)
 		} else if subscription.Confirmed {
+			// the confirmation token is kept after it is used, so the repeated confirmation is told apart from the unknown token
 			return ErrSubscriptionConfirmed
+		} else if subscription.ConfirmationExpired(ctx.GetConfirmation(r).TokenTTL, time.Now()) {
+			// a new token is requested with the resend endpoint or by subscribing again
+			return ErrConfirmationExpired
 		}
 
-		unsubToken := GenerateToken()
-		if err = db.SubscriptionsQ().UpdateConfirmed(subscription.Id, unsubToken); err != nil {
-			return fmt.Errorf("failed to confirm subscription: %w", err)
-		}
-
-		if err = mail.SendConfirmationSuccessEmail(subscription.Email, mailer.ConfirmationSuccessEmail{
-			Token:     unsubToken,
-			City:      subscription.City,
-			Frequency: string(subscription.Frequency),
-		}); err != nil {
-			return fmt.Errorf("failed to send confirmation success email: %w", err)
+		if err = confirmSubscription(db, mail, *subscription); err != nil {
+			return err
 		}
 
 		// additionally, the notification email can be sent immediately,
@@ -61,8 +59,9 @@ func Confirm(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case txErr == nil:
 		w.WriteHeader(http.StatusOK)
+	case errors.Is(txErr, ErrConfirmationExpired):
+		w.WriteHeader(http.StatusGone)
 	case errors.Is(txErr, ErrSubscriptionConfirmed):
-		// this token is supposed to be used as an unsubscribe token
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Is(txErr, database.ErrNoRowsAffected) ||
 		errors.Is(txErr, sql.ErrNoRows):
@@ -72,3 +71,29 @@ func Confirm(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 }
+
+// confirmSubscription confirms the subscription along with its subscriber and sends the unsubscribe token to the address
+func confirmSubscription(db database.Database, mail mailer.Mailer, subscription database.Subscription) error {
+	if err := db.SubscriptionsQ().UpdateConfirmed(subscription.Id); err != nil {
+		return fmt.Errorf("failed to confirm subscription: %w", err)
+	}
+	unsubToken, err := issueSubscriptionToken(db, subscription.Id, database.TokenPurposeUnsubscribe)
+	if err != nil {
+		return err
+	}
+	// the address is verified, so its unsubscribe tokens confirm its further subscriptions right away
+	if err = db.SubscribersQ().UpdateConfirmed(subscription.SubscriberId); err != nil {
+		return fmt.Errorf("failed to confirm subscriber: %w", err)
+	}
+
+	if err = mail.SendConfirmationSuccessEmail(subscription.Email, mailer.ConfirmationSuccessEmail{
+		Language:  subscription.Language,
+		Token:     unsubToken,
+		City:      subscription.City,
+		Frequency: string(subscription.Frequency),
+	}); err != nil {
+		return fmt.Errorf("failed to send confirmation success email: %w", err)
+	}
+
+	return nil
+}
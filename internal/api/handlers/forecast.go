@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/slbmax/ses-weather-app/internal/api/ctx"
+	"github.com/slbmax/ses-weather-app/internal/api/requests"
+	"github.com/slbmax/ses-weather-app/internal/api/responses"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	"gitlab.com/distributed_lab/ape"
+)
+
+func Forecast(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewForecastRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		log           = ctx.GetLogger(r)
+		weatherClient = ctx.GetWeatherClient(r)
+	)
+
+	forecast, err := weatherClient.GetForecast(localized(r), request.City, request.Days)
+	if err != nil {
+		if errors.Is(err, weatherapi.ErrCityNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+		} else {
+			log.WithError(err).Error("failed to get forecast data")
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+
+		return
+	}
+
+	ape.Render(w, responses.NewForecastResponse(*forecast))
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/api/ctx"
+	"github.com/slbmax/ses-weather-app/internal/api/requests"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+)
+
+// ResendConfirmation issues the new confirmation tokens for the unconfirmed subscriptions and alert rules of the email.
+// The ones whose confirmation was sent recently are skipped, and the response does not reveal
+// whether the email has any subscriptions.
+func ResendConfirmation(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewResendConfirmationRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		db           = ctx.GetDatabase(r)
+		mail         = ctx.GetMailer(r)
+		confirmation = ctx.GetConfirmation(r)
+		now          = time.Now()
+	)
+
+	txErr := db.Transaction(func() error {
+		if err := resendSubscriptionConfirmations(db, mail, request.Email, confirmation.ResendInterval, now); err != nil {
+			return err
+		}
+
+		return resendAlertRuleConfirmations(db, mail, request.Email, confirmation.ResendInterval, now)
+	})
+	if txErr != nil {
+		ctx.GetLogger(r).WithError(txErr).Error("failed to execute transaction")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func resendSubscriptionConfirmations(db database.Database, mail mailer.Mailer, email string, interval time.Duration, now time.Time) error {
+	subscriber, err := db.SubscribersQ().GetByEmail(email)
+	if err != nil {
+		return fmt.Errorf("failed to get subscriber: %w", err)
+	} else if subscriber == nil {
+		return nil
+	}
+
+	subscriptions, err := db.SubscriptionsQ().SelectUnconfirmed(subscriber.Id)
+	if err != nil {
+		return fmt.Errorf("failed to select unconfirmed subscriptions: %w", err)
+	}
+
+	for _, sub := range subscriptions {
+		if resendThrottled(sub.ConfirmationSentAt, interval, now) {
+			continue
+		}
+
+		token, err := reissueConfirmationToken(db, sub.Id)
+		if err != nil {
+			return err
+		}
+		if err = db.SubscriptionsQ().UpdateConfirmationSent(sub.Id, now); err != nil {
+			return fmt.Errorf("failed to update confirmation sending time: %w", err)
+		}
+
+		if err = mail.SendConfirmationEmail(sub.Email, mailer.ConfirmationEmail{
+			Language:  sub.Language,
+			Token:     token,
+			City:      sub.City,
+			Frequency: string(sub.Frequency),
+		}); err != nil {
+			return fmt.Errorf("failed to send confirmation email: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func resendAlertRuleConfirmations(db database.Database, mail mailer.Mailer, email string, interval time.Duration, now time.Time) error {
+	rules, err := db.AlertRulesQ().SelectUnconfirmed(email)
+	if err != nil {
+		return fmt.Errorf("failed to select unconfirmed alert rules: %w", err)
+	}
+
+	for _, rule := range rules {
+		if resendThrottled(rule.ConfirmationSentAt, interval, now) {
+			continue
+		}
+
+		token, err := reissueAlertRuleConfirmationToken(db, rule.Id)
+		if err != nil {
+			return err
+		}
+		if err = db.AlertRulesQ().UpdateConfirmationSent(rule.Id, now); err != nil {
+			return fmt.Errorf("failed to update alert rule confirmation sending time: %w", err)
+		}
+
+		if err = mail.SendConfirmationEmail(rule.Email, mailer.ConfirmationEmail{
+			Language:  rule.Language,
+			Token:     token,
+			City:      rule.City,
+			Frequency: alertFrequency,
+		}); err != nil {
+			return fmt.Errorf("failed to send confirmation email: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// resendThrottled reports whether the last confirmation was sent less than interval ago
+func resendThrottled(sentAt *time.Time, interval time.Duration, now time.Time) bool {
+	return sentAt != nil && now.Before(sentAt.Add(interval))
+}
@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"crypto/rand"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
@@ -12,11 +10,16 @@ import (
 	"github.com/slbmax/ses-weather-app/internal/api/requests"
 	"github.com/slbmax/ses-weather-app/internal/database"
 	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/pkg/schedule"
 	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 )
 
-const (
-	tokenLengthRaw = 16
+var (
+	// ErrConfirmationThrottled is returned if the confirmation of the subscription was sent too recently
+	ErrConfirmationThrottled = errors.New("confirmation was sent recently")
+	// ErrTokenNotOwned is returned if the token given to subscribe is not the unsubscribe token
+	// of a confirmed subscription of the same email
+	ErrTokenNotOwned = errors.New("token does not belong to the subscriber")
 )
 
 func Subscribe(w http.ResponseWriter, r *http.Request) {
@@ -27,33 +30,119 @@ func Subscribe(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var (
-		logger = ctx.GetLogger(r)
-		db     = ctx.GetDatabase(r)
-		mail   = ctx.GetMailer(r)
+		logger       = ctx.GetLogger(r)
+		db           = ctx.GetDatabase(r)
+		mail         = ctx.GetMailer(r)
+		confirmation = ctx.GetConfirmation(r)
 	)
 
 	txErr := db.Transaction(func() error {
+		// resolving ahead to avoid subscription without valid location
+		location, err := ctx.GetWeatherClient(r).ResolveLocation(r.Context(), request.LocationQuery())
+		if err != nil {
+			return fmt.Errorf("failed to resolve location: %w", err)
+		}
+
+		subscriber, err := db.SubscribersQ().GetOrInsert(request.Email)
+		if err != nil {
+			return fmt.Errorf("failed to get subscriber: %w", err)
+		}
+
+		// anyone may subscribe any address, so the location is confirmed right away only
+		// if the token of the address is given, the confirmation email is sent otherwise
+		var confirmed bool
+		if request.Token != "" {
+			if confirmed, err = ownsToken(db, subscriber.Id, request.Token); err != nil {
+				return err
+			} else if !confirmed {
+				return ErrTokenNotOwned
+			}
+		}
+
 		// writing data ahead to rollback in case of email sending failure
-		// also, reducing the number of queries to the database
 		sub := database.Subscription{
-			Email:     request.Email,
-			City:      request.City,
-			Token:     GenerateToken(),
-			Frequency: request.Frequency,
-			CreatedAt: time.Now(),
+			SubscriberId: subscriber.Id,
+			Email:        subscriber.Email,
+			City:         location.Name,
+			LocationId:   location.Id,
+			Region:       location.Region,
+			Country:      location.Country,
+			Latitude:     location.Latitude,
+			Longitude:    location.Longitude,
+			Timezone:     subscriptionTimezone(request.Timezone, location.Timezone),
+			DeliveryHour: database.DefaultDeliveryHour,
+			Frequency:    request.Frequency,
+			Language:     request.Language,
+			Units:        request.Units,
+			Confirmed:    confirmed,
+			CreatedAt:    time.Now(),
 		}
-		if sub.Id, err = db.SubscriptionsQ().Insert(sub); err != nil {
-			return fmt.Errorf("failed to insert subscription: %w", err)
+		if request.DeliveryHour != nil {
+			sub.DeliveryHour = *request.DeliveryHour
 		}
 
-		// validating if city exists to avoid subscription without valid one
-		_, err = ctx.GetWeatherClient(r).GetCurrentWeather(request.City)
+		sched, err := schedule.Parse(request.ScheduleExpr())
 		if err != nil {
-			return fmt.Errorf("failed to get weather data: %w", err)
+			return fmt.Errorf("failed to parse schedule: %w", err)
+		}
+		nextNotifyAt := sched.Next(time.Now().In(sub.Location()))
+		sub.Schedule, sub.NextNotifyAt = sched.String(), &nextNotifyAt
+
+		now := time.Now()
+		if !sub.Confirmed {
+			sub.ConfirmationSentAt = &now
+		}
+
+		// the unconfirmed subscription is taken over by the repeated request, e.g. if the confirmation email is lost
+		existing, err := db.SubscriptionsQ().GetByLocation(sub.SubscriberId, sub.City, sub.LocationId)
+		switch {
+		case err != nil:
+			return fmt.Errorf("failed to get existing subscription: %w", err)
+		case existing == nil:
+			if sub.Id, err = db.SubscriptionsQ().Insert(sub); err != nil {
+				return fmt.Errorf("failed to insert subscription: %w", err)
+			}
+		case existing.Confirmed:
+			return database.ErrSubscriptionExists
+		case !sub.Confirmed && resendThrottled(existing.ConfirmationSentAt, confirmation.ResendInterval, now):
+			return ErrConfirmationThrottled
+		default:
+			sub.Id, sub.CreatedAt = existing.Id, existing.CreatedAt
+			if err = db.SubscriptionsQ().Update(sub); err != nil {
+				return fmt.Errorf("failed to update subscription: %w", err)
+			}
+			// the links sent before are replaced with the new one
+			if err = db.TokensQ().DeleteBySubscription(sub.Id, database.TokenPurposeConfirmation); err != nil {
+				return fmt.Errorf("failed to revoke confirmation tokens: %w", err)
+			}
+		}
+
+		if sub.Confirmed {
+			token, err := issueSubscriptionToken(db, sub.Id, database.TokenPurposeUnsubscribe)
+			if err != nil {
+				return err
+			}
+
+			if err = mail.SendConfirmationSuccessEmail(sub.Email, mailer.ConfirmationSuccessEmail{
+				Language:  sub.Language,
+				Token:     token,
+				City:      sub.City,
+				Frequency: string(sub.Frequency),
+			}); err != nil {
+				return fmt.Errorf("failed to send confirmation success email: %w", err)
+			}
+
+			return nil
+		}
+
+		token, err := issueSubscriptionToken(db, sub.Id, database.TokenPurposeConfirmation)
+		if err != nil {
+			return err
 		}
 
 		if err = mail.SendConfirmationEmail(sub.Email, mailer.ConfirmationEmail{
-			Token:     sub.Token,
+			Language:  sub.Language,
+			Token:     token,
 			City:      sub.City,
 			Frequency: string(sub.Frequency),
 		}); err != nil {
@@ -68,6 +157,10 @@ func Subscribe(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	case errors.Is(txErr, database.ErrSubscriptionExists):
 		w.WriteHeader(http.StatusConflict)
+	case errors.Is(txErr, ErrConfirmationThrottled):
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Is(txErr, ErrTokenNotOwned):
+		w.WriteHeader(http.StatusForbidden)
 	case errors.Is(txErr, weatherapi.ErrCityNotFound):
 		w.WriteHeader(http.StatusNotFound)
 	default:
@@ -76,11 +169,25 @@ func Subscribe(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func GenerateToken() string {
-	b := make([]byte, tokenLengthRaw)
-	if _, err := rand.Read(b); err != nil {
-		panic("failed to generate token: " + err.Error())
+// ownsToken reports whether the token is the unsubscribe token of a confirmed subscription of the subscriber
+func ownsToken(db database.Database, subscriberId int64, token string) (bool, error) {
+	owner, err := db.SubscriptionsQ().GetByToken(token, database.TokenPurposeUnsubscribe)
+	if err != nil {
+		return false, fmt.Errorf("failed to get subscription by token: %w", err)
+	}
+
+	return owner != nil && owner.Confirmed && owner.SubscriberId == subscriberId, nil
+}
+
+// subscriptionTimezone prefers the requested timezone, the location one is used only if it is known
+// to the tz database, as the schedule is computed from it (empty timezone means UTC)
+func subscriptionTimezone(requested, location string) string {
+	if requested != "" {
+		return requested
+	}
+	if _, err := time.LoadLocation(location); err != nil {
+		return ""
 	}
 
-	return hex.EncodeToString(b)
+	return location
 }
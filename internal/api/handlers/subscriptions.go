@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/api/ctx"
+	"github.com/slbmax/ses-weather-app/internal/api/requests"
+	"github.com/slbmax/ses-weather-app/internal/api/responses"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/pkg/schedule"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	"gitlab.com/distributed_lab/ape"
+)
+
+var (
+	// ErrSubscriptionNotFound is returned for the unknown tokens and the confirmation ones,
+	// as only the confirmed subscription is managed by its token
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrInvalidSettings      = errors.New("invalid subscription settings")
+)
+
+func GetSubscription(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewSubscriptionTokenRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := ctx.GetDatabase(r).SubscriptionsQ().GetByToken(request.Token, database.TokenPurposeUnsubscribe)
+	switch {
+	case err != nil:
+		ctx.GetLogger(r).WithError(err).Error("failed to get subscription")
+		w.WriteHeader(http.StatusInternalServerError)
+	case subscription == nil || !subscription.Confirmed:
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		ape.Render(w, responses.NewSubscriptionResponse(*subscription))
+	}
+}
+
+// UpdateSubscription changes the settings of the confirmed subscription. The changed email
+// has to be confirmed again, so the subscription gets a new confirmation token sent to the new address.
+func UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewUpdateSubscriptionRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var (
+		logger  = ctx.GetLogger(r)
+		db      = ctx.GetDatabase(r)
+		mail    = ctx.GetMailer(r)
+		updated *database.Subscription
+	)
+
+	txErr := db.Transaction(func() error {
+		sub, err := db.SubscriptionsQ().GetByToken(request.Token, database.TokenPurposeUnsubscribe)
+		if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		} else if sub == nil || !sub.Confirmed {
+			return ErrSubscriptionNotFound
+		}
+
+		if request.LocationChanged() {
+			location, err := ctx.GetWeatherClient(r).ResolveLocation(r.Context(), request.LocationQuery())
+			if err != nil {
+				return fmt.Errorf("failed to resolve location: %w", err)
+			}
+
+			sub.City, sub.LocationId = location.Name, location.Id
+			sub.Region, sub.Country = location.Region, location.Country
+			sub.Latitude, sub.Longitude = location.Latitude, location.Longitude
+			sub.Timezone = subscriptionTimezone("", location.Timezone)
+		}
+		if request.Timezone != nil {
+			sub.Timezone = *request.Timezone
+		}
+		if request.Language != nil {
+			sub.Language = *request.Language
+		}
+		if request.Units != nil {
+			sub.Units = *request.Units
+		}
+
+		if err = updateSchedule(sub, request); err != nil {
+			return err
+		}
+
+		if request.Paused != nil && *request.Paused != (sub.PausedAt != nil) {
+			sub.PausedAt = nil
+			if *request.Paused {
+				now := time.Now()
+				sub.PausedAt = &now
+			}
+		}
+
+		previousSubscriberId := sub.SubscriberId
+		emailChanged := request.Email != nil && *request.Email != sub.Email
+		if emailChanged {
+			subscriber, err := db.SubscribersQ().GetOrInsert(*request.Email)
+			if err != nil {
+				return fmt.Errorf("failed to get subscriber: %w", err)
+			}
+
+			// the subscription is not notified until the new address is confirmed
+			sub.SubscriberId, sub.Email = subscriber.Id, subscriber.Email
+			now := time.Now()
+			sub.Confirmed, sub.ConfirmationSentAt = false, &now
+		}
+
+		if err = db.SubscriptionsQ().Update(*sub); err != nil {
+			return fmt.Errorf("failed to update subscription: %w", err)
+		}
+
+		if emailChanged {
+			if err = db.SubscribersQ().DeleteUnused(previousSubscriberId); err != nil {
+				return fmt.Errorf("failed to delete previous subscriber: %w", err)
+			}
+
+			// the links sent to the previous address stop working
+			if err = db.TokensQ().DeleteBySubscription(sub.Id, database.TokenPurposeUnsubscribe); err != nil {
+				return fmt.Errorf("failed to revoke unsubscribe tokens: %w", err)
+			}
+			token, err := reissueConfirmationToken(db, sub.Id)
+			if err != nil {
+				return err
+			}
+
+			if err = mail.SendConfirmationEmail(sub.Email, mailer.ConfirmationEmail{
+				Language:  sub.Language,
+				Token:     token,
+				City:      sub.City,
+				Frequency: string(sub.Frequency),
+			}); err != nil {
+				return fmt.Errorf("failed to send confirmation email: %w", err)
+			}
+		}
+
+		updated = sub
+		return nil
+	})
+
+	switch {
+	case txErr == nil:
+		ape.Render(w, responses.NewSubscriptionResponse(*updated))
+	case errors.Is(txErr, ErrInvalidSettings):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Is(txErr, ErrSubscriptionNotFound),
+		errors.Is(txErr, weatherapi.ErrCityNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(txErr, database.ErrSubscriptionExists):
+		w.WriteHeader(http.StatusConflict)
+	default:
+		logger.WithError(txErr).Error("failed to execute transaction")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// updateSchedule applies the frequency, delivery hour and schedule changes. The next notification
+// is rescheduled from now, so the paused notifications are not caught up after resuming.
+func updateSchedule(sub *database.Subscription, request *requests.UpdateSubscriptionRequest) error {
+	wasCustom := sub.Frequency == database.SubscriptionFrequencyCustom
+	if request.Frequency != nil {
+		sub.Frequency = *request.Frequency
+	}
+	if request.DeliveryHour != nil {
+		sub.DeliveryHour = *request.DeliveryHour
+	}
+
+	expr := sub.Frequency.Schedule(sub.DeliveryHour)
+	switch {
+	case sub.Frequency != database.SubscriptionFrequencyCustom && request.Schedule != nil:
+		return fmt.Errorf("%w: schedule is allowed only for the custom frequency", ErrInvalidSettings)
+	case sub.Frequency == database.SubscriptionFrequencyCustom && request.Schedule != nil:
+		expr = *request.Schedule
+	case sub.Frequency == database.SubscriptionFrequencyCustom && wasCustom:
+		expr = sub.Schedule
+	case sub.Frequency == database.SubscriptionFrequencyCustom:
+		return fmt.Errorf("%w: schedule is required for the custom frequency", ErrInvalidSettings)
+	}
+
+	sched, err := schedule.Parse(expr)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSettings, err)
+	}
+	nextNotifyAt := sched.Next(time.Now().In(sub.Location()))
+	sub.Schedule, sub.NextNotifyAt = sched.String(), &nextNotifyAt
+
+	return nil
+}
+
+func DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewSubscriptionTokenRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	db := ctx.GetDatabase(r)
+	err = db.Transaction(func() error {
+		subscription, err := db.SubscriptionsQ().GetByToken(request.Token, database.TokenPurposeUnsubscribe)
+		if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		} else if subscription == nil || !subscription.Confirmed {
+			return ErrSubscriptionNotFound
+		}
+
+		return deleteSubscription(db, request.Token, subscription.SubscriberId)
+	})
+
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrSubscriptionNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		ctx.GetLogger(r).WithError(err).Error("failed to delete subscription")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// deleteSubscription deletes the subscription of the unsubscribe token, the address is forgotten with its last subscription
+func deleteSubscription(db database.Database, token string, subscriberId int64) error {
+	if err := db.SubscriptionsQ().DeleteByToken(token); err != nil {
+		return fmt.Errorf("failed to delete subscription: %w", err)
+	}
+	if err := db.SubscribersQ().DeleteUnused(subscriberId); err != nil {
+		return fmt.Errorf("failed to delete subscriber: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+)
+
+// issueSubscriptionToken stores the new token of the subscription, returning the plaintext one to be sent
+func issueSubscriptionToken(db database.Database, subscriptionId int64, purpose database.TokenPurpose) (string, error) {
+	token, stored := database.NewSubscriptionToken(subscriptionId, purpose)
+	if err := db.TokensQ().Insert(stored); err != nil {
+		return "", fmt.Errorf("failed to insert %s token: %w", purpose, err)
+	}
+
+	return token, nil
+}
+
+// reissueConfirmationToken revokes the previously sent confirmation links of the subscription and issues the new one
+func reissueConfirmationToken(db database.Database, subscriptionId int64) (string, error) {
+	if err := db.TokensQ().DeleteBySubscription(subscriptionId, database.TokenPurposeConfirmation); err != nil {
+		return "", fmt.Errorf("failed to revoke confirmation tokens: %w", err)
+	}
+
+	return issueSubscriptionToken(db, subscriptionId, database.TokenPurposeConfirmation)
+}
+
+// issueAlertRuleToken stores the new token of the alert rule, returning the plaintext one to be sent
+func issueAlertRuleToken(db database.Database, alertRuleId int64, purpose database.TokenPurpose) (string, error) {
+	token, stored := database.NewAlertRuleToken(alertRuleId, purpose)
+	if err := db.TokensQ().Insert(stored); err != nil {
+		return "", fmt.Errorf("failed to insert %s token: %w", purpose, err)
+	}
+
+	return token, nil
+}
+
+// reissueAlertRuleConfirmationToken revokes the previously sent confirmation links of the alert rule and issues the new one
+func reissueAlertRuleConfirmationToken(db database.Database, alertRuleId int64) (string, error) {
+	if err := db.TokensQ().DeleteByAlertRule(alertRuleId, database.TokenPurposeConfirmation); err != nil {
+		return "", fmt.Errorf("failed to revoke confirmation tokens: %w", err)
+	}
+
+	return issueAlertRuleToken(db, alertRuleId, database.TokenPurposeConfirmation)
+}
@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/slbmax/ses-weather-app/internal/api/ctx"
 	"github.com/slbmax/ses-weather-app/internal/api/requests"
 	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
 )
 
 func Unsubscribe(w http.ResponseWriter, r *http.Request) {
@@ -16,17 +18,29 @@ func Unsubscribe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if unsubscribe.TokenRegex.MatchString(request.Token) {
+		unsubscribeSigned(w, r, request.Token)
+		return
+	}
+
 	var (
 		log = ctx.GetLogger(r)
 		db  = ctx.GetDatabase(r)
 	)
 
-	// as the specification states, we can delete the subscription by using
-	// - confirmation token at the unconfirmed state
-	// - unsubscribe token at the confirmed state
-	// (Unsubscribes an email from weather updates using the token sent in email__S__)
-	// also, no goodbye email is sent for simplicity
-	err = db.SubscriptionsQ().DeleteByToken(request.Token)
+	// only the unsubscribe tokens are accepted, the unconfirmed subscriptions are purged instead
+	// (see notificator.Purger), also, no goodbye email is sent for simplicity
+	err = db.Transaction(func() error {
+		subscription, err := db.SubscriptionsQ().GetByToken(request.Token, database.TokenPurposeUnsubscribe)
+		if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		} else if subscription == nil {
+			// the token flow is shared with the alert rules
+			return db.AlertRulesQ().DeleteByToken(request.Token)
+		}
+
+		return deleteSubscription(db, request.Token, subscription.SubscriberId)
+	})
 	switch {
 	case err == nil:
 		w.WriteHeader(http.StatusOK)
@@ -38,3 +52,87 @@ func Unsubscribe(w http.ResponseWriter, r *http.Request) {
 	}
 
 }
+
+var errUnsubscribeLinksDisabled = errors.New("one-click unsubscribe links are not configured")
+
+// OneClickUnsubscribe handles the RFC 8058 POST of the mail clients. The signed links are stateless,
+// so the target that is already deleted is treated as unsubscribed, as the mail clients may repeat the POST.
+// The same links opened with GET are handled by Unsubscribe.
+func OneClickUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewOneClickUnsubscribeRequest(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// the stored unsubscribe tokens of the email links are accepted as well
+	if !unsubscribe.TokenRegex.MatchString(request.Token) {
+		Unsubscribe(w, r)
+		return
+	}
+
+	unsubscribeSigned(w, r, request.Token)
+}
+
+// unsubscribeSigned unsubscribes the target of the signed List-Unsubscribe token
+func unsubscribeSigned(w http.ResponseWriter, r *http.Request, token string) {
+	var (
+		log   = ctx.GetLogger(r)
+		db    = ctx.GetDatabase(r)
+		links = ctx.GetUnsubscribeLinks(r)
+		err   error
+	)
+
+	if links == nil {
+		err = errUnsubscribeLinksDisabled
+	} else {
+		var target unsubscribe.Target
+		if target, err = links.Parse(token); err == nil {
+			err = db.Transaction(func() error {
+				return unsubscribeTarget(db, target)
+			})
+		}
+	}
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(err, unsubscribe.ErrInvalidToken), errors.Is(err, errUnsubscribeLinksDisabled):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		log.WithError(err).Error("failed to unsubscribe by signed link")
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func unsubscribeTarget(db database.Database, target unsubscribe.Target) error {
+	switch target.Kind {
+	case unsubscribe.KindAlertRule:
+		if err := db.AlertRulesQ().Delete(target.Id); err != nil && !errors.Is(err, database.ErrNoRowsAffected) {
+			return fmt.Errorf("failed to delete alert rule: %w", err)
+		}
+		return nil
+	case unsubscribe.KindSubscriber:
+		if _, err := db.SubscriptionsQ().DeleteBySubscriber(target.Id); err != nil {
+			return fmt.Errorf("failed to delete subscriptions: %w", err)
+		}
+		if err := db.SubscribersQ().DeleteUnused(target.Id); err != nil {
+			return fmt.Errorf("failed to delete subscriber: %w", err)
+		}
+		return nil
+	default:
+		subscription, err := db.SubscriptionsQ().GetById(target.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		} else if subscription == nil {
+			return nil
+		}
+
+		if err = db.SubscriptionsQ().Delete(target.Id); err != nil {
+			return fmt.Errorf("failed to delete subscription: %w", err)
+		}
+		if err = db.SubscribersQ().DeleteUnused(subscription.SubscriberId); err != nil {
+			return fmt.Errorf("failed to delete subscriber: %w", err)
+		}
+		return nil
+	}
+}
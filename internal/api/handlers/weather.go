@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
 	"github.com/slbmax/ses-weather-app/internal/api/ctx"
 	"github.com/slbmax/ses-weather-app/internal/api/requests"
 	"github.com/slbmax/ses-weather-app/internal/api/responses"
+	"github.com/slbmax/ses-weather-app/internal/i18n"
 	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 	"gitlab.com/distributed_lab/ape"
 )
@@ -23,7 +25,12 @@ func Weather(w http.ResponseWriter, r *http.Request) {
 		weatherClient = ctx.GetWeatherClient(r)
 	)
 
-	weather, err := weatherClient.GetCurrentWeather(request.City)
+	weatherCtx := localized(r)
+	if request.Includes(responses.WeatherFieldAirQuality) {
+		weatherCtx = weatherapi.WithAirQuality(weatherCtx)
+	}
+
+	weather, err := weatherClient.GetCurrentWeather(weatherCtx, request.City)
 	if err != nil {
 		if errors.Is(err, weatherapi.ErrCityNotFound) {
 			w.WriteHeader(http.StatusNotFound)
@@ -36,6 +43,12 @@ func Weather(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := responses.NewWeatherResponse(weather.CurrentWeather)
+	response := responses.NewWeatherResponse(weather.CurrentWeather, request.Units, request.Fields...)
 	ape.Render(w, response)
 }
+
+// localized requests the condition texts in the language of the Accept-Language header
+func localized(r *http.Request) context.Context {
+	lang := i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+	return weatherapi.WithLanguage(r.Context(), string(lang))
+}
@@ -0,0 +1,108 @@
+package requests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/internal/database"
+)
+
+const (
+	SubscriptionIdParam = "id"
+
+	queryParamSearch    = "search"
+	queryParamConfirmed = "confirmed"
+	queryParamSuspended = "suspended"
+	queryParamOffset    = "offset"
+
+	defaultAdminPageLimit = 20
+	maxAdminPageLimit     = 100
+)
+
+// AdminSubscriptionRequest addresses the subscription by its id, the admin is not given the tokens
+type AdminSubscriptionRequest struct {
+	Id int64
+}
+
+func (r *AdminSubscriptionRequest) Validate() error {
+	return validation.Validate(r.Id, validation.Required, validation.Min(int64(1)))
+}
+
+func NewAdminSubscriptionRequest(r *http.Request) (*AdminSubscriptionRequest, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, SubscriptionIdParam), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription id: %w", err)
+	}
+
+	request := &AdminSubscriptionRequest{Id: id}
+	if err = request.Validate(); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+// AdminListSubscriptionsRequest is the page of the subscriptions matching the filter, the newest first.
+// The limit is defaultAdminPageLimit unless given.
+type AdminListSubscriptionsRequest struct {
+	Filter database.SubscriptionsFilter
+	Limit  uint64
+	Offset uint64
+}
+
+func (r *AdminListSubscriptionsRequest) Validate() error {
+	return validation.Errors{
+		queryParamSearch: validation.Validate(r.Filter.Search, validation.Length(0, 100)),
+		queryParamLimit:  validation.Validate(r.Limit, validation.Required, validation.Max(uint64(maxAdminPageLimit))),
+	}.Filter()
+}
+
+func NewAdminListSubscriptionsRequest(r *http.Request) (*AdminListSubscriptionsRequest, error) {
+	var (
+		query   = r.URL.Query()
+		request = &AdminListSubscriptionsRequest{
+			Filter: database.SubscriptionsFilter{Search: query.Get(queryParamSearch)},
+			Limit:  defaultAdminPageLimit,
+		}
+		err error
+	)
+
+	for param, value := range map[string]**bool{
+		queryParamConfirmed: &request.Filter.Confirmed,
+		queryParamSuspended: &request.Filter.Suspended,
+	} {
+		if query.Has(param) {
+			if *value, err = parseBoolParam(query.Get(param)); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", param, err)
+			}
+		}
+	}
+	for param, value := range map[string]*uint64{
+		queryParamLimit:  &request.Limit,
+		queryParamOffset: &request.Offset,
+	} {
+		if query.Has(param) {
+			if *value, err = strconv.ParseUint(query.Get(param), 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", param, err)
+			}
+		}
+	}
+
+	if err = request.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	return request, nil
+}
+
+func parseBoolParam(value string) (*bool, error) {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
@@ -0,0 +1,150 @@
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+const (
+	formParamMetric    = "metric"
+	formParamOperator  = "operator"
+	formParamThreshold = "threshold"
+	formParamDay       = "day"
+
+	AlertDayToday    = "today"
+	AlertDayTomorrow = "tomorrow"
+)
+
+// CreateAlertRequest is a conditional alert, e.g. "rain_chance above 70 tomorrow",
+// the location is given the same way as in SubscribeRequest. Day is "today" unless given.
+// Language falls back to the Accept-Language header the same way as in SubscribeRequest.
+type CreateAlertRequest struct {
+	Email      string                 `json:"email"`
+	City       string                 `json:"city,omitempty"`
+	LocationId string                 `json:"location_id,omitempty"`
+	Latitude   *float64               `json:"lat,omitempty"`
+	Longitude  *float64               `json:"lon,omitempty"`
+	Metric     database.AlertMetric   `json:"metric"`
+	Operator   database.AlertOperator `json:"operator"`
+	Threshold  *float64               `json:"threshold"`
+	Day        string                 `json:"day,omitempty"`
+	Language   i18n.Language          `json:"language,omitempty"`
+}
+
+// LocationQuery maps the request into the provider location query, the request must be validated
+func (req *CreateAlertRequest) LocationQuery() weatherapi.LocationQuery {
+	return req.location().query()
+}
+
+// DayOffset returns the number of days from today, the request must be validated
+func (req *CreateAlertRequest) DayOffset() int {
+	if req.Day == AlertDayTomorrow {
+		return 1
+	}
+
+	return 0
+}
+
+func (req *CreateAlertRequest) location() locationParams {
+	return locationParams{
+		City:       req.City,
+		LocationId: req.LocationId,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+	}
+}
+
+func (req *CreateAlertRequest) Validate() error {
+	if req == nil {
+		return fmt.Errorf("request is nil")
+	}
+
+	errs := validation.Errors{
+		formParamEmail: validation.Validate(req.Email,
+			validation.Required,
+			validation.Match(RegexpEmail).Error("invalid email format"),
+		),
+		formParamMetric: validation.Validate(req.Metric,
+			validation.Required,
+			validation.By(func(value interface{}) error {
+				if m, ok := value.(database.AlertMetric); ok && m.Valid() {
+					return nil
+				}
+				return fmt.Errorf("invalid metric value: %v", value)
+			}),
+		),
+		formParamOperator: validation.Validate(req.Operator,
+			validation.Required,
+			validation.By(func(value interface{}) error {
+				if o, ok := value.(database.AlertOperator); ok && o.Valid() {
+					return nil
+				}
+				return fmt.Errorf("invalid operator value: %v", value)
+			}),
+		),
+		formParamThreshold: validation.Validate(req.Threshold,
+			validation.NotNil,
+			validation.When(req.Metric == database.AlertMetricRainChance, validation.Min(0.0), validation.Max(100.0)),
+			validation.When(req.Metric == database.AlertMetricMaxWind, validation.Min(0.0)),
+		),
+		formParamDay: validation.Validate(req.Day,
+			validation.In(AlertDayToday, AlertDayTomorrow),
+		),
+		formParamLanguage: validation.Validate(req.Language, validation.Required, validation.By(validateLanguage)),
+	}
+	for param, err := range req.location().rules() {
+		errs[param] = err
+	}
+
+	return errs.Filter()
+}
+
+func NewCreateAlertRequest(r *http.Request) (*CreateAlertRequest, error) {
+	var req *CreateAlertRequest
+
+	switch r.Header.Get("Content-Type") {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, fmt.Errorf("failed to parse form data: %w", err)
+		}
+		location, err := parseLocationForm(r)
+		if err != nil {
+			return nil, err
+		}
+		req = &CreateAlertRequest{
+			Email:      r.PostFormValue(formParamEmail),
+			City:       location.City,
+			LocationId: location.LocationId,
+			Latitude:   location.Latitude,
+			Longitude:  location.Longitude,
+			Metric:     database.AlertMetric(r.PostFormValue(formParamMetric)),
+			Operator:   database.AlertOperator(r.PostFormValue(formParamOperator)),
+			Day:        r.PostFormValue(formParamDay),
+			Language:   i18n.Language(r.PostFormValue(formParamLanguage)),
+		}
+		if req.Threshold, err = parseFormFloat(r, formParamThreshold); err != nil {
+			return nil, err
+		}
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, fmt.Errorf("failed to decode json body: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content type: %s", r.Header.Get("Content-Type"))
+	}
+	if req != nil && req.Language == "" {
+		req.Language = i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	return req, nil
+}
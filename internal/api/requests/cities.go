@@ -0,0 +1,59 @@
+package requests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+const (
+	queryParamQuery = "q"
+	queryParamLimit = "limit"
+
+	defaultCitiesSearchLimit = 5
+)
+
+type CitiesSearchRequest struct {
+	Query string
+	Limit int
+}
+
+func (r *CitiesSearchRequest) Validate() error {
+	return validation.Errors{
+		queryParamQuery: validation.Validate(r.Query,
+			validation.Required,
+			// providers do not return meaningful candidates for a single letter
+			validation.RuneLength(2, 100).Error("invalid search query"),
+		),
+		queryParamLimit: validation.Validate(r.Limit,
+			validation.Min(1),
+			validation.Max(weatherapi.MaxSearchResults),
+		),
+	}.Filter()
+}
+
+func NewCitiesSearchRequest(r *http.Request) (*CitiesSearchRequest, error) {
+	query := r.URL.Query()
+	req := &CitiesSearchRequest{
+		Query: strings.TrimSpace(query.Get(queryParamQuery)),
+		Limit: defaultCitiesSearchLimit,
+	}
+
+	if rawLimit := query.Get(queryParamLimit); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit value: %w", err)
+		}
+		req.Limit = limit
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	return req, nil
+}
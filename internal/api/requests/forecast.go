@@ -0,0 +1,56 @@
+package requests
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+const (
+	queryParamDays = "days"
+
+	defaultForecastDays = 3
+)
+
+type ForecastRequest struct {
+	City string
+	Days int
+}
+
+func (r *ForecastRequest) Validate() error {
+	return validation.Errors{
+		queryParamCity: validation.Validate(r.City,
+			validation.Required,
+			validation.Length(1, 100).Error("invalid city name"),
+		),
+		queryParamDays: validation.Validate(r.Days,
+			validation.Min(1),
+			validation.Max(weatherapi.MaxForecastDays),
+		),
+	}.Filter()
+}
+
+func NewForecastRequest(r *http.Request) (*ForecastRequest, error) {
+	query := r.URL.Query()
+	req := &ForecastRequest{
+		City: query.Get(queryParamCity),
+		Days: defaultForecastDays,
+	}
+
+	if rawDays := query.Get(queryParamDays); rawDays != "" {
+		days, err := strconv.Atoi(rawDays)
+		if err != nil {
+			return nil, fmt.Errorf("invalid days value: %w", err)
+		}
+		req.Days = days
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	return req, nil
+}
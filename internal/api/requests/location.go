@@ -0,0 +1,80 @@
+package requests
+
+import (
+	"fmt"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+// locationParams is a location given by exactly one of: city name, location id or coordinates,
+// shared by the subscription and alert requests
+type locationParams struct {
+	City       string
+	LocationId string
+	Latitude   *float64
+	Longitude  *float64
+}
+
+// query maps the params into the provider location query, the params must be validated
+func (p locationParams) query() weatherapi.LocationQuery {
+	query := weatherapi.LocationQuery{
+		Name: p.City,
+		Id:   p.LocationId,
+	}
+	if p.Latitude != nil && p.Longitude != nil {
+		query.Coordinates = &weatherapi.Coordinates{
+			Latitude:  *p.Latitude,
+			Longitude: *p.Longitude,
+		}
+	}
+
+	return query
+}
+
+// rules returns the validation errors keyed by the params names
+func (p locationParams) rules() validation.Errors {
+	var given int
+	for _, isSet := range []bool{p.City != "", p.LocationId != "", p.Latitude != nil || p.Longitude != nil} {
+		if isSet {
+			given++
+		}
+	}
+	var locationErr error
+	if given != 1 {
+		locationErr = fmt.Errorf("exactly one of city, location_id or lat/lon must be provided")
+	}
+
+	return validation.Errors{
+		"location": locationErr,
+		formParamCity: validation.Validate(p.City,
+			validation.Length(1, 100).Error("invalid city name"),
+		),
+		formParamLocationId: validation.Validate(p.LocationId,
+			validation.Length(1, 64).Error("invalid location id"),
+		),
+		formParamLatitude: validation.Validate(p.Latitude,
+			validation.When(p.Longitude != nil, validation.NotNil),
+			validation.Min(-90.0), validation.Max(90.0),
+		),
+		formParamLongitude: validation.Validate(p.Longitude,
+			validation.When(p.Latitude != nil, validation.NotNil),
+			validation.Min(-180.0), validation.Max(180.0),
+		),
+	}
+}
+
+// parseLocationForm reads the location params of the parsed form
+func parseLocationForm(r *http.Request) (params locationParams, err error) {
+	params.City = r.PostFormValue(formParamCity)
+	params.LocationId = r.PostFormValue(formParamLocationId)
+	if params.Latitude, err = parseFormFloat(r, formParamLatitude); err != nil {
+		return params, err
+	}
+	if params.Longitude, err = parseFormFloat(r, formParamLongitude); err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
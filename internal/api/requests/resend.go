@@ -0,0 +1,51 @@
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ResendConfirmationRequest asks for the new confirmation tokens of all the unconfirmed subscriptions of the email
+type ResendConfirmationRequest struct {
+	Email string `json:"email"`
+}
+
+func (req *ResendConfirmationRequest) Validate() error {
+	if req == nil {
+		return fmt.Errorf("request is nil")
+	}
+
+	return validation.Errors{
+		formParamEmail: validation.Validate(req.Email,
+			validation.Required,
+			validation.Match(RegexpEmail).Error("invalid email format"),
+		),
+	}.Filter()
+}
+
+func NewResendConfirmationRequest(r *http.Request) (*ResendConfirmationRequest, error) {
+	var req *ResendConfirmationRequest
+
+	switch r.Header.Get("Content-Type") {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, fmt.Errorf("failed to parse form data: %w", err)
+		}
+		req = &ResendConfirmationRequest{Email: r.PostFormValue(formParamEmail)}
+	case "application/json":
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, fmt.Errorf("failed to decode json body: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content type: %s", r.Header.Get("Content-Type"))
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	return req, nil
+}
@@ -5,25 +5,82 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/pkg/schedule"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 )
 
 const (
-	formParamEmail     = "email"
-	formParamCity      = "city"
-	formParamFrequency = "frequency"
+	formParamEmail      = "email"
+	formParamCity       = "city"
+	formParamLocationId = "location_id"
+	formParamLatitude   = "lat"
+	formParamLongitude  = "lon"
+	formParamFrequency  = "frequency"
+	formParamDelivery   = "delivery_hour"
+	formParamTimezone   = "timezone"
+	formParamSchedule   = "schedule"
+	formParamLanguage   = "language"
+	formParamUnits      = "units"
+	formParamToken      = "token"
 )
 
 var (
 	RegexpEmail = regexp.MustCompile("^\\S+@\\S+\\.\\S+$") // basic one, without overkill
 )
 
+// SubscribeRequest identifies the location by exactly one of: city name, location id or coordinates.
+// DeliveryHour is the local hour of the daily and weekly notifications, Schedule is the cron expression
+// of the custom frequency (see schedule.Parse). Timezone overrides the one of the resolved location.
+// Language of the emails falls back to the Accept-Language header (see i18n.FromAcceptLanguage),
+// Units of the emails are metric unless given. Token is the unsubscribe token of any confirmed subscription
+// of the same email, it proves the address is owned, so the new location is confirmed without the confirmation email.
 type SubscribeRequest struct {
-	Email     string                         `json:"email"`
-	City      string                         `json:"city"`
-	Frequency database.SubscriptionFrequency `json:"frequency"`
+	Email        string                         `json:"email"`
+	City         string                         `json:"city,omitempty"`
+	LocationId   string                         `json:"location_id,omitempty"`
+	Latitude     *float64                       `json:"lat,omitempty"`
+	Longitude    *float64                       `json:"lon,omitempty"`
+	Frequency    database.SubscriptionFrequency `json:"frequency"`
+	DeliveryHour *int                           `json:"delivery_hour,omitempty"`
+	Timezone     string                         `json:"timezone,omitempty"`
+	Schedule     string                         `json:"schedule,omitempty"`
+	Language     i18n.Language                  `json:"language,omitempty"`
+	Units        weatherapi.Units               `json:"units,omitempty"`
+	Token        string                         `json:"token,omitempty"`
+}
+
+// ScheduleExpr returns the cron schedule of the subscription, the request must be validated
+func (req *SubscribeRequest) ScheduleExpr() string {
+	if req.Frequency == database.SubscriptionFrequencyCustom {
+		return req.Schedule
+	}
+
+	deliveryHour := database.DefaultDeliveryHour
+	if req.DeliveryHour != nil {
+		deliveryHour = *req.DeliveryHour
+	}
+
+	return req.Frequency.Schedule(deliveryHour)
+}
+
+// LocationQuery maps the request into the provider location query, the request must be validated
+func (req *SubscribeRequest) LocationQuery() weatherapi.LocationQuery {
+	return req.location().query()
+}
+
+func (req *SubscribeRequest) location() locationParams {
+	return locationParams{
+		City:       req.City,
+		LocationId: req.LocationId,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+	}
 }
 
 func (req *SubscribeRequest) Validate() error {
@@ -31,15 +88,11 @@ func (req *SubscribeRequest) Validate() error {
 		return fmt.Errorf("request is nil")
 	}
 
-	return validation.Errors{
+	errs := validation.Errors{
 		formParamEmail: validation.Validate(req.Email,
 			validation.Required,
 			validation.Match(RegexpEmail).Error("invalid email format"),
 		),
-		formParamCity: validation.Validate(req.City,
-			validation.Required,
-			validation.Length(1, 100).Error("invalid city name"),
-		),
 		formParamFrequency: validation.Validate(req.Frequency,
 			validation.Required,
 			validation.By(func(value interface{}) error {
@@ -49,7 +102,42 @@ func (req *SubscribeRequest) Validate() error {
 				return fmt.Errorf("invalid frequency value: %v", value)
 			}),
 		),
-	}.Filter()
+		formParamDelivery: validation.Validate(req.DeliveryHour,
+			validation.Min(0), validation.Max(23),
+		),
+		formParamSchedule: validation.Validate(req.Schedule,
+			validation.When(req.Frequency == database.SubscriptionFrequencyCustom,
+				validation.Required,
+				validation.Length(1, 100),
+				validation.By(func(value interface{}) error {
+					sched, err := schedule.Parse(value.(string))
+					if err != nil {
+						return err
+					} else if sched.MaxPerHour() > 1 {
+						return fmt.Errorf("schedule must fire at most once an hour")
+					}
+					return nil
+				}),
+			).Else(validation.Empty),
+		),
+		formParamLanguage: validation.Validate(req.Language, validation.Required, validation.By(validateLanguage)),
+		formParamUnits:    validation.Validate(req.Units, validation.By(validateUnits)),
+		formParamTimezone: validation.Validate(req.Timezone,
+			validation.By(func(value interface{}) error {
+				if tz, _ := value.(string); tz != "" {
+					if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+						return fmt.Errorf("invalid timezone: %s", tz)
+					}
+				}
+				return nil
+			}),
+		),
+	}
+	for param, err := range req.location().rules() {
+		errs[param] = err
+	}
+
+	return errs.Filter()
 }
 
 func NewSubscribeRequest(r *http.Request) (*SubscribeRequest, error) {
@@ -61,10 +149,25 @@ func NewSubscribeRequest(r *http.Request) (*SubscribeRequest, error) {
 		if err := r.ParseForm(); err != nil {
 			return nil, fmt.Errorf("failed to parse form data: %w", err)
 		}
+		location, err := parseLocationForm(r)
+		if err != nil {
+			return nil, err
+		}
 		req = &SubscribeRequest{
-			Email:     r.PostFormValue(formParamEmail),
-			City:      r.PostFormValue(formParamCity),
-			Frequency: database.SubscriptionFrequency(r.PostFormValue(formParamFrequency)),
+			Email:      r.PostFormValue(formParamEmail),
+			City:       location.City,
+			LocationId: location.LocationId,
+			Latitude:   location.Latitude,
+			Longitude:  location.Longitude,
+			Frequency:  database.SubscriptionFrequency(r.PostFormValue(formParamFrequency)),
+			Timezone:   r.PostFormValue(formParamTimezone),
+			Schedule:   r.PostFormValue(formParamSchedule),
+			Language:   i18n.Language(r.PostFormValue(formParamLanguage)),
+			Units:      weatherapi.Units(r.PostFormValue(formParamUnits)),
+			Token:      r.PostFormValue(formParamToken),
+		}
+		if req.DeliveryHour, err = parseFormInt(r, formParamDelivery); err != nil {
+			return nil, err
 		}
 	case "application/json":
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -73,10 +176,82 @@ func NewSubscribeRequest(r *http.Request) (*SubscribeRequest, error) {
 	default:
 		return nil, fmt.Errorf("unsupported content type: %s", r.Header.Get("Content-Type"))
 	}
+	if req != nil && req.Language == "" {
+		req.Language = i18n.FromAcceptLanguage(r.Header.Get("Accept-Language"))
+	}
 
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("failed to validate request: %w", err)
 	}
+	req.Units = req.Units.OrDefault()
 
 	return req, nil
 }
+
+func validateUnits(value interface{}) error {
+	var units weatherapi.Units
+	switch v := value.(type) {
+	case weatherapi.Units:
+		units = v
+	case *weatherapi.Units:
+		if v == nil {
+			return nil
+		}
+		units = *v
+	}
+
+	if units != "" && !units.Valid() {
+		return fmt.Errorf("unsupported units: %s", units)
+	}
+
+	return nil
+}
+
+func validateLanguage(value interface{}) error {
+	var lang i18n.Language
+	switch v := value.(type) {
+	case i18n.Language:
+		lang = v
+	case *i18n.Language:
+		if v == nil {
+			return nil
+		}
+		lang = *v
+	}
+
+	if lang != "" && !lang.Valid() {
+		return fmt.Errorf("unsupported language: %s", lang)
+	}
+
+	return nil
+}
+
+// parseFormFloat returns nil for the missing param
+func parseFormFloat(r *http.Request, param string) (*float64, error) {
+	raw := r.PostFormValue(param)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %w", param, err)
+	}
+
+	return &value, nil
+}
+
+// parseFormInt returns nil for the missing param
+func parseFormInt(r *http.Request, param string) (*int, error) {
+	raw := r.PostFormValue(param)
+	if raw == "" {
+		return nil, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %w", param, err)
+	}
+
+	return &value, nil
+}
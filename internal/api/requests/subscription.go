@@ -0,0 +1,154 @@
+package requests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/pkg/schedule"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+// UpdateSubscriptionRequest changes only the given settings of the subscription managed by its token.
+// The location is changed if any of its params is given, the same way as in SubscribeRequest.
+// The schedule is allowed only if the resulting frequency is custom (see database.Subscription.Schedule).
+type UpdateSubscriptionRequest struct {
+	Token        string                          `json:"-"`
+	Email        *string                         `json:"email,omitempty"`
+	City         string                          `json:"city,omitempty"`
+	LocationId   string                          `json:"location_id,omitempty"`
+	Latitude     *float64                        `json:"lat,omitempty"`
+	Longitude    *float64                        `json:"lon,omitempty"`
+	Frequency    *database.SubscriptionFrequency `json:"frequency,omitempty"`
+	DeliveryHour *int                            `json:"delivery_hour,omitempty"`
+	Timezone     *string                         `json:"timezone,omitempty"`
+	Schedule     *string                         `json:"schedule,omitempty"`
+	Paused       *bool                           `json:"paused,omitempty"`
+	Language     *i18n.Language                  `json:"language,omitempty"`
+	Units        *weatherapi.Units               `json:"units,omitempty"`
+}
+
+// LocationChanged reports whether the new location is requested
+func (req *UpdateSubscriptionRequest) LocationChanged() bool {
+	return req.City != "" || req.LocationId != "" || req.Latitude != nil || req.Longitude != nil
+}
+
+// LocationQuery maps the request into the provider location query, the location must be changed
+func (req *UpdateSubscriptionRequest) LocationQuery() weatherapi.LocationQuery {
+	return req.location().query()
+}
+
+func (req *UpdateSubscriptionRequest) location() locationParams {
+	return locationParams{
+		City:       req.City,
+		LocationId: req.LocationId,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+	}
+}
+
+func (req *UpdateSubscriptionRequest) Validate() error {
+	if req == nil {
+		return fmt.Errorf("request is nil")
+	}
+
+	errs := validation.Errors{
+		TokenParam: validation.Validate(req.Token, validation.Required, validation.Match(tokenRegex)),
+		formParamEmail: validation.Validate(req.Email,
+			validation.NilOrNotEmpty,
+			validation.Match(RegexpEmail).Error("invalid email format"),
+		),
+		formParamFrequency: validation.Validate(req.Frequency,
+			validation.By(func(value interface{}) error {
+				if f, _ := value.(*database.SubscriptionFrequency); f != nil && !f.Valid() {
+					return fmt.Errorf("invalid frequency value: %v", *f)
+				}
+				return nil
+			}),
+		),
+		formParamDelivery: validation.Validate(req.DeliveryHour,
+			validation.Min(0), validation.Max(23),
+		),
+		formParamSchedule: validation.Validate(req.Schedule,
+			validation.NilOrNotEmpty,
+			validation.Length(1, 100),
+			validation.By(func(value interface{}) error {
+				expr, _ := value.(*string)
+				if expr == nil {
+					return nil
+				}
+				sched, err := schedule.Parse(*expr)
+				if err != nil {
+					return err
+				} else if sched.MaxPerHour() > 1 {
+					return fmt.Errorf("schedule must fire at most once an hour")
+				}
+				return nil
+			}),
+		),
+		formParamLanguage: validation.Validate(req.Language, validation.NilOrNotEmpty, validation.By(validateLanguage)),
+		formParamUnits:    validation.Validate(req.Units, validation.NilOrNotEmpty, validation.By(validateUnits)),
+		formParamTimezone: validation.Validate(req.Timezone,
+			validation.NilOrNotEmpty,
+			validation.By(func(value interface{}) error {
+				if tz, _ := value.(*string); tz != nil {
+					if _, err := time.LoadLocation(*tz); err != nil || *tz == "Local" {
+						return fmt.Errorf("invalid timezone: %s", *tz)
+					}
+				}
+				return nil
+			}),
+		),
+	}
+	if req.LocationChanged() {
+		for param, err := range req.location().rules() {
+			errs[param] = err
+		}
+	}
+
+	return errs.Filter()
+}
+
+func NewUpdateSubscriptionRequest(r *http.Request) (*UpdateSubscriptionRequest, error) {
+	if r.Header.Get("Content-Type") != "application/json" {
+		return nil, fmt.Errorf("unsupported content type: %s", r.Header.Get("Content-Type"))
+	}
+
+	var req *UpdateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to decode json body: %w", err)
+	}
+	if req == nil {
+		return nil, fmt.Errorf("empty json body")
+	}
+	req.Token = chi.URLParam(r, TokenParam)
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("failed to validate request: %w", err)
+	}
+
+	return req, nil
+}
+
+// SubscriptionTokenRequest identifies the subscription managed by its (unsubscribe) token
+type SubscriptionTokenRequest struct {
+	Token string
+}
+
+func (c *SubscriptionTokenRequest) Validate() error {
+	return validation.Validate(c.Token, validation.Required, validation.Match(tokenRegex))
+}
+
+func NewSubscriptionTokenRequest(r *http.Request) (*SubscriptionTokenRequest, error) {
+	request := &SubscriptionTokenRequest{Token: chi.URLParam(r, TokenParam)}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
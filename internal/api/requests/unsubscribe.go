@@ -5,14 +5,20 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
 )
 
+// oneClickValue is the List-Unsubscribe form value of the one-click POST (see unsubscribe.OneClickBody)
+const oneClickValue = "One-Click"
+
+// UnsubscribeRequest is the unsubscribe link opened in the browser, the token is either the stored unsubscribe one
+// or the signed one of the List-Unsubscribe header, opened with GET by the mail clients without RFC 8058 support
 type UnsubscribeRequest struct {
 	Token string
 }
 
 func (c *UnsubscribeRequest) Validate() error {
-	return validation.Validate(c.Token, validation.Required, validation.Match(tokenRegex))
+	return validateUnsubscribeToken(c.Token)
 }
 
 func NewUnsubscribeRequest(r *http.Request) (*UnsubscribeRequest, error) {
@@ -23,3 +29,36 @@ func NewUnsubscribeRequest(r *http.Request) (*UnsubscribeRequest, error) {
 
 	return request, nil
 }
+
+// OneClickUnsubscribeRequest is the RFC 8058 one-click unsubscribe POST, the token is either
+// the signed one of the List-Unsubscribe header or the stored unsubscribe one
+type OneClickUnsubscribeRequest struct {
+	Token string
+	// OneClick is the form value, it must be "One-Click"
+	OneClick string
+}
+
+func (c *OneClickUnsubscribeRequest) Validate() error {
+	return validation.Errors{
+		"token":            validateUnsubscribeToken(c.Token),
+		"List-Unsubscribe": validation.Validate(c.OneClick, validation.Required, validation.In(oneClickValue)),
+	}.Filter()
+}
+
+func NewOneClickUnsubscribeRequest(r *http.Request) (*OneClickUnsubscribeRequest, error) {
+	// both the urlencoded and multipart bodies are allowed by the RFC
+	request := &OneClickUnsubscribeRequest{
+		Token:    chi.URLParam(r, TokenParam),
+		OneClick: r.PostFormValue(unsubscribe.HeaderListUnsubscribe),
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func validateUnsubscribeToken(token string) error {
+	return validation.Validate(token, validation.Required,
+		validation.When(!tokenRegex.MatchString(token), validation.Match(unsubscribe.TokenRegex)))
+}
@@ -3,26 +3,63 @@ package requests
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/slbmax/ses-weather-app/internal/api/responses"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 )
 
-const queryParamCity = "city"
+const (
+	queryParamCity   = "city"
+	queryParamUnits  = "units"
+	queryParamFields = "fields"
+)
 
+// WeatherRequest is the current weather of the city, Units are metric unless given.
+// Fields select the extended response fields (see responses.WeatherFields), given comma-separated.
 type WeatherRequest struct {
-	City string
+	City   string
+	Units  weatherapi.Units
+	Fields []string
+}
+
+// Includes reports whether the extended field is selected
+func (r *WeatherRequest) Includes(field string) bool {
+	return slices.Contains(r.Fields, field)
 }
 
 func (r *WeatherRequest) Validate() error {
-	return validation.Validate(r.City, validation.Required, validation.Length(1, 100).Error("invalid city name"))
+	return validation.Errors{
+		queryParamCity: validation.Validate(r.City, validation.Required, validation.Length(1, 100).Error("invalid city name")),
+		queryParamUnits: validation.Validate(r.Units,
+			validation.In(weatherapi.UnitsMetric, weatherapi.UnitsImperial).Error("invalid units"),
+		),
+		queryParamFields: validation.Validate(r.Fields, validation.Each(
+			validation.By(func(value interface{}) error {
+				if field, _ := value.(string); !slices.Contains(responses.WeatherFields, field) {
+					return fmt.Errorf("unknown field: %v", value)
+				}
+				return nil
+			}),
+		)),
+	}.Filter()
 }
 
 func NewWeatherRequest(r *http.Request) (*WeatherRequest, error) {
 	query := r.URL.Query()
-	req := &WeatherRequest{query.Get(queryParamCity)}
+	req := &WeatherRequest{
+		City:  query.Get(queryParamCity),
+		Units: weatherapi.Units(query.Get(queryParamUnits)),
+	}
+	if fields := query.Get(queryParamFields); fields != "" {
+		req.Fields = strings.Split(fields, ",")
+	}
 	if err := req.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
+	req.Units = req.Units.OrDefault()
 
 	return req, nil
 }
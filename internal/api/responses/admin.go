@@ -0,0 +1,115 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+)
+
+type AdminSubscriptionResponse struct {
+	Id                  int64      `json:"id"`
+	Email               string     `json:"email"`
+	City                string     `json:"city"`
+	Frequency           string     `json:"frequency"`
+	Schedule            string     `json:"schedule"`
+	Confirmed           bool       `json:"confirmed"`
+	CreatedAt           time.Time  `json:"created_at"`
+	LastNotifiedAt      *time.Time `json:"last_notified_at"`
+	NextNotifyAt        *time.Time `json:"next_notify_at"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	NextRetryAt         *time.Time `json:"next_retry_at"`
+	LastError           *string    `json:"last_error"`
+	SuspendedAt         *time.Time `json:"suspended_at"`
+	PausedAt            *time.Time `json:"paused_at"`
+}
+
+type AdminSubscriptionsResponse struct {
+	Subscriptions []AdminSubscriptionResponse `json:"subscriptions"`
+}
+
+// AdminSubscriptionsPageResponse is the page of the listed subscriptions, Total counts all the matching ones
+type AdminSubscriptionsPageResponse struct {
+	AdminSubscriptionsResponse
+	Total  int64  `json:"total"`
+	Limit  uint64 `json:"limit"`
+	Offset uint64 `json:"offset"`
+}
+
+// AdminSubscriptionDetailsResponse is the subscription along with its latest deliveries, the newest first
+type AdminSubscriptionDetailsResponse struct {
+	Subscription AdminSubscriptionResponse `json:"subscription"`
+	Deliveries   []AdminDeliveryResponse   `json:"deliveries"`
+}
+
+type AdminDeliveryResponse struct {
+	Id             int64      `json:"id"`
+	IdempotencyKey string     `json:"idempotency_key"`
+	Subject        string     `json:"subject"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      *string    `json:"last_error"`
+	CreatedAt      time.Time  `json:"created_at"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	SentAt         *time.Time `json:"sent_at"`
+}
+
+func NewAdminSubscriptionResponse(sub database.Subscription) AdminSubscriptionResponse {
+	return AdminSubscriptionResponse{
+		Id:                  sub.Id,
+		Email:               sub.Email,
+		City:                sub.City,
+		Frequency:           string(sub.Frequency),
+		Schedule:            sub.Schedule,
+		Confirmed:           sub.Confirmed,
+		CreatedAt:           sub.CreatedAt,
+		LastNotifiedAt:      sub.LastNotifiedAt,
+		NextNotifyAt:        sub.NextNotifyAt,
+		ConsecutiveFailures: sub.ConsecutiveFailures,
+		NextRetryAt:         sub.NextRetryAt,
+		LastError:           sub.LastError,
+		SuspendedAt:         sub.SuspendedAt,
+		PausedAt:            sub.PausedAt,
+	}
+}
+
+func NewAdminSubscriptionsResponse(subs []database.Subscription) AdminSubscriptionsResponse {
+	response := AdminSubscriptionsResponse{
+		Subscriptions: make([]AdminSubscriptionResponse, len(subs)),
+	}
+	for i, sub := range subs {
+		response.Subscriptions[i] = NewAdminSubscriptionResponse(sub)
+	}
+
+	return response
+}
+
+func NewAdminSubscriptionsPageResponse(subs []database.Subscription, total int64, limit, offset uint64) AdminSubscriptionsPageResponse {
+	return AdminSubscriptionsPageResponse{
+		AdminSubscriptionsResponse: NewAdminSubscriptionsResponse(subs),
+		Total:                      total,
+		Limit:                      limit,
+		Offset:                     offset,
+	}
+}
+
+func NewAdminSubscriptionDetailsResponse(sub database.Subscription, notifications []database.Notification) AdminSubscriptionDetailsResponse {
+	response := AdminSubscriptionDetailsResponse{
+		Subscription: NewAdminSubscriptionResponse(sub),
+		Deliveries:   make([]AdminDeliveryResponse, len(notifications)),
+	}
+	for i, notification := range notifications {
+		response.Deliveries[i] = AdminDeliveryResponse{
+			Id:             notification.Id,
+			IdempotencyKey: notification.IdempotencyKey,
+			Subject:        notification.Subject,
+			Status:         string(notification.Status),
+			Attempts:       notification.Attempts,
+			LastError:      notification.LastError,
+			CreatedAt:      notification.CreatedAt,
+			NextAttemptAt:  notification.NextAttemptAt,
+			SentAt:         notification.SentAt,
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,33 @@
+package responses
+
+import "github.com/slbmax/ses-weather-app/pkg/weatherapi"
+
+type CityResponse struct {
+	// LocationId is empty when the provider has no ids, the candidate can still be subscribed by coordinates
+	LocationId string  `json:"location_id,omitempty"`
+	Name       string  `json:"name"`
+	Region     string  `json:"region"`
+	Country    string  `json:"country"`
+	Latitude   float64 `json:"lat"`
+	Longitude  float64 `json:"lon"`
+}
+
+type CitiesSearchResponse struct {
+	Cities []CityResponse `json:"cities"`
+}
+
+func NewCitiesSearchResponse(locations []weatherapi.Location) CitiesSearchResponse {
+	cities := make([]CityResponse, len(locations))
+	for i, location := range locations {
+		cities[i] = CityResponse{
+			LocationId: location.Id,
+			Name:       location.Name,
+			Region:     location.Region,
+			Country:    location.Country,
+			Latitude:   location.Latitude,
+			Longitude:  location.Longitude,
+		}
+	}
+
+	return CitiesSearchResponse{Cities: cities}
+}
@@ -0,0 +1,65 @@
+package responses
+
+import "github.com/slbmax/ses-weather-app/pkg/weatherapi"
+
+type ForecastHourResponse struct {
+	Time         string  `json:"time"`
+	Temperature  float32 `json:"temperature"`
+	Humidity     uint8   `json:"humidity"`
+	ChanceOfRain uint8   `json:"chance_of_rain"`
+	Description  string  `json:"description"`
+}
+
+type ForecastDayResponse struct {
+	Date               string                 `json:"date"`
+	MinTemperature     float32                `json:"min_temperature"`
+	MaxTemperature     float32                `json:"max_temperature"`
+	AvgTemperature     float32                `json:"avg_temperature"`
+	TotalPrecipitation float32                `json:"total_precipitation"`
+	ChanceOfRain       uint8                  `json:"chance_of_rain"`
+	ChanceOfSnow       uint8                  `json:"chance_of_snow"`
+	Description        string                 `json:"description"`
+	Sunrise            string                 `json:"sunrise"`
+	Sunset             string                 `json:"sunset"`
+	Hours              []ForecastHourResponse `json:"hours"`
+}
+
+type ForecastResponse struct {
+	City string                `json:"city"`
+	Days []ForecastDayResponse `json:"days"`
+}
+
+func NewForecastResponse(forecast weatherapi.WeatherForecastResponse) ForecastResponse {
+	days := make([]ForecastDayResponse, len(forecast.Forecast.Days))
+	for i, day := range forecast.Forecast.Days {
+		hours := make([]ForecastHourResponse, len(day.Hours))
+		for j, hour := range day.Hours {
+			hours[j] = ForecastHourResponse{
+				Time:         hour.Time,
+				Temperature:  hour.Temperature,
+				Humidity:     hour.Humidity,
+				ChanceOfRain: hour.ChanceOfRain,
+				Description:  hour.Condition.Text,
+			}
+		}
+
+		days[i] = ForecastDayResponse{
+			Date:               day.Date,
+			MinTemperature:     day.Day.MinTemperature,
+			MaxTemperature:     day.Day.MaxTemperature,
+			AvgTemperature:     day.Day.AvgTemperature,
+			TotalPrecipitation: day.Day.TotalPrecipitation,
+			ChanceOfRain:       day.Day.ChanceOfRain,
+			ChanceOfSnow:       day.Day.ChanceOfSnow,
+			Description:        day.Day.Condition.Text,
+			Sunrise:            day.Astro.Sunrise,
+			Sunset:             day.Astro.Sunset,
+			Hours:              hours,
+		}
+	}
+
+	return ForecastResponse{
+		City: forecast.Location.Name,
+		Days: days,
+	}
+}
@@ -0,0 +1,48 @@
+package responses
+
+import (
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+)
+
+// SubscriptionResponse is the subscription as seen by its owner
+type SubscriptionResponse struct {
+	Email          string     `json:"email"`
+	City           string     `json:"city"`
+	LocationId     string     `json:"location_id,omitempty"`
+	Region         string     `json:"region,omitempty"`
+	Country        string     `json:"country,omitempty"`
+	Timezone       string     `json:"timezone"`
+	Frequency      string     `json:"frequency"`
+	DeliveryHour   int        `json:"delivery_hour"`
+	Schedule       string     `json:"schedule"`
+	Language       string     `json:"language"`
+	Units          string     `json:"units"`
+	Confirmed      bool       `json:"confirmed"`
+	Paused         bool       `json:"paused"`
+	NextNotifyAt   *time.Time `json:"next_notify_at"`
+	LastNotifiedAt *time.Time `json:"last_notified_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func NewSubscriptionResponse(sub database.Subscription) SubscriptionResponse {
+	return SubscriptionResponse{
+		Email:          sub.Email,
+		City:           sub.City,
+		LocationId:     sub.LocationId,
+		Region:         sub.Region,
+		Country:        sub.Country,
+		Timezone:       sub.Timezone,
+		Frequency:      string(sub.Frequency),
+		DeliveryHour:   sub.DeliveryHour,
+		Schedule:       sub.Schedule,
+		Language:       string(sub.Language.OrDefault()),
+		Units:          string(sub.Units.OrDefault()),
+		Confirmed:      sub.Confirmed,
+		Paused:         sub.PausedAt != nil,
+		NextNotifyAt:   sub.NextNotifyAt,
+		LastNotifiedAt: sub.LastNotifiedAt,
+		CreatedAt:      sub.CreatedAt,
+	}
+}
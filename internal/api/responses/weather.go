@@ -2,16 +2,128 @@ package responses
 
 import "github.com/slbmax/ses-weather-app/pkg/weatherapi"
 
+// the extended fields of WeatherResponse, included only if selected
+const (
+	WeatherFieldFeelsLike     = "feels_like"
+	WeatherFieldWind          = "wind"
+	WeatherFieldPressure      = "pressure"
+	WeatherFieldPrecipitation = "precipitation"
+	WeatherFieldUV            = "uv"
+	WeatherFieldVisibility    = "visibility"
+	WeatherFieldCloud         = "cloud"
+	WeatherFieldIsDay         = "is_day"
+	WeatherFieldCondition     = "condition"
+	WeatherFieldAirQuality    = "air_quality"
+)
+
+var WeatherFields = []string{
+	WeatherFieldFeelsLike,
+	WeatherFieldWind,
+	WeatherFieldPressure,
+	WeatherFieldPrecipitation,
+	WeatherFieldUV,
+	WeatherFieldVisibility,
+	WeatherFieldCloud,
+	WeatherFieldIsDay,
+	WeatherFieldCondition,
+	WeatherFieldAirQuality,
+}
+
+// WeatherResponse has the values in the requested units: °C, km/h, mb, mm and km for the metric ones,
+// °F, mph, inHg, in and miles for the imperial ones. The first three fields are always present.
 type WeatherResponse struct {
 	Temperature float32 `json:"temperature"`
 	Humidity    uint8   `json:"humidity"`
 	Description string  `json:"description"`
+
+	FeelsLike     *float32            `json:"feels_like,omitempty"`
+	Wind          *WindResponse       `json:"wind,omitempty"`
+	Pressure      *float32            `json:"pressure,omitempty"`
+	Precipitation *float32            `json:"precipitation,omitempty"`
+	UV            *float32            `json:"uv,omitempty"`
+	Visibility    *float32            `json:"visibility,omitempty"`
+	Cloud         *uint8              `json:"cloud,omitempty"`
+	IsDay         *bool               `json:"is_day,omitempty"`
+	Condition     *ConditionResponse  `json:"condition,omitempty"`
+	AirQuality    *AirQualityResponse `json:"air_quality,omitempty"`
+}
+
+type WindResponse struct {
+	Speed     float32 `json:"speed"`
+	Gust      float32 `json:"gust"`
+	Degree    uint16  `json:"degree"`
+	Direction string  `json:"direction"`
+}
+
+// ConditionResponse Code is the weatherapi.com condition code, whatever provider is used
+type ConditionResponse struct {
+	Code int    `json:"code"`
+	Icon string `json:"icon,omitempty"`
 }
 
-func NewWeatherResponse(weather weatherapi.CurrentWeather) WeatherResponse {
-	return WeatherResponse{
-		Temperature: weather.Temperature,
+// AirQualityResponse is omitted if the provider doesn't support it
+type AirQualityResponse struct {
+	CO           float32 `json:"co"`
+	NO2          float32 `json:"no2"`
+	O3           float32 `json:"o3"`
+	SO2          float32 `json:"so2"`
+	PM25         float32 `json:"pm2_5"`
+	PM10         float32 `json:"pm10"`
+	USEPAIndex   uint8   `json:"us_epa_index"`
+	GBDefraIndex uint8   `json:"gb_defra_index"`
+}
+
+func NewWeatherResponse(weather weatherapi.CurrentWeather, units weatherapi.Units, fields ...string) WeatherResponse {
+	response := WeatherResponse{
+		Temperature: units.Temperature(weather.Temperature, weather.TemperatureF),
 		Humidity:    weather.Humidity,
 		Description: weather.Condition.Text,
 	}
+
+	for _, field := range fields {
+		switch field {
+		case WeatherFieldFeelsLike:
+			response.FeelsLike = ptr(units.Temperature(weather.FeelsLike, weather.FeelsLikeF))
+		case WeatherFieldWind:
+			response.Wind = &WindResponse{
+				Speed:     units.Speed(weather.WindKph, weather.WindMph),
+				Gust:      units.Speed(weather.GustKph, weather.GustMph),
+				Degree:    weather.WindDegree,
+				Direction: weather.WindDirection,
+			}
+		case WeatherFieldPressure:
+			response.Pressure = ptr(units.Value(weather.PressureMb, weather.PressureIn))
+		case WeatherFieldPrecipitation:
+			response.Precipitation = ptr(units.Value(weather.PrecipitationMm, weather.PrecipitationIn))
+		case WeatherFieldUV:
+			response.UV = ptr(weather.UV)
+		case WeatherFieldVisibility:
+			response.Visibility = ptr(units.Value(weather.VisibilityKm, weather.VisibilityMiles))
+		case WeatherFieldCloud:
+			response.Cloud = ptr(weather.Cloud)
+		case WeatherFieldIsDay:
+			response.IsDay = ptr(weather.IsDay == 1)
+		case WeatherFieldCondition:
+			response.Condition = &ConditionResponse{Code: weather.Condition.Code, Icon: weather.Condition.Icon}
+		case WeatherFieldAirQuality:
+			if aqi := weather.AirQuality; aqi != nil {
+				response.AirQuality = &AirQualityResponse{
+					CO:           aqi.CO,
+					NO2:          aqi.NO2,
+					O3:           aqi.O3,
+					SO2:          aqi.SO2,
+					PM25:         aqi.PM25,
+					PM10:         aqi.PM10,
+					USEPAIndex:   aqi.USEPAIndex,
+					GBDefraIndex: aqi.GBDefraIndex,
+				}
+			}
+		}
+	}
+
+	return response
+}
+
+func ptr[T any](value T) *T {
+	return &value
 }
@@ -9,23 +9,51 @@ import (
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/slbmax/ses-weather-app/internal/api/ctx"
 	"github.com/slbmax/ses-weather-app/internal/api/handlers"
 	"github.com/slbmax/ses-weather-app/internal/api/requests"
 	"github.com/slbmax/ses-weather-app/internal/database"
 	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
 	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 	"gitlab.com/distributed_lab/ape"
 	"gitlab.com/distributed_lab/logan/v3"
 )
 
+const (
+	defaultConfirmationTokenTTL       = 24 * time.Hour
+	defaultConfirmationResendInterval = time.Minute
+)
+
+type ServerOpts struct {
+	// AdminApiKeys protect the admin API, which is disabled when there are none
+	AdminApiKeys        []string
+	CitySearchRateLimit RateLimit
+	ResendRateLimit     RateLimit
+	// TrustProxy limits by the client IP from the X-Forwarded-For or X-Real-IP headers instead of the remote address
+	TrustProxy bool
+	// ConfirmationTokenTTL and ConfirmationResendInterval are replaced with defaults if zero
+	ConfirmationTokenTTL       time.Duration
+	ConfirmationResendInterval time.Duration
+	// UnsubscribeLinks verifies the signed one-click unsubscribe links, they are rejected if nil
+	UnsubscribeLinks *unsubscribe.Links
+}
+
 type Server struct {
 	logger     *logan.Entry
 	listener   net.Listener
 	db         database.Database
 	mailer     mailer.Mailer
 	weatherApi weatherapi.WeatherProvider
+
+	adminApiKeys      []string
+	citySearchLimiter *rateLimiter
+	resendLimiter     *rateLimiter
+	trustProxy        bool
+	confirmation      ctx.Confirmation
+	unsubscribeLinks  *unsubscribe.Links
 }
 
 func NewServer(
@@ -34,13 +62,30 @@ func NewServer(
 	db database.Database,
 	mailer mailer.Mailer,
 	logger *logan.Entry,
+	opts ServerOpts,
 ) *Server {
+	if opts.ConfirmationTokenTTL <= 0 {
+		opts.ConfirmationTokenTTL = defaultConfirmationTokenTTL
+	}
+	if opts.ConfirmationResendInterval <= 0 {
+		opts.ConfirmationResendInterval = defaultConfirmationResendInterval
+	}
+
 	return &Server{
-		logger:     logger,
-		listener:   listener,
-		weatherApi: weatherApi,
-		mailer:     mailer,
-		db:         db,
+		logger:            logger,
+		listener:          listener,
+		weatherApi:        weatherApi,
+		mailer:            mailer,
+		db:                db,
+		adminApiKeys:      opts.AdminApiKeys,
+		citySearchLimiter: newRateLimiter(opts.CitySearchRateLimit),
+		resendLimiter:     newRateLimiter(opts.ResendRateLimit),
+		trustProxy:        opts.TrustProxy,
+		confirmation: ctx.Confirmation{
+			TokenTTL:       opts.ConfirmationTokenTTL,
+			ResendInterval: opts.ConfirmationResendInterval,
+		},
+		unsubscribeLinks: opts.UnsubscribeLinks,
 	}
 }
 
@@ -72,10 +117,16 @@ func (s *Server) Run(ctx context.Context) error {
 func (s *Server) requestHandler() chi.Router {
 	r := chi.NewRouter()
 
+	if s.trustProxy {
+		// the proxy sets the client IP the rate limiters key the buckets on
+		r.Use(middleware.RealIP)
+	}
+
 	r.Use(
 		cors.Handler(cors.Options{
 			// it is not a production code, so we allow all origins
 			AllowedOrigins: []string{"*"},
+			AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
 		}),
 		ape.RecoverMiddleware(s.logger),
 		ape.LoganMiddleware(s.logger),
@@ -84,14 +135,42 @@ func (s *Server) requestHandler() chi.Router {
 			ctx.WeatherApiProvider(s.weatherApi),
 			ctx.DatabaseProvider(s.db),
 			ctx.MailerProvider(s.mailer),
+			ctx.ConfirmationProvider(s.confirmation),
+			ctx.UnsubscribeLinksProvider(s.unsubscribeLinks),
 		),
 	)
 
 	r.Route("/api", func(r chi.Router) {
 		r.Get("/weather", handlers.Weather)
+		r.Get("/forecast", handlers.Forecast)
+		r.With(s.citySearchLimiter.middleware).Get("/cities/search", handlers.SearchCities)
 		r.Post("/subscribe", handlers.Subscribe)
+		r.With(s.resendLimiter.middleware).Post("/subscribe/resend", handlers.ResendConfirmation)
+		r.Post("/alerts", handlers.CreateAlert)
 		r.Get(fmt.Sprintf("/confirm/{%s}", requests.TokenParam), handlers.Confirm)
 		r.Get(fmt.Sprintf("/unsubscribe/{%s}", requests.TokenParam), handlers.Unsubscribe)
+		r.Post(fmt.Sprintf("/unsubscribe/{%s}", requests.TokenParam), handlers.OneClickUnsubscribe)
+		r.Route(fmt.Sprintf("/subscriptions/{%s}", requests.TokenParam), func(r chi.Router) {
+			r.Get("/", handlers.GetSubscription)
+			r.Patch("/", handlers.UpdateSubscription)
+			r.Delete("/", handlers.DeleteSubscription)
+		})
+	})
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(adminAuth(s.adminApiKeys))
+		r.Route("/subscriptions", func(r chi.Router) {
+			r.Get("/", handlers.AdminListSubscriptions)
+			r.Get("/suspended", handlers.AdminListSuspended)
+			r.Route(fmt.Sprintf("/{%s}", requests.SubscriptionIdParam), func(r chi.Router) {
+				r.Get("/", handlers.AdminGetSubscription)
+				r.Delete("/", handlers.AdminDeleteSubscription)
+				r.Post("/confirm", handlers.AdminConfirmSubscription)
+				r.Post("/suspend", handlers.AdminSuspendSubscription)
+				r.Post("/resume", handlers.AdminResumeSubscription)
+				r.Post("/notify", handlers.AdminNotifySubscription)
+			})
+		})
 	})
 
 	return r
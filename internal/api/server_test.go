@@ -8,22 +8,36 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/slbmax/ses-weather-app/internal/api/requests"
 	"github.com/slbmax/ses-weather-app/internal/api/responses"
 	"github.com/slbmax/ses-weather-app/internal/database"
 	subsMock "github.com/slbmax/ses-weather-app/internal/database/mock"
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
 	mailerMock "github.com/slbmax/ses-weather-app/internal/mailer/mock"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
 	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 	weatherApiMock "github.com/slbmax/ses-weather-app/pkg/weatherapi/mock"
 	"github.com/stretchr/testify/mock"
 	"gitlab.com/distributed_lab/logan/v3"
 )
 
+const adminApiKey = "admin-api-key"
+
+var unsubscribeLinks = unsubscribe.NewLinks("signing-key", "http://localhost:8090/api")
+
 var (
 	server           *httptest.Server
 	subscriptionMock *subsMock.MockSubscriptionsQ
+	notificationMock *subsMock.MockNotificationsQ
+	subscriberMock   *subsMock.MockSubscribersQ
+	alertRuleMock    *subsMock.MockAlertRulesQ
+	tokenMock        *subsMock.MockTokensQ
 	weatherMock      *weatherApiMock.MockWeatherProvider
 	mailMock         *mailerMock.MockMailer
 )
@@ -32,6 +46,18 @@ func resetMocks() {
 	subscriptionMock.Calls = []mock.Call{}
 	subscriptionMock.Mock = mock.Mock{}
 
+	notificationMock.Calls = []mock.Call{}
+	notificationMock.Mock = mock.Mock{}
+
+	subscriberMock.Calls = []mock.Call{}
+	subscriberMock.Mock = mock.Mock{}
+
+	alertRuleMock.Calls = []mock.Call{}
+	alertRuleMock.Mock = mock.Mock{}
+
+	tokenMock.Calls = []mock.Call{}
+	tokenMock.Mock = mock.Mock{}
+
 	weatherMock.Calls = []mock.Call{}
 	weatherMock.Mock = mock.Mock{}
 
@@ -41,16 +67,21 @@ func resetMocks() {
 
 func TestMain(m *testing.M) {
 	subscriptionMock = &subsMock.MockSubscriptionsQ{}
+	notificationMock = &subsMock.MockNotificationsQ{}
+	subscriberMock = &subsMock.MockSubscribersQ{}
+	alertRuleMock = &subsMock.MockAlertRulesQ{}
+	tokenMock = &subsMock.MockTokensQ{}
 	weatherMock = &weatherApiMock.MockWeatherProvider{}
 	mailMock = &mailerMock.MockMailer{}
 
-	db := subsMock.NewDatabase(subscriptionMock)
+	db := subsMock.NewDatabase(subscriptionMock, notificationMock, alertRuleMock, subscriberMock, tokenMock)
 	srv := NewServer(
 		nil, // won't be even used
 		weatherMock,
 		db,
 		mailMock,
 		logan.New().Level(logan.ErrorLevel), // ignoring logging middleware
+		ServerOpts{AdminApiKeys: []string{"another-admin-api-key", adminApiKey}, UnsubscribeLinks: unsubscribeLinks},
 	)
 	server = httptest.NewServer(srv.requestHandler())
 
@@ -64,11 +95,16 @@ func TestServer_Weather(t *testing.T) {
 	stringPtr := func(s string) *string {
 		return &s
 	}
+	float32Ptr := func(f float32) *float32 {
+		return &f
+	}
 
 	testCases := map[string]struct {
 		preparation    func()
 		cleanup        func()
 		city           *string
+		units          string
+		fields         string
 		expectedStatus int
 		response       *responses.WeatherResponse
 	}{
@@ -81,7 +117,7 @@ func TestServer_Weather(t *testing.T) {
 		},
 		"must 404 (city not found)": {
 			preparation: func() {
-				weatherMock.On("GetCurrentWeather", "non-existent").Return(nil, weatherapi.ErrCityNotFound)
+				weatherMock.On("GetCurrentWeather", mock.Anything, "non-existent").Return(nil, weatherapi.ErrCityNotFound)
 			},
 			cleanup: func() {
 				weatherMock.AssertExpectations(t)
@@ -92,7 +128,7 @@ func TestServer_Weather(t *testing.T) {
 		},
 		"must 500 (unknown error)": {
 			preparation: func() {
-				weatherMock.On("GetCurrentWeather", "London").Return(nil, errors.New("unknown error"))
+				weatherMock.On("GetCurrentWeather", mock.Anything, "London").Return(nil, errors.New("unknown error"))
 			},
 			cleanup: func() {
 				weatherMock.AssertExpectations(t)
@@ -101,9 +137,76 @@ func TestServer_Weather(t *testing.T) {
 			city:           stringPtr("London"),
 			expectedStatus: http.StatusInternalServerError,
 		},
+		"must 400 (unknown units)": {
+			city:           stringPtr("London"),
+			units:          "kelvin",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 200 (imperial units)": {
+			preparation: func() {
+				weatherMock.On("GetCurrentWeather", mock.Anything, "London").Return(&weatherapi.WeatherCurrentResponse{
+					CurrentWeather: weatherapi.CurrentWeather{
+						Temperature:  25,
+						TemperatureF: 77,
+						Humidity:     60,
+						Condition:    weatherapi.WeatherCondition{Text: "Sunny"},
+					},
+				}, nil)
+			},
+			cleanup: func() {
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+			city:           stringPtr("London"),
+			units:          "imperial",
+			expectedStatus: http.StatusOK,
+			response: &responses.WeatherResponse{
+				Temperature: 77,
+				Humidity:    60,
+				Description: "Sunny",
+			},
+		},
+		"must 400 (unknown field)": {
+			city:           stringPtr("London"),
+			fields:         "wind,mood",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 200 (extended fields)": {
+			preparation: func() {
+				weatherMock.On("GetCurrentWeather", mock.Anything, "London").Return(&weatherapi.WeatherCurrentResponse{
+					CurrentWeather: weatherapi.CurrentWeather{
+						Temperature:   25,
+						Humidity:      60,
+						WindKph:       14.4,
+						WindMph:       8.9,
+						WindDegree:    200,
+						WindDirection: "SSW",
+						GustKph:       20.2,
+						GustMph:       12.6,
+						UV:            6,
+						Condition:     weatherapi.WeatherCondition{Text: "Sunny", Code: 1000},
+					},
+				}, nil)
+			},
+			cleanup: func() {
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+			city:           stringPtr("London"),
+			units:          "imperial",
+			fields:         "wind,uv,condition,air_quality",
+			expectedStatus: http.StatusOK,
+			response: &responses.WeatherResponse{
+				Humidity:    60,
+				Description: "Sunny",
+				Wind:        &responses.WindResponse{Speed: 8.9, Gust: 12.6, Degree: 200, Direction: "SSW"},
+				UV:          float32Ptr(6),
+				Condition:   &responses.ConditionResponse{Code: 1000},
+			},
+		},
 		"must 200 (valid response)": {
 			preparation: func() {
-				weatherMock.On("GetCurrentWeather", "London").Return(&weatherapi.WeatherCurrentResponse{
+				weatherMock.On("GetCurrentWeather", mock.Anything, "London").Return(&weatherapi.WeatherCurrentResponse{
 					CurrentWeather: weatherapi.CurrentWeather{
 						Temperature: 25,
 						Humidity:    60,
@@ -137,6 +240,12 @@ func TestServer_Weather(t *testing.T) {
 			if tc.city != nil {
 				endpoint += "?city=" + *tc.city
 			}
+			if tc.units != "" {
+				endpoint += "&units=" + tc.units
+			}
+			if tc.fields != "" {
+				endpoint += "&fields=" + tc.fields
+			}
 
 			response, err := http.Get(endpoint)
 			if tc.cleanup != nil {
@@ -156,7 +265,7 @@ func TestServer_Weather(t *testing.T) {
 					t.Fatalf("failed to decode response: %v", err)
 				}
 
-				if resp != *tc.response {
+				if !reflect.DeepEqual(resp, *tc.response) {
 					t.Fatalf("expected response %+v, got %+v", *tc.response, resp)
 				}
 			}
@@ -164,150 +273,95 @@ func TestServer_Weather(t *testing.T) {
 	}
 }
 
-func TestServer_Subscribe(t *testing.T) {
+func TestServer_Forecast(t *testing.T) {
+	forecast := &weatherapi.WeatherForecastResponse{
+		Location: weatherapi.Location{Name: "London"},
+		Forecast: weatherapi.Forecast{
+			Days: []weatherapi.ForecastDay{
+				{
+					Date: "2025-06-01",
+					Day: weatherapi.DayForecast{
+						MaxTemperature: 22,
+						MinTemperature: 12,
+						ChanceOfRain:   70,
+						Condition:      weatherapi.WeatherCondition{Text: "Patchy rain"},
+					},
+					Astro: weatherapi.Astro{Sunrise: "04:45 AM", Sunset: "09:10 PM"},
+					Hours: []weatherapi.HourForecast{
+						{Time: "2025-06-01 00:00", Temperature: 13, ChanceOfRain: 20},
+					},
+				},
+			},
+		},
+	}
+
 	testCases := map[string]struct {
 		preparation    func()
-		call           func() (*http.Response, error)
-		expectedStatus int
 		cleanup        func()
+		query          string
+		expectedStatus int
+		response       *responses.ForecastResponse
 	}{
-		"must 400 (missing required fields)": {
-			call: func() (*http.Response, error) {
-				return http.PostForm(server.URL+"/api/subscribe", nil)
-			},
+		"must 400 (missing city)": {
+			query:          "?days=1",
 			expectedStatus: http.StatusBadRequest,
 		},
-		"must 400 (missing required fields in json body)": {
-			call: func() (*http.Response, error) {
-				return http.Post(server.URL+"/api/subscribe", "application/json", nil)
-			},
+		"must 400 (invalid days)": {
+			query:          "?city=London&days=abc",
 			expectedStatus: http.StatusBadRequest,
 		},
-		"must 400 (invalid email)": {
-			call: func() (*http.Response, error) {
-				return http.PostForm(server.URL+"/api/subscribe", url.Values{
-					"email":     {"invalid-email"},
-					"city":      {"New York"},
-					"frequency": {"daily"},
-				})
-			},
+		"must 400 (days out of range)": {
+			query:          "?city=London&days=15",
 			expectedStatus: http.StatusBadRequest,
 		},
-		"must 409 (subscription already exists) ": {
-			preparation: func() {
-				subscriptionMock.On("Insert", mock.Anything).Return(int64(0), database.ErrSubscriptionExists)
-			},
-			call: func() (*http.Response, error) {
-				return http.PostForm(server.URL+"/api/subscribe", url.Values{
-					"email":     {"max@gmail.com"},
-					"city":      {"New York"},
-					"frequency": {"daily"},
-				})
-			},
-			expectedStatus: http.StatusConflict,
-			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
-				resetMocks()
-			},
-		},
-		"must 404 (city not found error) ": {
+		"must 404 (city not found)": {
 			preparation: func() {
-				subscriptionMock.On("Insert", mock.Anything).Return(int64(0), nil)
-				weatherMock.On("GetCurrentWeather", "New York").Return(nil, weatherapi.ErrCityNotFound)
-			},
-			call: func() (*http.Response, error) {
-				return http.PostForm(server.URL+"/api/subscribe", url.Values{
-					"email":     {"max@gmail.com"},
-					"city":      {"New York"},
-					"frequency": {"daily"},
-				})
+				weatherMock.On("GetForecast", mock.Anything, "non-existent", 3).Return(nil, weatherapi.ErrCityNotFound)
 			},
-			expectedStatus: http.StatusNotFound,
 			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
 				weatherMock.AssertExpectations(t)
 				resetMocks()
 			},
+			query:          "?city=non-existent",
+			expectedStatus: http.StatusNotFound,
 		},
-		"must 500 (unknown error) ": {
+		"must 500 (unknown error)": {
 			preparation: func() {
-				subscriptionMock.On("Insert", mock.Anything).Return(int64(0), errors.New("db error"))
-			},
-			call: func() (*http.Response, error) {
-				return http.PostForm(server.URL+"/api/subscribe", url.Values{
-					"email":     {"max@gmail.com"},
-					"city":      {"New York"},
-					"frequency": {"daily"},
-				})
+				weatherMock.On("GetForecast", mock.Anything, "London", 1).Return(nil, errors.New("unknown error"))
 			},
-			expectedStatus: http.StatusInternalServerError,
 			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
 				resetMocks()
 			},
-		},
-		"must 500 (email sending error) ": {
-			preparation: func() {
-				subscriptionMock.On("Insert", mock.Anything).Return(int64(0), nil)
-				weatherMock.On("GetCurrentWeather", "New York").Return(&weatherapi.WeatherCurrentResponse{}, nil)
-				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(errors.New("error"))
-
-			},
-			call: func() (*http.Response, error) {
-				return http.PostForm(server.URL+"/api/subscribe", url.Values{
-					"email":     {"max@gmail.com"},
-					"city":      {"New York"},
-					"frequency": {"daily"},
-				})
-			},
+			query:          "?city=London&days=1",
 			expectedStatus: http.StatusInternalServerError,
-			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
-				resetMocks()
-			},
 		},
-		"must 200 (url val)": {
+		"must 200 (valid response)": {
 			preparation: func() {
-				subscriptionMock.On("Insert", mock.Anything).Return(int64(1), nil)
-				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
-				weatherMock.On("GetCurrentWeather", "New York").Return(&weatherapi.WeatherCurrentResponse{}, nil)
-			},
-			call: func() (*http.Response, error) {
-				return http.PostForm(server.URL+"/api/subscribe", url.Values{
-					"email":     {"max@gmail.com"},
-					"city":      {"New York"},
-					"frequency": {"daily"},
-				})
+				weatherMock.On("GetForecast", mock.Anything, "London", 1).Return(forecast, nil)
 			},
-			expectedStatus: http.StatusOK,
 			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
-				mailMock.AssertExpectations(t)
 				weatherMock.AssertExpectations(t)
 				resetMocks()
 			},
-		},
-		"must 200 (json body)": {
-			preparation: func() {
-				subscriptionMock.On("Insert", mock.Anything).Return(int64(1), nil)
-				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
-				weatherMock.On("GetCurrentWeather", "New York").Return(&weatherapi.WeatherCurrentResponse{}, nil)
-			},
-			call: func() (*http.Response, error) {
-				req, _ := json.Marshal(requests.SubscribeRequest{
-					Email:     "max@gmail.com",
-					City:      "New York",
-					Frequency: "daily",
-				})
-
-				return http.Post(server.URL+"/api/subscribe", "application/json", bytes.NewReader(req))
-			},
+			query:          "?city=London&days=1",
 			expectedStatus: http.StatusOK,
-			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
-				mailMock.AssertExpectations(t)
-				weatherMock.AssertExpectations(t)
-				resetMocks()
+			response: &responses.ForecastResponse{
+				City: "London",
+				Days: []responses.ForecastDayResponse{
+					{
+						Date:           "2025-06-01",
+						MinTemperature: 12,
+						MaxTemperature: 22,
+						ChanceOfRain:   70,
+						Description:    "Patchy rain",
+						Sunrise:        "04:45 AM",
+						Sunset:         "09:10 PM",
+						Hours: []responses.ForecastHourResponse{
+							{Time: "2025-06-01 00:00", Temperature: 13, ChanceOfRain: 20},
+						},
+					},
+				},
 			},
 		},
 	}
@@ -318,93 +372,105 @@ func TestServer_Subscribe(t *testing.T) {
 				tc.preparation()
 			}
 
-			response, err := tc.call()
-			if err != nil {
-				t.Fatalf("failed to make request: %v", err)
-			}
-
+			response, err := http.Get(server.URL + "/api/forecast" + tc.query)
 			if tc.cleanup != nil {
 				tc.cleanup()
 			}
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
 
 			if response.StatusCode != tc.expectedStatus {
 				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
 			}
+
+			if tc.response != nil {
+				var resp responses.ForecastResponse
+				if err = json.NewDecoder(response.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+
+				if !reflect.DeepEqual(resp, *tc.response) {
+					t.Fatalf("expected response %+v, got %+v", *tc.response, resp)
+				}
+			}
 		})
 	}
 }
 
-func TestServer_Confirm(t *testing.T) {
-	validToken := "00000000000000000000000000000000"
+func TestServer_CitiesSearch(t *testing.T) {
+	kyiv := weatherapi.Location{
+		Id:        "weatherapi:2",
+		Name:      "Kyiv",
+		Region:    "Kyyivs'ka Oblast'",
+		Country:   "Ukraine",
+		Latitude:  50.43,
+		Longitude: 30.52,
+	}
+
 	testCases := map[string]struct {
 		preparation    func()
 		cleanup        func()
-		token          string
+		query          string
 		expectedStatus int
+		response       *responses.CitiesSearchResponse
 	}{
-		"must 400 (invalid token)": {
+		"must 400 (missing query)": {
 			expectedStatus: http.StatusBadRequest,
-			token:          "awe",
 		},
-		"must 400 (subscription already confirmed)": {
-			preparation: func() {
-				subscriptionMock.On("GetByToken", validToken).Return(&database.Subscription{
-					Confirmed: true,
-				}, nil)
-			},
-			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
-				resetMocks()
-			},
-			token:          validToken,
+		"must 400 (too short query)": {
+			query:          "?q=K",
 			expectedStatus: http.StatusBadRequest,
 		},
-		"must 404 (token not found)": {
+		"must 400 (limit out of range)": {
+			query:          "?q=Ky&limit=11",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 500 (unknown error)": {
 			preparation: func() {
-				subscriptionMock.On("GetByToken", validToken).Return(nil, nil)
+				weatherMock.On("SearchLocations", mock.Anything, "Ky", 5).Return(nil, errors.New("unknown error"))
 			},
 			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
 				resetMocks()
 			},
-			token:          validToken,
-			expectedStatus: http.StatusNotFound,
+			query:          "?q=Ky",
+			expectedStatus: http.StatusInternalServerError,
 		},
-		"must 500 (mail sending error)": {
+		"must 200 (no candidates)": {
 			preparation: func() {
-				subscriptionMock.On("GetByToken", validToken).Return(&database.Subscription{
-					Id:        1,
-					Confirmed: false,
-					Email:     "max@gmail.com",
-				}, nil)
-				subscriptionMock.On("UpdateConfirmed", int64(1), mock.Anything).Return(nil)
-				mailMock.On("SendConfirmationSuccessEmail", "max@gmail.com", mock.Anything).Return(errors.New("error"))
+				weatherMock.On("SearchLocations", mock.Anything, "Qwerty", 5).Return([]weatherapi.Location{}, nil)
 			},
 			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
-				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
 				resetMocks()
 			},
-			token:          validToken,
-			expectedStatus: http.StatusInternalServerError,
+			query:          "?q=Qwerty",
+			expectedStatus: http.StatusOK,
+			response:       &responses.CitiesSearchResponse{Cities: []responses.CityResponse{}},
 		},
-		"must 200": {
+		"must 200 (valid response)": {
 			preparation: func() {
-				subscriptionMock.On("GetByToken", validToken).Return(&database.Subscription{
-					Id:        1,
-					Confirmed: false,
-					Email:     "max@gmail.com",
-				}, nil)
-				subscriptionMock.On("UpdateConfirmed", int64(1), mock.Anything).Return(nil)
-				mailMock.On("SendConfirmationSuccessEmail", "max@gmail.com", mock.Anything).Return(nil)
+				weatherMock.On("SearchLocations", mock.Anything, "Kyi", 3).Return([]weatherapi.Location{kyiv}, nil)
 			},
 			cleanup: func() {
-				subscriptionMock.AssertExpectations(t)
-				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
 				resetMocks()
 			},
-			token:          validToken,
+			query:          "?q=Kyi&limit=3",
 			expectedStatus: http.StatusOK,
+			response: &responses.CitiesSearchResponse{
+				Cities: []responses.CityResponse{
+					{
+						LocationId: kyiv.Id,
+						Name:       kyiv.Name,
+						Region:     kyiv.Region,
+						Country:    kyiv.Country,
+						Latitude:   kyiv.Latitude,
+						Longitude:  kyiv.Longitude,
+					},
+				},
+			},
 		},
 	}
 
@@ -414,7 +480,7 @@ func TestServer_Confirm(t *testing.T) {
 				tc.preparation()
 			}
 
-			response, err := http.Get(server.URL + "/api/confirm/" + tc.token)
+			response, err := http.Get(server.URL + "/api/cities/search" + tc.query)
 			if tc.cleanup != nil {
 				tc.cleanup()
 			}
@@ -425,54 +491,1678 @@ func TestServer_Confirm(t *testing.T) {
 			if response.StatusCode != tc.expectedStatus {
 				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
 			}
+
+			if tc.response != nil {
+				var resp responses.CitiesSearchResponse
+				if err = json.NewDecoder(response.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+
+				if !reflect.DeepEqual(resp, *tc.response) {
+					t.Fatalf("expected response %+v, got %+v", *tc.response, resp)
+				}
+			}
 		})
 	}
 }
 
-func TestServer_Unsubscribe(t *testing.T) {
-	validToken := "00000000000000000000000000000000"
-	testCases := map[string]struct {
-		preparation    func()
-		cleanup        func()
-		token          string
-		expectedStatus int
-	}{
-		"must 400 (invalid token)": {
-			expectedStatus: http.StatusBadRequest,
-			token:          "awe",
+func TestServer_CitiesSearchRateLimit(t *testing.T) {
+	// separate server to not share the client buckets with other tests
+	limitedServer := httptest.NewServer(NewServer(
+		nil,
+		weatherapi.NewMockWeatherProvider(),
+		subsMock.NewDatabase(subscriptionMock, notificationMock, alertRuleMock, subscriberMock, tokenMock),
+		mailMock,
+		logan.New().Level(logan.ErrorLevel),
+		ServerOpts{CitySearchRateLimit: RateLimit{Rate: 0.01, Burst: 2}},
+	).requestHandler())
+	defer limitedServer.Close()
+
+	expectedStatuses := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i, expectedStatus := range expectedStatuses {
+		response, err := http.Get(limitedServer.URL + "/api/cities/search?q=Kyiv")
+		if err != nil {
+			t.Fatalf("failed to make request: %v", err)
+		}
+
+		if response.StatusCode != expectedStatus {
+			t.Fatalf("request %d: expected status %d, got %d", i, expectedStatus, response.StatusCode)
+		}
+		if expectedStatus == http.StatusTooManyRequests && response.Header.Get("Retry-After") == "" {
+			t.Fatalf("expected Retry-After header")
+		}
+	}
+}
+
+func TestServer_RateLimitTrustProxy(t *testing.T) {
+	cases := map[string]struct {
+		trustProxy       bool
+		expectedStatuses []int
+	}{
+		"must limit by remote address": {
+			trustProxy:       false,
+			expectedStatuses: []int{http.StatusOK, http.StatusTooManyRequests},
+		},
+		"must limit by forwarded client IP": {
+			trustProxy:       true,
+			expectedStatuses: []int{http.StatusOK, http.StatusOK},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			limitedServer := httptest.NewServer(NewServer(
+				nil,
+				weatherapi.NewMockWeatherProvider(),
+				subsMock.NewDatabase(subscriptionMock, notificationMock, alertRuleMock, subscriberMock, tokenMock),
+				mailMock,
+				logan.New().Level(logan.ErrorLevel),
+				ServerOpts{
+					CitySearchRateLimit: RateLimit{Rate: 0.01, Burst: 1},
+					TrustProxy:          tc.trustProxy,
+				},
+			).requestHandler())
+			defer limitedServer.Close()
+
+			// both requests come from the same proxy on behalf of different clients
+			clients := []string{"203.0.113.1", "203.0.113.2"}
+			for i, expectedStatus := range tc.expectedStatuses {
+				request, err := http.NewRequest(http.MethodGet, limitedServer.URL+"/api/cities/search?q=Kyiv", nil)
+				if err != nil {
+					t.Fatalf("failed to create request: %v", err)
+				}
+				request.Header.Set("X-Forwarded-For", clients[i])
+
+				response, err := http.DefaultClient.Do(request)
+				if err != nil {
+					t.Fatalf("failed to make request: %v", err)
+				}
+
+				if response.StatusCode != expectedStatus {
+					t.Fatalf("request %d: expected status %d, got %d", i, expectedStatus, response.StatusCode)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_Subscribe(t *testing.T) {
+	newYork := &weatherapi.Location{
+		Id:        "weatherapi:2618724",
+		Name:      "New York",
+		Region:    "New York",
+		Country:   "United States of America",
+		Latitude:  40.71,
+		Longitude: -74.01,
+		Timezone:  "America/New_York",
+	}
+	unverified := &database.Subscriber{Id: 2, Email: "max@gmail.com"}
+	verified := &database.Subscriber{Id: 2, Email: "max@gmail.com", Confirmed: true}
+	manageToken := "00000000000000000000000000000000"
+	coordinates := weatherapi.LocationQuery{Coordinates: &weatherapi.Coordinates{Latitude: 40.71, Longitude: -74.01}}
+
+	testCases := map[string]struct {
+		preparation    func()
+		call           func() (*http.Response, error)
+		expectedStatus int
+		cleanup        func()
+	}{
+		"must 400 (missing required fields)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (missing required fields in json body)": {
+			call: func() (*http.Response, error) {
+				return http.Post(server.URL+"/api/subscribe", "application/json", nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (invalid email)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"invalid-email"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (both city and coordinates)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"lat":       {"40.71"},
+					"lon":       {"-74.01"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (latitude out of range)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"lat":       {"140.71"},
+					"lon":       {"-74.01"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (delivery hour out of range)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":         {"max@gmail.com"},
+					"city":          {"New York"},
+					"frequency":     {"daily"},
+					"delivery_hour": {"24"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (unknown timezone)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+					"timezone":  {"Mars/Olympus_Mons"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (custom frequency without schedule)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"custom"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (schedule firing more often than hourly)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"custom"},
+					"schedule":  {"*/30 * * * *"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (schedule with predefined frequency)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+					"schedule":  {"0 7 * * *"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 409 (subscription already exists) ": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", unverified.Id, newYork.Name, newYork.Id).Return(&database.Subscription{
+					Id:        1,
+					Confirmed: true,
+				}, nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusConflict,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (unconfirmed subscription is reissued)": {
+			preparation: func() {
+				sentAt := time.Now().Add(-time.Hour)
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", unverified.Id, newYork.Name, newYork.Id).Return(&database.Subscription{
+					Id:                 1,
+					ConfirmationSentAt: &sentAt,
+				}, nil)
+				subscriptionMock.On("Update", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.Id == 1 && sub.ConfirmationSentAt != nil && sub.ConfirmationSentAt.After(sentAt)
+				})).Return(nil)
+				tokenMock.On("DeleteBySubscription", int64(1), database.TokenPurposeConfirmation).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeConfirmation && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				tokenMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 429 (confirmation sent recently)": {
+			preparation: func() {
+				sentAt := time.Now()
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", unverified.Id, newYork.Name, newYork.Id).Return(&database.Subscription{
+					Id:                 1,
+					ConfirmationSentAt: &sentAt,
+				}, nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusTooManyRequests,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertNotCalled(t, "SendConfirmationEmail", mock.Anything, mock.Anything)
+				resetMocks()
+			},
+		},
+		"must 404 (city not found error) ": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(nil, weatherapi.ErrCityNotFound)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusNotFound,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 500 (unknown error) ": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.Anything).Return(int64(0), errors.New("db error"))
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusInternalServerError,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 500 (email sending error) ": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.Anything).Return(int64(0), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(errors.New("error"))
+
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusInternalServerError,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (url val)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.LocationId == newYork.Id && sub.City == newYork.Name && sub.Timezone == newYork.Timezone &&
+						sub.DeliveryHour == database.DefaultDeliveryHour && sub.SubscriberId == unverified.Id && !sub.Confirmed
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeConfirmation && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (json body)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.LocationId == newYork.Id && sub.City == newYork.Name && sub.Timezone == newYork.Timezone
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				req, _ := json.Marshal(requests.SubscribeRequest{
+					Email:     "max@gmail.com",
+					City:      "New York",
+					Frequency: "daily",
+				})
+
+				return http.Post(server.URL+"/api/subscribe", "application/json", bytes.NewReader(req))
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 400 (unsupported language)": {
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+					"language":  {"de"},
+				})
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 200 (language from Accept-Language)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.Language == i18n.Ukrainian
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.MatchedBy(func(email mailer.ConfirmationEmail) bool {
+					return email.Language == i18n.Ukrainian
+				})).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				form := url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				}
+				req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/subscribe", strings.NewReader(form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				req.Header.Set("Accept-Language", "uk-UA,uk;q=0.9,en;q=0.8")
+
+				return http.DefaultClient.Do(req)
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (delivery hour and timezone)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.Timezone == "Europe/Kyiv" && sub.DeliveryHour == 6
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":         {"max@gmail.com"},
+					"city":          {"New York"},
+					"frequency":     {"daily"},
+					"delivery_hour": {"6"},
+					"timezone":      {"Europe/Kyiv"},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (custom schedule)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.Schedule == "0 7 * * MON-FRI" && sub.NextNotifyAt != nil && sub.NextNotifyAt.After(time.Now())
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				req, _ := json.Marshal(requests.SubscribeRequest{
+					Email:     "max@gmail.com",
+					City:      "New York",
+					Frequency: "custom",
+					Schedule:  "0 7 * * MON-FRI",
+				})
+
+				return http.Post(server.URL+"/api/subscribe", "application/json", bytes.NewReader(req))
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (weekly)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.Schedule == "0 9 * * 0"
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":         {"max@gmail.com"},
+					"city":          {"New York"},
+					"frequency":     {"weekly"},
+					"delivery_hour": {"9"},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (verified subscriber confirms location)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(verified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				// anyone may subscribe the verified address, so the confirmation is sent as usual
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.SubscriberId == 2 && !sub.Confirmed && sub.ConfirmationSentAt != nil
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeConfirmation && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriberMock.AssertExpectations(t)
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (token of verified subscriber adds location)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(verified, nil)
+				subscriptionMock.On("GetByToken", manageToken, database.TokenPurposeUnsubscribe).
+					Return(&database.Subscription{Id: 5, SubscriberId: 2, Confirmed: true}, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.SubscriberId == 2 && sub.Confirmed && sub.ConfirmationSentAt == nil
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeUnsubscribe && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationSuccessEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+					"token":     {manageToken},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriberMock.AssertExpectations(t)
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 403 (token of another subscriber)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(verified, nil)
+				subscriptionMock.On("GetByToken", manageToken, database.TokenPurposeUnsubscribe).
+					Return(&database.Subscription{Id: 5, SubscriberId: 3, Confirmed: true}, nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+					"token":     {manageToken},
+				})
+			},
+			expectedStatus: http.StatusForbidden,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertNotCalled(t, "SendConfirmationSuccessEmail", mock.Anything, mock.Anything)
+				resetMocks()
+			},
+		},
+		"must 403 (unknown token)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "New York"}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(verified, nil)
+				subscriptionMock.On("GetByToken", manageToken, database.TokenPurposeUnsubscribe).Return(nil, nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"city":      {"New York"},
+					"frequency": {"daily"},
+					"token":     {manageToken},
+				})
+			},
+			expectedStatus: http.StatusForbidden,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (coordinates)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, coordinates).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.Anything).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				return http.PostForm(server.URL+"/api/subscribe", url.Values{
+					"email":     {"max@gmail.com"},
+					"lat":       {"40.71"},
+					"lon":       {"-74.01"},
+					"frequency": {"hourly"},
+				})
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+		"must 200 (location id)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Id: newYork.Id}).Return(newYork, nil)
+				subscriberMock.On("GetOrInsert", "max@gmail.com").Return(unverified, nil)
+				subscriptionMock.On("GetByLocation", int64(2), newYork.Name, newYork.Id).Return(nil, nil)
+				subscriptionMock.On("Insert", mock.Anything).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			call: func() (*http.Response, error) {
+				req, _ := json.Marshal(requests.SubscribeRequest{
+					Email:      "max@gmail.com",
+					LocationId: newYork.Id,
+					Frequency:  "daily",
+				})
+
+				return http.Post(server.URL+"/api/subscribe", "application/json", bytes.NewReader(req))
+			},
+			expectedStatus: http.StatusOK,
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				weatherMock.AssertExpectations(t)
+				resetMocks()
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			response, err := tc.call()
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if tc.cleanup != nil {
+				tc.cleanup()
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_Confirm(t *testing.T) {
+	validToken := "00000000000000000000000000000000"
+	testCases := map[string]struct {
+		preparation    func()
+		cleanup        func()
+		token          string
+		expectedStatus int
+	}{
+		"must 400 (invalid token)": {
+			expectedStatus: http.StatusBadRequest,
+			token:          "awe",
+		},
+		"must 400 (subscription already confirmed)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(&database.Subscription{
+					Confirmed: true,
+				}, nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 404 (token not found)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(nil, nil)
+				alertRuleMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(nil, nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				alertRuleMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 400 (alert rule already confirmed)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(nil, nil)
+				alertRuleMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(&database.AlertRule{Confirmed: true}, nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				alertRuleMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 200 (alert rule)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(nil, nil)
+				alertRuleMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(&database.AlertRule{
+					Id:    2,
+					Email: "max@gmail.com",
+				}, nil)
+				alertRuleMock.On("UpdateConfirmed", int64(2)).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeUnsubscribe && *token.AlertRuleId == 2
+				})).Return(nil)
+				mailMock.On("SendConfirmationSuccessEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				alertRuleMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusOK,
+		},
+		"must 410 (confirmation token expired)": {
+			preparation: func() {
+				sentAt := time.Now().Add(-48 * time.Hour)
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(&database.Subscription{
+					Id:                 1,
+					Confirmed:          false,
+					ConfirmationSentAt: &sentAt,
+				}, nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				subscriptionMock.AssertNotCalled(t, "UpdateConfirmed", mock.Anything)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusGone,
+		},
+		"must 410 (alert rule confirmation token expired)": {
+			preparation: func() {
+				sentAt := time.Now().Add(-48 * time.Hour)
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(nil, nil)
+				alertRuleMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(&database.AlertRule{
+					Id:                 2,
+					Email:              "max@gmail.com",
+					ConfirmationSentAt: &sentAt,
+				}, nil)
+			},
+			cleanup: func() {
+				alertRuleMock.AssertExpectations(t)
+				alertRuleMock.AssertNotCalled(t, "UpdateConfirmed", mock.Anything)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusGone,
+		},
+		"must 500 (mail sending error)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(&database.Subscription{
+					Id:           1,
+					SubscriberId: 2,
+					Confirmed:    false,
+					Email:        "max@gmail.com",
+				}, nil)
+				subscriptionMock.On("UpdateConfirmed", int64(1)).Return(nil)
+				subscriberMock.On("UpdateConfirmed", int64(2)).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeUnsubscribe && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationSuccessEmail", "max@gmail.com", mock.Anything).Return(errors.New("error"))
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"must 200": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeConfirmation).Return(&database.Subscription{
+					Id:           1,
+					SubscriberId: 2,
+					Confirmed:    false,
+					Email:        "max@gmail.com",
+				}, nil)
+				subscriptionMock.On("UpdateConfirmed", int64(1)).Return(nil)
+				subscriberMock.On("UpdateConfirmed", int64(2)).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeUnsubscribe && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationSuccessEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				subscriberMock.AssertExpectations(t)
+				tokenMock.AssertExpectations(t)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			response, err := http.Get(server.URL + "/api/confirm/" + tc.token)
+			if tc.cleanup != nil {
+				tc.cleanup()
+			}
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_ResendConfirmation(t *testing.T) {
+	subscriber := &database.Subscriber{Id: 2, Email: "max@gmail.com"}
+	recently, longAgo := time.Now(), time.Now().Add(-time.Hour)
+	testCases := map[string]struct {
+		preparation    func()
+		cleanup        func()
+		email          string
+		expectedStatus int
+	}{
+		"must 400 (invalid email)": {
+			email:          "invalid-email",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 200 (unknown email)": {
+			preparation: func() {
+				subscriberMock.On("GetByEmail", "max@gmail.com").Return(nil, nil)
+				alertRuleMock.On("SelectUnconfirmed", "max@gmail.com").Return(nil, nil)
+			},
+			cleanup: func() {
+				subscriberMock.AssertExpectations(t)
+				subscriptionMock.AssertNotCalled(t, "SelectUnconfirmed", mock.Anything)
+				mailMock.AssertNotCalled(t, "SendConfirmationEmail", mock.Anything, mock.Anything)
+				resetMocks()
+			},
+			email:          "max@gmail.com",
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (recently sent confirmation is skipped)": {
+			preparation: func() {
+				subscriberMock.On("GetByEmail", "max@gmail.com").Return(subscriber, nil)
+				subscriptionMock.On("SelectUnconfirmed", subscriber.Id).Return([]database.Subscription{
+					{Id: 1, Email: "max@gmail.com", City: "Kyiv", ConfirmationSentAt: &longAgo},
+					{Id: 2, Email: "max@gmail.com", City: "Lviv", ConfirmationSentAt: &recently},
+				}, nil)
+				tokenMock.On("DeleteBySubscription", int64(1), database.TokenPurposeConfirmation).Return(nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				subscriptionMock.On("UpdateConfirmationSent", int64(1), mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.MatchedBy(func(email mailer.ConfirmationEmail) bool {
+					return email.City == "Kyiv"
+				})).Return(nil).Once()
+				alertRuleMock.On("SelectUnconfirmed", "max@gmail.com").Return(nil, nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				tokenMock.AssertNotCalled(t, "DeleteBySubscription", int64(2), mock.Anything)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+			email:          "max@gmail.com",
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (alert rules are resent)": {
+			preparation: func() {
+				subscriberMock.On("GetByEmail", "max@gmail.com").Return(nil, nil)
+				alertRuleMock.On("SelectUnconfirmed", "max@gmail.com").Return([]database.AlertRule{
+					{Id: 3, Email: "max@gmail.com", City: "Odesa", ConfirmationSentAt: &longAgo},
+					{Id: 4, Email: "max@gmail.com", City: "Dnipro", ConfirmationSentAt: &recently},
+				}, nil)
+				tokenMock.On("DeleteByAlertRule", int64(3), database.TokenPurposeConfirmation).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeConfirmation && *token.AlertRuleId == 3
+				})).Return(nil)
+				alertRuleMock.On("UpdateConfirmationSent", int64(3), mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.MatchedBy(func(email mailer.ConfirmationEmail) bool {
+					return email.City == "Odesa"
+				})).Return(nil).Once()
+			},
+			cleanup: func() {
+				alertRuleMock.AssertExpectations(t)
+				tokenMock.AssertExpectations(t)
+				tokenMock.AssertNotCalled(t, "DeleteByAlertRule", int64(4), mock.Anything)
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+			email:          "max@gmail.com",
+			expectedStatus: http.StatusOK,
+		},
+		"must 500 (mail sending error)": {
+			preparation: func() {
+				subscriberMock.On("GetByEmail", "max@gmail.com").Return(subscriber, nil)
+				subscriptionMock.On("SelectUnconfirmed", subscriber.Id).Return([]database.Subscription{
+					{Id: 1, Email: "max@gmail.com", City: "Kyiv", ConfirmationSentAt: &longAgo},
+				}, nil)
+				tokenMock.On("DeleteBySubscription", int64(1), database.TokenPurposeConfirmation).Return(nil)
+				tokenMock.On("Insert", mock.Anything).Return(nil)
+				subscriptionMock.On("UpdateConfirmationSent", int64(1), mock.Anything).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(errors.New("error"))
+			},
+			cleanup: func() {
+				mailMock.AssertExpectations(t)
+				resetMocks()
+			},
+			email:          "max@gmail.com",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			response, err := http.PostForm(server.URL+"/api/subscribe/resend", url.Values{"email": {tc.email}})
+			if tc.cleanup != nil {
+				tc.cleanup()
+			}
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_Unsubscribe(t *testing.T) {
+	validToken := "00000000000000000000000000000000"
+	subscription := &database.Subscription{Id: 1, SubscriberId: 2, Email: "max@gmail.com", Confirmed: true}
+	testCases := map[string]struct {
+		preparation    func()
+		cleanup        func()
+		token          string
+		expectedStatus int
+	}{
+		"must 400 (invalid token)": {
+			expectedStatus: http.StatusBadRequest,
+			token:          "awe",
+		},
+		"must 404 (token not found)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(nil, nil)
+				alertRuleMock.On("DeleteByToken", validToken).Return(database.ErrNoRowsAffected)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				alertRuleMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 200 (alert rule)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(nil, nil)
+				alertRuleMock.On("DeleteByToken", validToken).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				alertRuleMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusOK,
+		},
+		"must 500 (unknown error)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(subscription, nil)
+				subscriptionMock.On("DeleteByToken", validToken).Return(errors.New("error"))
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"must 200 (signed token of List-Unsubscribe)": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(subscription, nil)
+				subscriptionMock.On("Delete", int64(1)).Return(nil)
+				subscriberMock.On("DeleteUnused", subscription.SubscriberId).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				subscriptionMock.AssertNotCalled(t, "GetByToken", mock.Anything, mock.Anything)
+				subscriberMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          unsubscribeLinks.Token(unsubscribe.Target{Kind: unsubscribe.KindSubscription, Id: 1}),
+			expectedStatus: http.StatusOK,
+		},
+		"must 404 (forged signed token)": {
+			token:          unsubscribe.NewLinks("other-key", "").Token(unsubscribe.Target{Kind: unsubscribe.KindSubscription, Id: 1}),
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 200 (subscriber keeps other subscriptions)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(subscription, nil)
+				subscriptionMock.On("DeleteByToken", validToken).Return(nil)
+				subscriberMock.On("DeleteUnused", subscription.SubscriberId).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				subscriberMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          validToken,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			response, err := http.Get(server.URL + "/api/unsubscribe/" + tc.token)
+			if tc.cleanup != nil {
+				tc.cleanup()
+			}
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_OneClickUnsubscribe(t *testing.T) {
+	var (
+		storedToken       = "00000000000000000000000000000000"
+		subscriptionToken = unsubscribeLinks.Token(unsubscribe.Target{Kind: unsubscribe.KindSubscription, Id: 1})
+		subscriberToken   = unsubscribeLinks.Token(unsubscribe.Target{Kind: unsubscribe.KindSubscriber, Id: 2})
+		alertRuleToken    = unsubscribeLinks.Token(unsubscribe.Target{Kind: unsubscribe.KindAlertRule, Id: 3})
+		forgedToken       = unsubscribe.NewLinks("other-key", "").Token(unsubscribe.Target{Kind: unsubscribe.KindSubscription, Id: 1})
+		oneClickBody      = unsubscribe.OneClickBody
+		subscription      = &database.Subscription{Id: 1, SubscriberId: 2, Email: "max@gmail.com", Confirmed: true}
+	)
+	testCases := map[string]struct {
+		preparation    func()
+		cleanup        func()
+		token          string
+		body           string
+		expectedStatus int
+	}{
+		"must 400 (invalid token)": {
+			token:          "s-1.awe",
+			body:           oneClickBody,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (missing one-click body)": {
+			token:          subscriptionToken,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (invalid one-click body)": {
+			token:          subscriptionToken,
+			body:           "List-Unsubscribe=Yes",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 404 (forged signature)": {
+			token:          forgedToken,
+			body:           oneClickBody,
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 200 (subscription)": {
+			preparation: func() {
+				subscriptionMock.On("GetById", subscription.Id).Return(subscription, nil)
+				subscriptionMock.On("Delete", subscription.Id).Return(nil)
+				subscriberMock.On("DeleteUnused", subscription.SubscriberId).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				subscriberMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          subscriptionToken,
+			body:           oneClickBody,
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (subscription is already deleted)": {
+			preparation: func() {
+				subscriptionMock.On("GetById", subscription.Id).Return(nil, nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          subscriptionToken,
+			body:           oneClickBody,
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (all subscriptions of subscriber)": {
+			preparation: func() {
+				subscriptionMock.On("DeleteBySubscriber", int64(2)).Return(int64(3), nil)
+				subscriberMock.On("DeleteUnused", int64(2)).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				subscriberMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          subscriberToken,
+			body:           oneClickBody,
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (alert rule is already deleted)": {
+			preparation: func() {
+				alertRuleMock.On("Delete", int64(3)).Return(database.ErrNoRowsAffected)
+			},
+			cleanup: func() {
+				alertRuleMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          alertRuleToken,
+			body:           oneClickBody,
+			expectedStatus: http.StatusOK,
+		},
+		"must 500 (unknown error)": {
+			preparation: func() {
+				alertRuleMock.On("Delete", int64(3)).Return(errors.New("error"))
+			},
+			cleanup: func() {
+				alertRuleMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          alertRuleToken,
+			body:           oneClickBody,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"must 200 (stored unsubscribe token)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", storedToken, database.TokenPurposeUnsubscribe).Return(subscription, nil)
+				subscriptionMock.On("DeleteByToken", storedToken).Return(nil)
+				subscriberMock.On("DeleteUnused", subscription.SubscriberId).Return(nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				subscriberMock.AssertExpectations(t)
+				resetMocks()
+			},
+			token:          storedToken,
+			body:           oneClickBody,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			response, err := http.Post(
+				server.URL+"/api/unsubscribe/"+tc.token,
+				"application/x-www-form-urlencoded",
+				bytes.NewBufferString(tc.body),
+			)
+			if tc.cleanup != nil {
+				tc.cleanup()
+			}
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_CreateAlert(t *testing.T) {
+	kyiv := &weatherapi.Location{
+		Id:       "weatherapi:2",
+		Name:     "Kyiv",
+		Country:  "Ukraine",
+		Timezone: "Europe/Kyiv",
+	}
+	validForm := func() url.Values {
+		return url.Values{
+			"email":     {"max@gmail.com"},
+			"city":      {"Kyiv"},
+			"metric":    {"rain_chance"},
+			"operator":  {"above"},
+			"threshold": {"70"},
+			"day":       {"tomorrow"},
+		}
+	}
+	withForm := func(modify func(form url.Values)) url.Values {
+		form := validForm()
+		modify(form)
+		return form
+	}
+	recently, longAgo := time.Now(), time.Now().Add(-time.Hour)
+
+	testCases := map[string]struct {
+		preparation    func()
+		form           url.Values
+		expectedStatus int
+	}{
+		"must 400 (unknown metric)": {
+			form:           withForm(func(form url.Values) { form.Set("metric", "humidity") }),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (unknown operator)": {
+			form:           withForm(func(form url.Values) { form.Set("operator", "equals") }),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (missing threshold)": {
+			form:           withForm(func(form url.Values) { form.Del("threshold") }),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (rain chance out of range)": {
+			form:           withForm(func(form url.Values) { form.Set("threshold", "170") }),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (unknown day)": {
+			form:           withForm(func(form url.Values) { form.Set("day", "yesterday") }),
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 404 (city not found)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "Kyiv"}).Return(nil, weatherapi.ErrCityNotFound)
+			},
+			form:           validForm(),
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 409 (rule already exists)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "Kyiv"}).Return(kyiv, nil)
+				alertRuleMock.On("GetByCondition", mock.Anything).Return(&database.AlertRule{Id: 1, Confirmed: true}, nil)
+			},
+			form:           validForm(),
+			expectedStatus: http.StatusConflict,
+		},
+		"must 429 (confirmation of unconfirmed rule sent recently)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "Kyiv"}).Return(kyiv, nil)
+				alertRuleMock.On("GetByCondition", mock.Anything).Return(&database.AlertRule{Id: 1, ConfirmationSentAt: &recently}, nil)
+			},
+			form:           validForm(),
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		"must 200 (confirmation of unconfirmed rule reissued)": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "Kyiv"}).Return(kyiv, nil)
+				alertRuleMock.On("GetByCondition", mock.Anything).Return(&database.AlertRule{Id: 1, ConfirmationSentAt: &longAgo}, nil)
+				alertRuleMock.On("UpdateConfirmationSent", int64(1), mock.Anything).Return(nil)
+				tokenMock.On("DeleteByAlertRule", int64(1), database.TokenPurposeConfirmation).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeConfirmation && *token.AlertRuleId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			form:           validForm(),
+			expectedStatus: http.StatusOK,
+		},
+		"must 200": {
+			preparation: func() {
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "Kyiv"}).Return(kyiv, nil)
+				alertRuleMock.On("GetByCondition", mock.Anything).Return(nil, nil)
+				alertRuleMock.On("Insert", mock.MatchedBy(func(rule database.AlertRule) bool {
+					return rule.LocationId == kyiv.Id &&
+						rule.Metric == database.AlertMetricRainChance &&
+						rule.Operator == database.AlertOperatorAbove &&
+						rule.Threshold == 70 &&
+						rule.DayOffset == 1 &&
+						rule.ConfirmationSentAt != nil
+				})).Return(int64(1), nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeConfirmation && *token.AlertRuleId == 1 && len(token.Hash) == 64
+				})).Return(nil)
+				mailMock.On("SendConfirmationEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			form:           validForm(),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			response, err := http.PostForm(server.URL+"/api/alerts", tc.form)
+
+			weatherMock.AssertExpectations(t)
+			alertRuleMock.AssertExpectations(t)
+			tokenMock.AssertExpectations(t)
+			mailMock.AssertExpectations(t)
+			resetMocks()
+
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_AdminListSuspended(t *testing.T) {
+	suspendedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	lastError := "failed to send email: mailbox does not exist"
+	suspended := database.Subscription{
+		Id:                  1,
+		Email:               "max@gmail.com",
+		City:                "London",
+		Frequency:           database.SubscriptionFrequencyDaily,
+		Confirmed:           true,
+		CreatedAt:           suspendedAt.Add(-time.Hour),
+		ConsecutiveFailures: 5,
+		LastError:           &lastError,
+		SuspendedAt:         &suspendedAt,
+	}
+
+	testCases := map[string]struct {
+		preparation    func()
+		cleanup        func()
+		apiKey         string
+		expectedStatus int
+		response       *responses.AdminSubscriptionsResponse
+	}{
+		"must 401 (missing api key)": {
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"must 401 (invalid api key)": {
+			apiKey:         "invalid",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"must 500 (db error)": {
+			preparation: func() {
+				subscriptionMock.On("SelectSuspended").Return(nil, errors.New("db error"))
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+			apiKey:         adminApiKey,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"must 200 (valid response)": {
+			preparation: func() {
+				subscriptionMock.On("SelectSuspended").Return([]database.Subscription{suspended}, nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+			apiKey:         adminApiKey,
+			expectedStatus: http.StatusOK,
+			response: &responses.AdminSubscriptionsResponse{
+				Subscriptions: []responses.AdminSubscriptionResponse{responses.NewAdminSubscriptionResponse(suspended)},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/admin/subscriptions/suspended", nil)
+			if tc.apiKey != "" {
+				req.Header.Set("X-API-Key", tc.apiKey)
+			}
+
+			response, err := http.DefaultClient.Do(req)
+			if tc.cleanup != nil {
+				tc.cleanup()
+			}
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+
+			if tc.response != nil {
+				var resp responses.AdminSubscriptionsResponse
+				if err = json.NewDecoder(response.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+
+				if !reflect.DeepEqual(resp, *tc.response) {
+					t.Fatalf("expected response %+v, got %+v", *tc.response, resp)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_AdminListSubscriptions(t *testing.T) {
+	boolPtr := func(b bool) *bool {
+		return &b
+	}
+	subscription := database.Subscription{
+		Id:        1,
+		Email:     "max@gmail.com",
+		City:      "London",
+		Frequency: database.SubscriptionFrequencyDaily,
+		Confirmed: true,
+		CreatedAt: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	testCases := map[string]struct {
+		preparation    func()
+		cleanup        func()
+		apiKey         string
+		query          string
+		expectedStatus int
+		response       *responses.AdminSubscriptionsPageResponse
+	}{
+		"must 401 (invalid api key)": {
+			apiKey:         "invalid",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		"must 400 (invalid confirmed filter)": {
+			apiKey:         adminApiKey,
+			query:          "?confirmed=maybe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (limit too big)": {
+			apiKey:         adminApiKey,
+			query:          "?limit=1000",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 500 (db error)": {
+			preparation: func() {
+				subscriptionMock.On("Select", database.SubscriptionsFilter{}, uint64(20), uint64(0)).Return(nil, errors.New("db error"))
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+			apiKey:         adminApiKey,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"must 200 (filtered page)": {
+			preparation: func() {
+				filter := database.SubscriptionsFilter{Search: "max", Confirmed: boolPtr(true), Suspended: boolPtr(false)}
+				subscriptionMock.On("Select", filter, uint64(10), uint64(10)).Return([]database.Subscription{subscription}, nil)
+				subscriptionMock.On("Count", filter).Return(int64(11), nil)
+			},
+			cleanup: func() {
+				subscriptionMock.AssertExpectations(t)
+				resetMocks()
+			},
+			apiKey:         "another-admin-api-key",
+			query:          "?search=max&confirmed=true&suspended=false&limit=10&offset=10",
+			expectedStatus: http.StatusOK,
+			response: &responses.AdminSubscriptionsPageResponse{
+				AdminSubscriptionsResponse: responses.NewAdminSubscriptionsResponse([]database.Subscription{subscription}),
+				Total:                      11,
+				Limit:                      10,
+				Offset:                     10,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/admin/subscriptions"+tc.query, nil)
+			req.Header.Set("X-API-Key", tc.apiKey)
+
+			response, err := http.DefaultClient.Do(req)
+			if tc.cleanup != nil {
+				tc.cleanup()
+			}
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+
+			if tc.response != nil {
+				var resp responses.AdminSubscriptionsPageResponse
+				if err = json.NewDecoder(response.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+
+				if !reflect.DeepEqual(resp, *tc.response) {
+					t.Fatalf("expected response %+v, got %+v", *tc.response, resp)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_AdminGetSubscription(t *testing.T) {
+	createdAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	subscription := database.Subscription{
+		Id:        1,
+		Email:     "max@gmail.com",
+		City:      "London",
+		Frequency: database.SubscriptionFrequencyHourly,
+		Confirmed: true,
+		CreatedAt: createdAt,
+	}
+	subscriptionId := subscription.Id
+	notification := database.Notification{
+		Id:             10,
+		SubscriptionId: &subscriptionId,
+		IdempotencyKey: "1:hourly:2025-06-01T11:00:00Z",
+		Subject:        mailer.EmailSubjectNotification,
+		Status:         database.NotificationStatusSent,
+		Attempts:       1,
+		CreatedAt:      createdAt.Add(time.Hour),
+		NextAttemptAt:  createdAt.Add(time.Hour),
+	}
+
+	testCases := map[string]struct {
+		preparation    func()
+		cleanup        func()
+		id             string
+		expectedStatus int
+		response       *responses.AdminSubscriptionDetailsResponse
+	}{
+		"must 400 (invalid id)": {
+			id:             "abc",
+			expectedStatus: http.StatusBadRequest,
 		},
-		"must 404 (token not found)": {
+		"must 404 (not found)": {
 			preparation: func() {
-				subscriptionMock.On("DeleteByToken", validToken).Return(database.ErrNoRowsAffected)
+				subscriptionMock.On("GetById", int64(2)).Return(nil, nil)
 			},
 			cleanup: func() {
 				subscriptionMock.AssertExpectations(t)
 				resetMocks()
 			},
-			token:          validToken,
+			id:             "2",
 			expectedStatus: http.StatusNotFound,
 		},
-		"must 500 (unknown error)": {
+		"must 500 (history error)": {
 			preparation: func() {
-				subscriptionMock.On("DeleteByToken", validToken).Return(errors.New("error"))
+				subscriptionMock.On("GetById", int64(1)).Return(&subscription, nil)
+				notificationMock.On("SelectBySubscription", int64(1), uint64(20)).Return(nil, errors.New("db error"))
 			},
 			cleanup: func() {
 				subscriptionMock.AssertExpectations(t)
+				notificationMock.AssertExpectations(t)
 				resetMocks()
 			},
-			token:          validToken,
+			id:             "1",
 			expectedStatus: http.StatusInternalServerError,
 		},
-		"must 200": {
+		"must 200 (with delivery history)": {
 			preparation: func() {
-				subscriptionMock.On("DeleteByToken", validToken).Return(nil)
+				subscriptionMock.On("GetById", int64(1)).Return(&subscription, nil)
+				notificationMock.On("SelectBySubscription", int64(1), uint64(20)).Return([]database.Notification{notification}, nil)
 			},
 			cleanup: func() {
 				subscriptionMock.AssertExpectations(t)
+				notificationMock.AssertExpectations(t)
 				resetMocks()
 			},
-			token:          validToken,
+			id:             "1",
 			expectedStatus: http.StatusOK,
+			response: &responses.AdminSubscriptionDetailsResponse{
+				Subscription: responses.NewAdminSubscriptionResponse(subscription),
+				Deliveries: []responses.AdminDeliveryResponse{{
+					Id:             notification.Id,
+					IdempotencyKey: notification.IdempotencyKey,
+					Subject:        notification.Subject,
+					Status:         string(database.NotificationStatusSent),
+					Attempts:       1,
+					CreatedAt:      notification.CreatedAt,
+					NextAttemptAt:  notification.NextAttemptAt,
+				}},
+			},
 		},
 	}
 
@@ -482,7 +2172,10 @@ func TestServer_Unsubscribe(t *testing.T) {
 				tc.preparation()
 			}
 
-			response, err := http.Get(server.URL + "/api/unsubscribe/" + tc.token)
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/admin/subscriptions/"+tc.id, nil)
+			req.Header.Set("X-API-Key", adminApiKey)
+
+			response, err := http.DefaultClient.Do(req)
 			if tc.cleanup != nil {
 				tc.cleanup()
 			}
@@ -493,6 +2186,394 @@ func TestServer_Unsubscribe(t *testing.T) {
 			if response.StatusCode != tc.expectedStatus {
 				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
 			}
+
+			if tc.response != nil {
+				var resp responses.AdminSubscriptionDetailsResponse
+				if err = json.NewDecoder(response.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+
+				if !reflect.DeepEqual(resp, *tc.response) {
+					t.Fatalf("expected response %+v, got %+v", *tc.response, resp)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_AdminSubscriptionActions(t *testing.T) {
+	suspendedAt := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	active := database.Subscription{Id: 1, SubscriberId: 2, Email: "max@gmail.com", City: "London", Confirmed: true}
+	unconfirmed := database.Subscription{Id: 1, SubscriberId: 2, Email: "max@gmail.com", City: "London"}
+	suspended := database.Subscription{Id: 1, SubscriberId: 2, Email: "max@gmail.com", City: "London", Confirmed: true, SuspendedAt: &suspendedAt}
+
+	testCases := map[string]struct {
+		preparation    func()
+		method         string
+		path           string
+		expectedStatus int
+	}{
+		"must 404 (unknown subscription)": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(nil, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/1/suspend",
+			expectedStatus: http.StatusNotFound,
+		},
+		"confirm must 409 (already confirmed)": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&active, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/1/confirm",
+			expectedStatus: http.StatusConflict,
+		},
+		"confirm must 204": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&unconfirmed, nil)
+				subscriptionMock.On("UpdateConfirmed", int64(1)).Return(nil)
+				subscriberMock.On("UpdateConfirmed", int64(2)).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeUnsubscribe && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationSuccessEmail", "max@gmail.com", mock.Anything).Return(nil)
+			},
+			method:         http.MethodPost,
+			path:           "/1/confirm",
+			expectedStatus: http.StatusNoContent,
+		},
+		"suspend must 204": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&active, nil)
+				subscriptionMock.On("UpdateSuspended", int64(1), mock.AnythingOfType("*time.Time")).Return(nil)
+			},
+			method:         http.MethodPost,
+			path:           "/1/suspend",
+			expectedStatus: http.StatusNoContent,
+		},
+		"resume must 204": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&suspended, nil)
+				subscriptionMock.On("UpdateSuspended", int64(1), (*time.Time)(nil)).Return(nil)
+				subscriptionMock.On("ResetFailures", int64(1)).Return(nil)
+			},
+			method:         http.MethodPost,
+			path:           "/1/resume",
+			expectedStatus: http.StatusNoContent,
+		},
+		"delete must 204": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&active, nil)
+				subscriptionMock.On("Delete", int64(1)).Return(nil)
+				subscriberMock.On("DeleteUnused", int64(2)).Return(nil)
+			},
+			method:         http.MethodDelete,
+			path:           "/1",
+			expectedStatus: http.StatusNoContent,
+		},
+		"notify must 409 (suspended)": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&suspended, nil)
+			},
+			method:         http.MethodPost,
+			path:           "/1/notify",
+			expectedStatus: http.StatusConflict,
+		},
+		"notify must 500 (db error)": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&active, nil)
+				subscriptionMock.On("UpdateNotifyNow", int64(1)).Return(errors.New("db error"))
+			},
+			method:         http.MethodPost,
+			path:           "/1/notify",
+			expectedStatus: http.StatusInternalServerError,
+		},
+		"notify must 202": {
+			preparation: func() {
+				subscriptionMock.On("GetById", int64(1)).Return(&active, nil)
+				subscriptionMock.On("UpdateNotifyNow", int64(1)).Return(nil)
+			},
+			method:         http.MethodPost,
+			path:           "/1/notify",
+			expectedStatus: http.StatusAccepted,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tc.preparation()
+
+			req, _ := http.NewRequest(tc.method, server.URL+"/admin/subscriptions"+tc.path, nil)
+			req.Header.Set("X-API-Key", adminApiKey)
+
+			response, err := http.DefaultClient.Do(req)
+
+			subscriptionMock.AssertExpectations(t)
+			subscriberMock.AssertExpectations(t)
+			tokenMock.AssertExpectations(t)
+			mailMock.AssertExpectations(t)
+			resetMocks()
+
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_GetSubscription(t *testing.T) {
+	validToken := "00000000000000000000000000000000"
+	testCases := map[string]struct {
+		preparation    func()
+		token          string
+		expectedStatus int
+	}{
+		"must 400 (invalid token)": {
+			token:          "awe",
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 404 (token not found)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(nil, nil)
+			},
+			token:          validToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 404 (unconfirmed subscription)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(&database.Subscription{Confirmed: false}, nil)
+			},
+			token:          validToken,
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 200": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(&database.Subscription{
+					Email:     "max@gmail.com",
+					City:      "Kyiv",
+					Frequency: database.SubscriptionFrequencyDaily,
+					Confirmed: true,
+				}, nil)
+			},
+			token:          validToken,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			response, err := http.Get(server.URL + "/api/subscriptions/" + tc.token)
+			subscriptionMock.AssertExpectations(t)
+			resetMocks()
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+			if tc.expectedStatus == http.StatusOK {
+				var body responses.SubscriptionResponse
+				if err = json.NewDecoder(response.Body).Decode(&body); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if body.Email != "max@gmail.com" || body.City != "Kyiv" || body.Paused {
+					t.Fatalf("unexpected response: %+v", body)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_UpdateSubscription(t *testing.T) {
+	validToken := "00000000000000000000000000000000"
+	newSubscription := func() *database.Subscription {
+		return &database.Subscription{
+			Id:           1,
+			SubscriberId: 2,
+			Email:        "max@gmail.com",
+			City:         "Kyiv",
+			Timezone:     "Europe/Kyiv",
+			Frequency:    database.SubscriptionFrequencyDaily,
+			DeliveryHour: 8,
+			Schedule:     "0 8 * * *",
+			Confirmed:    true,
+		}
+	}
+	lviv := &weatherapi.Location{Id: "weatherapi:3", Name: "Lviv", Country: "Ukraine", Timezone: "Europe/Kyiv"}
+
+	testCases := map[string]struct {
+		preparation    func()
+		body           string
+		expectedStatus int
+	}{
+		"must 400 (invalid body)": {
+			body:           `{"frequency": "yearly"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (custom frequency without schedule)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(newSubscription(), nil)
+			},
+			body:           `{"frequency": "custom"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 400 (schedule with daily frequency)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(newSubscription(), nil)
+			},
+			body:           `{"schedule": "0 7 * * MON-FRI"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		"must 404 (token not found)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(nil, nil)
+			},
+			body:           `{"frequency": "hourly"}`,
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 409 (location already followed)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(newSubscription(), nil)
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "Lviv"}).Return(lviv, nil)
+				subscriptionMock.On("Update", mock.Anything).Return(database.ErrSubscriptionExists)
+			},
+			body:           `{"city": "Lviv"}`,
+			expectedStatus: http.StatusConflict,
+		},
+		"must 200 (location and frequency)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(newSubscription(), nil)
+				weatherMock.On("ResolveLocation", mock.Anything, weatherapi.LocationQuery{Name: "Lviv"}).Return(lviv, nil)
+				subscriptionMock.On("Update", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.City == "Lviv" && sub.LocationId == lviv.Id && sub.Frequency == database.SubscriptionFrequencyCustom &&
+						sub.Schedule == "0 7 * * MON-FRI" && sub.NextNotifyAt != nil && sub.Confirmed
+				})).Return(nil)
+			},
+			body:           `{"city": "Lviv", "frequency": "custom", "schedule": "0 7 * * MON-FRI"}`,
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (pause)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(newSubscription(), nil)
+				subscriptionMock.On("Update", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.PausedAt != nil && sub.Schedule == "0 8 * * *"
+				})).Return(nil)
+			},
+			body:           `{"paused": true}`,
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (resume)": {
+			preparation: func() {
+				paused := newSubscription()
+				pausedAt := time.Now().Add(-time.Hour)
+				paused.PausedAt = &pausedAt
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(paused, nil)
+				subscriptionMock.On("Update", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.PausedAt == nil && sub.NextNotifyAt.After(time.Now())
+				})).Return(nil)
+			},
+			body:           `{"paused": false}`,
+			expectedStatus: http.StatusOK,
+		},
+		"must 200 (email change requires confirmation)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(newSubscription(), nil)
+				subscriberMock.On("GetOrInsert", "ann@gmail.com").Return(&database.Subscriber{Id: 3, Email: "ann@gmail.com", Confirmed: true}, nil)
+				subscriptionMock.On("Update", mock.MatchedBy(func(sub database.Subscription) bool {
+					return sub.SubscriberId == 3 && !sub.Confirmed
+				})).Return(nil)
+				subscriberMock.On("DeleteUnused", int64(2)).Return(nil)
+				tokenMock.On("DeleteBySubscription", int64(1), database.TokenPurposeUnsubscribe).Return(nil)
+				tokenMock.On("DeleteBySubscription", int64(1), database.TokenPurposeConfirmation).Return(nil)
+				tokenMock.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeConfirmation && *token.SubscriptionId == 1
+				})).Return(nil)
+				mailMock.On("SendConfirmationEmail", "ann@gmail.com", mock.Anything).Return(nil)
+			},
+			body:           `{"email": "ann@gmail.com"}`,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.preparation != nil {
+				tc.preparation()
+			}
+
+			req, _ := http.NewRequest(http.MethodPatch, server.URL+"/api/subscriptions/"+validToken, bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			response, err := http.DefaultClient.Do(req)
+
+			subscriptionMock.AssertExpectations(t)
+			subscriberMock.AssertExpectations(t)
+			tokenMock.AssertExpectations(t)
+			weatherMock.AssertExpectations(t)
+			mailMock.AssertExpectations(t)
+			resetMocks()
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
+		})
+	}
+}
+
+func TestServer_DeleteSubscription(t *testing.T) {
+	validToken := "00000000000000000000000000000000"
+	testCases := map[string]struct {
+		preparation    func()
+		expectedStatus int
+	}{
+		"must 404 (unconfirmed subscription)": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(&database.Subscription{Confirmed: false}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		"must 204": {
+			preparation: func() {
+				subscriptionMock.On("GetByToken", validToken, database.TokenPurposeUnsubscribe).Return(&database.Subscription{
+					SubscriberId: 2,
+					Confirmed:    true,
+				}, nil)
+				subscriptionMock.On("DeleteByToken", validToken).Return(nil)
+				subscriberMock.On("DeleteUnused", int64(2)).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tc.preparation()
+
+			req, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/subscriptions/"+validToken, nil)
+			response, err := http.DefaultClient.Do(req)
+
+			subscriptionMock.AssertExpectations(t)
+			subscriberMock.AssertExpectations(t)
+			resetMocks()
+			if err != nil {
+				t.Fatalf("failed to make request: %v", err)
+			}
+
+			if response.StatusCode != tc.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tc.expectedStatus, response.StatusCode)
+			}
 		})
 	}
 }
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyAdmin = "admin"
+
+// AdminConfig is optional, the admin API is disabled without the API keys.
+// Several keys let the admins be granted and revoked one by one, ApiKey is a shorthand for the single one.
+type AdminConfig struct {
+	ApiKey  string   `fig:"api_key"`
+	ApiKeys []string `fig:"api_keys"`
+}
+
+// Keys returns all the configured API keys, the empty ones are skipped
+func (c AdminConfig) Keys() []string {
+	keys := make([]string, 0, len(c.ApiKeys)+1)
+	for _, key := range append([]string{c.ApiKey}, c.ApiKeys...) {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+type AdminConfiger interface {
+	AdminConfig() AdminConfig
+}
+
+type adminConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewAdminConfiger(getter kv.Getter) AdminConfiger {
+	return &adminConfiger{
+		getter: getter,
+	}
+}
+
+func (c *adminConfiger) AdminConfig() AdminConfig {
+	return c.once.Do(func() interface{} {
+		var cfg AdminConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyAdmin)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out admin config: %w", err))
+		}
+
+		return cfg
+	}).(AdminConfig)
+}
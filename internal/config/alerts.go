@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyAlerts = "alerts"
+
+// AlertsConfig is optional, zero values are replaced with the alert evaluator defaults
+type AlertsConfig struct {
+	Interval      time.Duration `fig:"interval"`
+	CheckInterval time.Duration `fig:"check_interval"`
+	BatchSize     uint64        `fig:"batch_size"`
+	Lease         time.Duration `fig:"lease"`
+}
+
+type AlertsConfiger interface {
+	AlertsConfig() AlertsConfig
+}
+
+type alertsConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewAlertsConfiger(getter kv.Getter) AlertsConfiger {
+	return &alertsConfiger{
+		getter: getter,
+	}
+}
+
+func (c *alertsConfiger) AlertsConfig() AlertsConfig {
+	return c.once.Do(func() interface{} {
+		var cfg AlertsConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyAlerts)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out alerts config: %w", err))
+		}
+
+		return cfg
+	}).(AlertsConfig)
+}
@@ -11,17 +11,41 @@ type Config struct {
 	pgdb.Databaser
 	comfig.Listenerer
 	WeatherAPIConfiger
+	WeatherProvidersConfiger
+	WeatherCacheConfiger
+	RateLimitsConfiger
+	ConfirmationConfiger
+	NotificatorConfiger
+	AlertsConfiger
+	NotificationDispatcherConfiger
+	SubscriptionFailuresConfiger
+	AdminConfiger
+	UnsubscribeConfiger
+	MailConfiger
 	MailjetConfiger
+	SMTPConfiger
 	ServeStaticConfiger
 }
 
 func New(getter kv.Getter) *Config {
 	return &Config{
-		Logger:              comfig.NewLogger(getter, comfig.LoggerOpts{}),
-		Databaser:           pgdb.NewDatabaser(getter),
-		Listenerer:          comfig.NewListenerer(getter),
-		WeatherAPIConfiger:  NewWeatherAPIConfiger(getter),
-		MailjetConfiger:     NewMailjetConfiger(getter),
-		ServeStaticConfiger: NewServeStaticConfiger(getter),
+		Logger:                         comfig.NewLogger(getter, comfig.LoggerOpts{}),
+		Databaser:                      pgdb.NewDatabaser(getter),
+		Listenerer:                     comfig.NewListenerer(getter),
+		WeatherAPIConfiger:             NewWeatherAPIConfiger(getter),
+		WeatherProvidersConfiger:       NewWeatherProvidersConfiger(getter),
+		WeatherCacheConfiger:           NewWeatherCacheConfiger(getter),
+		RateLimitsConfiger:             NewRateLimitsConfiger(getter),
+		ConfirmationConfiger:           NewConfirmationConfiger(getter),
+		NotificatorConfiger:            NewNotificatorConfiger(getter),
+		AlertsConfiger:                 NewAlertsConfiger(getter),
+		NotificationDispatcherConfiger: NewNotificationDispatcherConfiger(getter),
+		SubscriptionFailuresConfiger:   NewSubscriptionFailuresConfiger(getter),
+		AdminConfiger:                  NewAdminConfiger(getter),
+		UnsubscribeConfiger:            NewUnsubscribeConfiger(getter),
+		MailConfiger:                   NewMailConfiger(getter),
+		MailjetConfiger:                NewMailjetConfiger(getter),
+		SMTPConfiger:                   NewSMTPConfiger(getter),
+		ServeStaticConfiger:            NewServeStaticConfiger(getter),
 	}
 }
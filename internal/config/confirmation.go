@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyConfirmation = "confirmation"
+
+// ConfirmationConfig is optional, zero values are replaced with the api and purger defaults
+type ConfirmationConfig struct {
+	TokenTTL time.Duration `fig:"token_ttl"`
+	// ResendInterval is the minimal time between the confirmation emails of the same subscription
+	ResendInterval time.Duration `fig:"resend_interval"`
+	// PurgeAfter is the time the unconfirmed subscriptions are kept after the last confirmation email
+	PurgeAfter    time.Duration `fig:"purge_after"`
+	PurgeInterval time.Duration `fig:"purge_interval"`
+}
+
+type ConfirmationConfiger interface {
+	ConfirmationConfig() ConfirmationConfig
+}
+
+type confirmationConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewConfirmationConfiger(getter kv.Getter) ConfirmationConfiger {
+	return &confirmationConfiger{
+		getter: getter,
+	}
+}
+
+func (c *confirmationConfiger) ConfirmationConfig() ConfirmationConfig {
+	return c.once.Do(func() interface{} {
+		var cfg ConfirmationConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyConfirmation)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out confirmation config: %w", err))
+		}
+
+		return cfg
+	}).(ConfirmationConfig)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyMail = "mail"
+
+const (
+	MailTransportMailjet = "mailjet"
+	MailTransportSMTP    = "smtp"
+)
+
+// MailConfig is optional, it selects the transport configured by its own section (mailjet or smtp)
+type MailConfig struct {
+	Transport string `fig:"transport"`
+}
+
+type MailConfiger interface {
+	MailConfig() MailConfig
+}
+
+type mailConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewMailConfiger(getter kv.Getter) MailConfiger {
+	return &mailConfiger{
+		getter: getter,
+	}
+}
+
+func (c *mailConfiger) MailConfig() MailConfig {
+	return c.once.Do(func() interface{} {
+		var cfg = MailConfig{
+			Transport: MailTransportMailjet,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyMail)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out mail config: %w", err))
+		}
+
+		switch cfg.Transport {
+		case MailTransportMailjet, MailTransportSMTP:
+		default:
+			panic(fmt.Errorf("unknown mail transport %s", cfg.Transport))
+		}
+
+		return cfg
+	}).(MailConfig)
+}
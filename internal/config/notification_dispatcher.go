@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyNotificationDispatcher = "notification_dispatcher"
+
+// NotificationDispatcherConfig is optional, zero values are replaced with the dispatcher defaults
+type NotificationDispatcherConfig struct {
+	Interval     time.Duration `fig:"interval"`
+	BatchSize    uint64        `fig:"batch_size"`
+	Lease        time.Duration `fig:"lease"`
+	MaxAttempts  int           `fig:"max_attempts"`
+	RetryBackoff time.Duration `fig:"retry_backoff"`
+}
+
+type NotificationDispatcherConfiger interface {
+	NotificationDispatcherConfig() NotificationDispatcherConfig
+}
+
+type notificationDispatcherConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewNotificationDispatcherConfiger(getter kv.Getter) NotificationDispatcherConfiger {
+	return &notificationDispatcherConfiger{
+		getter: getter,
+	}
+}
+
+func (c *notificationDispatcherConfiger) NotificationDispatcherConfig() NotificationDispatcherConfig {
+	return c.once.Do(func() interface{} {
+		var cfg NotificationDispatcherConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyNotificationDispatcher)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out notification dispatcher config: %w", err))
+		}
+
+		return cfg
+	}).(NotificationDispatcherConfig)
+}
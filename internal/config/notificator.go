@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyNotificator = "notificator"
+
+// NotificatorConfig is optional, zero values are replaced with the notificator defaults
+type NotificatorConfig struct {
+	Interval           time.Duration `fig:"interval"`
+	BatchSize          uint64        `fig:"batch_size"`
+	Lease              time.Duration `fig:"lease"`
+	MergeSubscriptions bool          `fig:"merge_subscriptions"`
+}
+
+type NotificatorConfiger interface {
+	NotificatorConfig() NotificatorConfig
+}
+
+type notificatorConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewNotificatorConfiger(getter kv.Getter) NotificatorConfiger {
+	return &notificatorConfiger{
+		getter: getter,
+	}
+}
+
+func (c *notificatorConfiger) NotificatorConfig() NotificatorConfig {
+	return c.once.Do(func() interface{} {
+		var cfg NotificatorConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyNotificator)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out notificator config: %w", err))
+		}
+
+		return cfg
+	}).(NotificatorConfig)
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyRateLimits = "rate_limits"
+
+// RateLimitsConfig is optional, zero values are replaced with the api defaults.
+// Rates are in requests per second per client IP, bursts are the bucket capacities.
+type RateLimitsConfig struct {
+	// TrustProxy takes the client IP from the X-Forwarded-For or X-Real-IP headers,
+	// enable it only behind a proxy or a load balancer that sets them
+	TrustProxy      bool    `fig:"trust_proxy"`
+	CitySearchRate  float64 `fig:"city_search_rate"`
+	CitySearchBurst int     `fig:"city_search_burst"`
+	ResendRate      float64 `fig:"resend_rate"`
+	ResendBurst     int     `fig:"resend_burst"`
+}
+
+type RateLimitsConfiger interface {
+	RateLimitsConfig() RateLimitsConfig
+}
+
+type rateLimitsConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewRateLimitsConfiger(getter kv.Getter) RateLimitsConfiger {
+	return &rateLimitsConfiger{
+		getter: getter,
+	}
+}
+
+func (c *rateLimitsConfiger) RateLimitsConfig() RateLimitsConfig {
+	return c.once.Do(func() interface{} {
+		var cfg RateLimitsConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyRateLimits)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out rate limits config: %w", err))
+		}
+
+		return cfg
+	}).(RateLimitsConfig)
+}
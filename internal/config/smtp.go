@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeySMTP = "smtp"
+
+// SMTPConfig is required with the smtp mail transport only, zero values are replaced with the client defaults
+type SMTPConfig struct {
+	Host string `fig:"host,required"`
+	Port int    `fig:"port"`
+	// Security is starttls (default), tls (implicit) or none
+	Security string `fig:"security"`
+	// Username is optional, the authentication is skipped without it
+	Username string `fig:"username"`
+	Password string `fig:"password"`
+	// Auth is plain (default) or login
+	Auth        string        `fig:"auth"`
+	FromEmail   string        `fig:"from_email,required"`
+	FromName    string        `fig:"from_name"`
+	PoolSize    int           `fig:"pool_size"`
+	IdleTimeout time.Duration `fig:"idle_timeout"`
+	DialTimeout time.Duration `fig:"dial_timeout"`
+	SendTimeout time.Duration `fig:"send_timeout"`
+}
+
+type SMTPConfiger interface {
+	SMTPConfig() SMTPConfig
+}
+
+type smtpConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewSMTPConfiger(getter kv.Getter) SMTPConfiger {
+	return &smtpConfiger{
+		getter: getter,
+	}
+}
+
+func (c *smtpConfiger) SMTPConfig() SMTPConfig {
+	return c.once.Do(func() interface{} {
+		var cfg = SMTPConfig{
+			FromName: defaultFromName,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeySMTP)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out smtp config: %w", err))
+		}
+
+		return cfg
+	}).(SMTPConfig)
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeySubscriptionFailures = "subscription_failures"
+
+// SubscriptionFailuresConfig is optional, zero values are replaced with the notificator defaults
+type SubscriptionFailuresConfig struct {
+	MaxFailures     int           `fig:"max_failures"`
+	RetryBackoff    time.Duration `fig:"retry_backoff"`
+	MaxRetryBackoff time.Duration `fig:"max_retry_backoff"`
+}
+
+type SubscriptionFailuresConfiger interface {
+	SubscriptionFailuresConfig() SubscriptionFailuresConfig
+}
+
+type subscriptionFailuresConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewSubscriptionFailuresConfiger(getter kv.Getter) SubscriptionFailuresConfiger {
+	return &subscriptionFailuresConfiger{
+		getter: getter,
+	}
+}
+
+func (c *subscriptionFailuresConfiger) SubscriptionFailuresConfig() SubscriptionFailuresConfig {
+	return c.once.Do(func() interface{} {
+		var cfg SubscriptionFailuresConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeySubscriptionFailures)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out subscription failures config: %w", err))
+		}
+
+		return cfg
+	}).(SubscriptionFailuresConfig)
+}
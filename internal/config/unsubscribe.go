@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyUnsubscribe = "unsubscribe"
+
+// UnsubscribeConfig signs the one-click unsubscribe links (List-Unsubscribe headers) of every sent email
+type UnsubscribeConfig struct {
+	// SigningKey signs the links, changing it invalidates all the sent ones
+	SigningKey string `fig:"signing_key"`
+	// BaseURL is the public API URL the links point to, e.g. https://example.com/api
+	BaseURL string `fig:"base_url"`
+}
+
+type UnsubscribeConfiger interface {
+	UnsubscribeConfig() UnsubscribeConfig
+}
+
+type unsubscribeConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewUnsubscribeConfiger(getter kv.Getter) UnsubscribeConfiger {
+	return &unsubscribeConfiger{
+		getter: getter,
+	}
+}
+
+func (c *unsubscribeConfiger) UnsubscribeConfig() UnsubscribeConfig {
+	return c.once.Do(func() interface{} {
+		var cfg UnsubscribeConfig
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyUnsubscribe)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out unsubscribe config: %w", err))
+		}
+		if cfg.SigningKey == "" {
+			panic(errors.New("unsubscribe signing_key is required"))
+		}
+		if cfg.BaseURL == "" {
+			panic(errors.New("unsubscribe base_url is required"))
+		}
+
+		return cfg
+	}).(UnsubscribeConfig)
+}
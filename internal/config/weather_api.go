@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"gitlab.com/distributed_lab/figure/v3"
 	"gitlab.com/distributed_lab/kit/comfig"
@@ -12,6 +13,11 @@ const configKeyWeatherAPI = "weather_api"
 
 type WeatherAPIConfig struct {
 	APIKey string `fig:"api_key,required"`
+	// optional transport tuning, zero values are replaced with client defaults
+	RequestTimeout time.Duration `fig:"request_timeout"`
+	MaxRetries     int           `fig:"max_retries"`
+	BackoffBase    time.Duration `fig:"backoff_base"`
+	BackoffMax     time.Duration `fig:"backoff_max"`
 }
 
 type WeatherAPIConfiger interface {
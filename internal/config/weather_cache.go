@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const (
+	configKeyWeatherCache = "weather_cache"
+
+	defaultCacheStatsInterval = 5 * time.Minute
+)
+
+// WeatherCacheConfig is optional, zero values are replaced with cache defaults
+type WeatherCacheConfig struct {
+	TTL           time.Duration `fig:"ttl"`
+	NegativeTTL   time.Duration `fig:"negative_ttl"`
+	MaxEntries    int           `fig:"max_entries"`
+	StatsInterval time.Duration `fig:"stats_interval"`
+}
+
+type WeatherCacheConfiger interface {
+	WeatherCacheConfig() WeatherCacheConfig
+}
+
+type weatherCacheConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewWeatherCacheConfiger(getter kv.Getter) WeatherCacheConfiger {
+	return &weatherCacheConfiger{
+		getter: getter,
+	}
+}
+
+func (c *weatherCacheConfiger) WeatherCacheConfig() WeatherCacheConfig {
+	return c.once.Do(func() interface{} {
+		var cfg = WeatherCacheConfig{
+			StatsInterval: defaultCacheStatsInterval,
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyWeatherCache)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out weather cache config: %w", err))
+		}
+
+		return cfg
+	}).(WeatherCacheConfig)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	"gitlab.com/distributed_lab/figure/v3"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+)
+
+const configKeyWeatherProviders = "weather_providers"
+
+type WeatherProvidersConfig struct {
+	// Priority lists provider names in the order they are tried, defaults to weatherapi only
+	Priority         []string      `fig:"priority"`
+	FailureThreshold int           `fig:"failure_threshold"`
+	Cooldown         time.Duration `fig:"cooldown"`
+
+	OpenWeatherMapAPIKey string `fig:"openweathermap_api_key"`
+
+	// transport tuning of the additional providers, weatherapi.com one is configured in its own section
+	RequestTimeout time.Duration `fig:"request_timeout"`
+	MaxRetries     int           `fig:"max_retries"`
+	BackoffBase    time.Duration `fig:"backoff_base"`
+	BackoffMax     time.Duration `fig:"backoff_max"`
+}
+
+type WeatherProvidersConfiger interface {
+	WeatherProvidersConfig() WeatherProvidersConfig
+}
+
+type weatherProvidersConfiger struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func NewWeatherProvidersConfiger(getter kv.Getter) WeatherProvidersConfiger {
+	return &weatherProvidersConfiger{
+		getter: getter,
+	}
+}
+
+func (c *weatherProvidersConfiger) WeatherProvidersConfig() WeatherProvidersConfig {
+	return c.once.Do(func() interface{} {
+		var cfg = WeatherProvidersConfig{
+			Priority: []string{weatherapi.ProviderWeatherAPI},
+		}
+
+		err := figure.
+			Out(&cfg).
+			From(kv.MustGetStringMap(c.getter, configKeyWeatherProviders)).
+			With(figure.BaseHooks).
+			Please()
+		if err != nil {
+			panic(fmt.Errorf("failed to figure out weather providers config: %w", err))
+		}
+
+		return cfg
+	}).(WeatherProvidersConfig)
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+)
+
+// AlertMetric is the daily forecast value checked by the alert rule
+type AlertMetric string
+
+const (
+	// AlertMetricRainChance is the daily chance of rain, %
+	AlertMetricRainChance AlertMetric = "rain_chance"
+	// AlertMetricMinTemperature is the minimal daily temperature, °C
+	AlertMetricMinTemperature AlertMetric = "min_temperature"
+	// AlertMetricMaxTemperature is the maximal daily temperature, °C
+	AlertMetricMaxTemperature AlertMetric = "max_temperature"
+	// AlertMetricMaxWind is the maximal daily wind speed, kph
+	AlertMetricMaxWind AlertMetric = "max_wind"
+)
+
+func (m AlertMetric) Valid() bool {
+	switch m {
+	case AlertMetricRainChance, AlertMetricMinTemperature, AlertMetricMaxTemperature, AlertMetricMaxWind:
+		return true
+	default:
+		return false
+	}
+}
+
+type AlertOperator string
+
+const (
+	AlertOperatorAbove AlertOperator = "above"
+	AlertOperatorBelow AlertOperator = "below"
+)
+
+func (o AlertOperator) Valid() bool {
+	return o == AlertOperatorAbove || o == AlertOperatorBelow
+}
+
+// Holds reports whether the value satisfies the operator against the threshold (strictly)
+func (o AlertOperator) Holds(value, threshold float64) bool {
+	switch o {
+	case AlertOperatorAbove:
+		return value > threshold
+	case AlertOperatorBelow:
+		return value < threshold
+	default:
+		return false
+	}
+}
+
+var ErrAlertRuleExists = errors.New("alert rule already exists")
+
+type AlertRulesQ interface {
+	// New creates a new instance of AlertRulesQ (separate conn)
+	New() AlertRulesQ
+	// Insert returns ErrAlertRuleExists if the same rule is already created for the email
+	Insert(rule AlertRule) (id int64, err error)
+	// GetByToken returns the alert rule owning the token of the purpose, nil if there is none
+	GetByToken(token string, purpose TokenPurpose) (rule *AlertRule, err error)
+	// GetByCondition returns the rule of the email with the same location and condition, nil if there is none
+	GetByCondition(rule AlertRule) (*AlertRule, error)
+	// SelectUnconfirmed returns the unconfirmed rules of the email
+	SelectUnconfirmed(email string) ([]AlertRule, error)
+	UpdateConfirmed(id int64) error
+	// UpdateConfirmationSent records the reissue of the confirmation token of the unconfirmed rule
+	UpdateConfirmationSent(id int64, sentAt time.Time) error
+	// DeleteByToken deletes the alert rule owning the unsubscribe token
+	DeleteByToken(token string) error
+	// Delete returns ErrNoRowsAffected if there is no alert rule with the id
+	Delete(id int64) error
+	// DeleteUnconfirmed deletes the unconfirmed rules whose last confirmation token was sent before sentBefore
+	DeleteUnconfirmed(sentBefore time.Time) (deleted int64, err error)
+	// SelectToEvaluate claims up to limit confirmed rules last checked before checkedBefore for the lease duration,
+	// skipping the ones claimed by other instances. The lease is released by UpdateEvaluated or expires.
+	SelectToEvaluate(limit uint64, lease time.Duration, checkedBefore time.Time) ([]AlertRule, error)
+	// UpdateEvaluated records the outcome of the check, it also releases the lease
+	UpdateEvaluated(id int64, triggered bool, checkedAt time.Time) error
+}
+
+type AlertRule struct {
+	Id         int64   `structs:"-" db:"id"`
+	Email      string  `structs:"email" db:"email"`
+	City       string  `structs:"city" db:"city"`
+	LocationId string  `structs:"location_id" db:"location_id"`
+	Region     string  `structs:"region" db:"region"`
+	Country    string  `structs:"country" db:"country"`
+	Latitude   float64 `structs:"latitude" db:"latitude"`
+	Longitude  float64 `structs:"longitude" db:"longitude"`
+	Timezone   string  `structs:"timezone" db:"timezone"`
+	// Language of the emails
+	Language i18n.Language `structs:"language" db:"language"`
+
+	Metric    AlertMetric   `structs:"metric" db:"metric"`
+	Operator  AlertOperator `structs:"operator" db:"operator"`
+	Threshold float64       `structs:"threshold" db:"threshold"`
+	// DayOffset is 0 for today and 1 for tomorrow
+	DayOffset int `structs:"day_offset" db:"day_offset"`
+
+	Confirmed bool `structs:"confirmed" db:"confirmed"`
+	// ConfirmationSentAt is the issue time of the confirmation token, the token expires after the configured TTL
+	ConfirmationSentAt *time.Time `structs:"confirmation_sent_at" db:"confirmation_sent_at"`
+	Triggered          bool       `structs:"triggered" db:"triggered"`
+	LastCheckedAt      *time.Time `structs:"last_checked_at" db:"last_checked_at"`
+	LeaseExpiresAt     *time.Time `structs:"lease_expires_at" db:"lease_expires_at"`
+	CreatedAt          time.Time  `structs:"created_at" db:"created_at"`
+}
+
+// ConfirmationExpired reports whether the confirmation token of the unconfirmed rule has expired
+func (r AlertRule) ConfirmationExpired(ttl time.Duration, now time.Time) bool {
+	return !r.Confirmed && r.ConfirmationSentAt != nil && now.After(r.ConfirmationSentAt.Add(ttl))
+}
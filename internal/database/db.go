@@ -2,6 +2,10 @@ package database
 
 type Database interface {
 	New() Database
+	SubscribersQ() SubscribersQ
 	SubscriptionsQ() SubscriptionsQ
+	NotificationsQ() NotificationsQ
+	AlertRulesQ() AlertRulesQ
+	TokensQ() TokensQ
 	Transaction(func() error) error
 }
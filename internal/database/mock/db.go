@@ -4,24 +4,58 @@ import "github.com/slbmax/ses-weather-app/internal/database"
 
 type db struct {
 	subscriptionsMock *MockSubscriptionsQ
+	notificationsMock *MockNotificationsQ
+	alertRulesMock    *MockAlertRulesQ
+	subscribersMock   *MockSubscribersQ
+	tokensMock        *MockTokensQ
 }
 
-func NewDatabase(mock *MockSubscriptionsQ) database.Database {
+func NewDatabase(
+	subscriptions *MockSubscriptionsQ,
+	notifications *MockNotificationsQ,
+	alertRules *MockAlertRulesQ,
+	subscribers *MockSubscribersQ,
+	tokens *MockTokensQ,
+) database.Database {
 	return &db{
-		subscriptionsMock: mock,
+		subscriptionsMock: subscriptions,
+		notificationsMock: notifications,
+		alertRulesMock:    alertRules,
+		subscribersMock:   subscribers,
+		tokensMock:        tokens,
 	}
 }
 
 func (d *db) New() database.Database {
 	return &db{
 		subscriptionsMock: d.subscriptionsMock,
+		notificationsMock: d.notificationsMock,
+		alertRulesMock:    d.alertRulesMock,
+		subscribersMock:   d.subscribersMock,
+		tokensMock:        d.tokensMock,
 	}
 }
 
+func (d *db) SubscribersQ() database.SubscribersQ {
+	return d.subscribersMock
+}
+
 func (d *db) SubscriptionsQ() database.SubscriptionsQ {
 	return d.subscriptionsMock
 }
 
+func (d *db) NotificationsQ() database.NotificationsQ {
+	return d.notificationsMock
+}
+
+func (d *db) AlertRulesQ() database.AlertRulesQ {
+	return d.alertRulesMock
+}
+
+func (d *db) TokensQ() database.TokensQ {
+	return d.tokensMock
+}
+
 func (d *db) Transaction(fn func() error) error {
 	return fn()
 }
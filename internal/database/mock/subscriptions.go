@@ -38,6 +38,159 @@ func (_m *MockSubscriptionsQ) EXPECT() *MockSubscriptionsQ_Expecter {
 	return &MockSubscriptionsQ_Expecter{mock: &_m.Mock}
 }
 
+// Count provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) Count(filter database.SubscriptionsFilter) (int64, error) {
+	ret := _mock.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(database.SubscriptionsFilter) (int64, error)); ok {
+		return returnFunc(filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(database.SubscriptionsFilter) int64); ok {
+		r0 = returnFunc(filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(database.SubscriptionsFilter) error); ok {
+		r1 = returnFunc(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type MockSubscriptionsQ_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - filter
+func (_e *MockSubscriptionsQ_Expecter) Count(filter interface{}) *MockSubscriptionsQ_Count_Call {
+	return &MockSubscriptionsQ_Count_Call{Call: _e.mock.On("Count", filter)}
+}
+
+func (_c *MockSubscriptionsQ_Count_Call) Run(run func(filter database.SubscriptionsFilter)) *MockSubscriptionsQ_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(database.SubscriptionsFilter))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_Count_Call) Return(n int64, err error) *MockSubscriptionsQ_Count_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_Count_Call) RunAndReturn(run func(filter database.SubscriptionsFilter) (int64, error)) *MockSubscriptionsQ_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) Delete(id int64) error {
+	ret := _mock.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = returnFunc(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockSubscriptionsQ_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - id
+func (_e *MockSubscriptionsQ_Expecter) Delete(id interface{}) *MockSubscriptionsQ_Delete_Call {
+	return &MockSubscriptionsQ_Delete_Call{Call: _e.mock.On("Delete", id)}
+}
+
+func (_c *MockSubscriptionsQ_Delete_Call) Run(run func(id int64)) *MockSubscriptionsQ_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_Delete_Call) Return(err error) *MockSubscriptionsQ_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_Delete_Call) RunAndReturn(run func(id int64) error) *MockSubscriptionsQ_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteBySubscriber provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) DeleteBySubscriber(subscriberId int64) (int64, error) {
+	ret := _mock.Called(subscriberId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBySubscriber")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return returnFunc(subscriberId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = returnFunc(subscriberId)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = returnFunc(subscriberId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_DeleteBySubscriber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBySubscriber'
+type MockSubscriptionsQ_DeleteBySubscriber_Call struct {
+	*mock.Call
+}
+
+// DeleteBySubscriber is a helper method to define mock.On call
+//   - subscriberId
+func (_e *MockSubscriptionsQ_Expecter) DeleteBySubscriber(subscriberId interface{}) *MockSubscriptionsQ_DeleteBySubscriber_Call {
+	return &MockSubscriptionsQ_DeleteBySubscriber_Call{Call: _e.mock.On("DeleteBySubscriber", subscriberId)}
+}
+
+func (_c *MockSubscriptionsQ_DeleteBySubscriber_Call) Run(run func(subscriberId int64)) *MockSubscriptionsQ_DeleteBySubscriber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_DeleteBySubscriber_Call) Return(deleted int64, err error) *MockSubscriptionsQ_DeleteBySubscriber_Call {
+	_c.Call.Return(deleted, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_DeleteBySubscriber_Call) RunAndReturn(run func(subscriberId int64) (int64, error)) *MockSubscriptionsQ_DeleteBySubscriber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteByToken provides a mock function for the type MockSubscriptionsQ
 func (_mock *MockSubscriptionsQ) DeleteByToken(token string) error {
 	ret := _mock.Called(token)
@@ -83,9 +236,177 @@ func (_c *MockSubscriptionsQ_DeleteByToken_Call) RunAndReturn(run func(token str
 	return _c
 }
 
+// DeleteUnconfirmed provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) DeleteUnconfirmed(sentBefore time.Time) (int64, error) {
+	ret := _mock.Called(sentBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUnconfirmed")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return returnFunc(sentBefore)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = returnFunc(sentBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = returnFunc(sentBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_DeleteUnconfirmed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUnconfirmed'
+type MockSubscriptionsQ_DeleteUnconfirmed_Call struct {
+	*mock.Call
+}
+
+// DeleteUnconfirmed is a helper method to define mock.On call
+//   - sentBefore
+func (_e *MockSubscriptionsQ_Expecter) DeleteUnconfirmed(sentBefore interface{}) *MockSubscriptionsQ_DeleteUnconfirmed_Call {
+	return &MockSubscriptionsQ_DeleteUnconfirmed_Call{Call: _e.mock.On("DeleteUnconfirmed", sentBefore)}
+}
+
+func (_c *MockSubscriptionsQ_DeleteUnconfirmed_Call) Run(run func(sentBefore time.Time)) *MockSubscriptionsQ_DeleteUnconfirmed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_DeleteUnconfirmed_Call) Return(deleted int64, err error) *MockSubscriptionsQ_DeleteUnconfirmed_Call {
+	_c.Call.Return(deleted, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_DeleteUnconfirmed_Call) RunAndReturn(run func(sentBefore time.Time) (int64, error)) *MockSubscriptionsQ_DeleteUnconfirmed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetById provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) GetById(id int64) (*database.Subscription, error) {
+	ret := _mock.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetById")
+	}
+
+	var r0 *database.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int64) (*database.Subscription, error)); ok {
+		return returnFunc(id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64) *database.Subscription); ok {
+		r0 = returnFunc(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*database.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = returnFunc(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_GetById_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetById'
+type MockSubscriptionsQ_GetById_Call struct {
+	*mock.Call
+}
+
+// GetById is a helper method to define mock.On call
+//   - id
+func (_e *MockSubscriptionsQ_Expecter) GetById(id interface{}) *MockSubscriptionsQ_GetById_Call {
+	return &MockSubscriptionsQ_GetById_Call{Call: _e.mock.On("GetById", id)}
+}
+
+func (_c *MockSubscriptionsQ_GetById_Call) Run(run func(id int64)) *MockSubscriptionsQ_GetById_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_GetById_Call) Return(subscription *database.Subscription, err error) *MockSubscriptionsQ_GetById_Call {
+	_c.Call.Return(subscription, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_GetById_Call) RunAndReturn(run func(id int64) (*database.Subscription, error)) *MockSubscriptionsQ_GetById_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByLocation provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) GetByLocation(subscriberId int64, city string, locationId string) (*database.Subscription, error) {
+	ret := _mock.Called(subscriberId, city, locationId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByLocation")
+	}
+
+	var r0 *database.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int64, string, string) (*database.Subscription, error)); ok {
+		return returnFunc(subscriberId, city, locationId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64, string, string) *database.Subscription); ok {
+		r0 = returnFunc(subscriberId, city, locationId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*database.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64, string, string) error); ok {
+		r1 = returnFunc(subscriberId, city, locationId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_GetByLocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByLocation'
+type MockSubscriptionsQ_GetByLocation_Call struct {
+	*mock.Call
+}
+
+// GetByLocation is a helper method to define mock.On call
+//   - subscriberId
+//   - city
+//   - locationId
+func (_e *MockSubscriptionsQ_Expecter) GetByLocation(subscriberId interface{}, city interface{}, locationId interface{}) *MockSubscriptionsQ_GetByLocation_Call {
+	return &MockSubscriptionsQ_GetByLocation_Call{Call: _e.mock.On("GetByLocation", subscriberId, city, locationId)}
+}
+
+func (_c *MockSubscriptionsQ_GetByLocation_Call) Run(run func(subscriberId int64, city string, locationId string)) *MockSubscriptionsQ_GetByLocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_GetByLocation_Call) Return(subscription *database.Subscription, err error) *MockSubscriptionsQ_GetByLocation_Call {
+	_c.Call.Return(subscription, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_GetByLocation_Call) RunAndReturn(run func(subscriberId int64, city string, locationId string) (*database.Subscription, error)) *MockSubscriptionsQ_GetByLocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByToken provides a mock function for the type MockSubscriptionsQ
-func (_mock *MockSubscriptionsQ) GetByToken(token string) (*database.Subscription, error) {
-	ret := _mock.Called(token)
+func (_mock *MockSubscriptionsQ) GetByToken(token string, purpose database.TokenPurpose) (*database.Subscription, error) {
+	ret := _mock.Called(token, purpose)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByToken")
@@ -93,18 +414,18 @@ func (_mock *MockSubscriptionsQ) GetByToken(token string) (*database.Subscriptio
 
 	var r0 *database.Subscription
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*database.Subscription, error)); ok {
-		return returnFunc(token)
+	if returnFunc, ok := ret.Get(0).(func(string, database.TokenPurpose) (*database.Subscription, error)); ok {
+		return returnFunc(token, purpose)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *database.Subscription); ok {
-		r0 = returnFunc(token)
+	if returnFunc, ok := ret.Get(0).(func(string, database.TokenPurpose) *database.Subscription); ok {
+		r0 = returnFunc(token, purpose)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*database.Subscription)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(token)
+	if returnFunc, ok := ret.Get(1).(func(string, database.TokenPurpose) error); ok {
+		r1 = returnFunc(token, purpose)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -118,13 +439,14 @@ type MockSubscriptionsQ_GetByToken_Call struct {
 
 // GetByToken is a helper method to define mock.On call
 //   - token
-func (_e *MockSubscriptionsQ_Expecter) GetByToken(token interface{}) *MockSubscriptionsQ_GetByToken_Call {
-	return &MockSubscriptionsQ_GetByToken_Call{Call: _e.mock.On("GetByToken", token)}
+//   - purpose
+func (_e *MockSubscriptionsQ_Expecter) GetByToken(token interface{}, purpose interface{}) *MockSubscriptionsQ_GetByToken_Call {
+	return &MockSubscriptionsQ_GetByToken_Call{Call: _e.mock.On("GetByToken", token, purpose)}
 }
 
-func (_c *MockSubscriptionsQ_GetByToken_Call) Run(run func(token string)) *MockSubscriptionsQ_GetByToken_Call {
+func (_c *MockSubscriptionsQ_GetByToken_Call) Run(run func(token string, purpose database.TokenPurpose)) *MockSubscriptionsQ_GetByToken_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(string), args[1].(database.TokenPurpose))
 	})
 	return _c
 }
@@ -134,7 +456,7 @@ func (_c *MockSubscriptionsQ_GetByToken_Call) Return(subscription *database.Subs
 	return _c
 }
 
-func (_c *MockSubscriptionsQ_GetByToken_Call) RunAndReturn(run func(token string) (*database.Subscription, error)) *MockSubscriptionsQ_GetByToken_Call {
+func (_c *MockSubscriptionsQ_GetByToken_Call) RunAndReturn(run func(token string, purpose database.TokenPurpose) (*database.Subscription, error)) *MockSubscriptionsQ_GetByToken_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -239,118 +561,480 @@ func (_c *MockSubscriptionsQ_New_Call) RunAndReturn(run func() database.Subscrip
 	return _c
 }
 
-// SelectToNotify provides a mock function for the type MockSubscriptionsQ
-func (_mock *MockSubscriptionsQ) SelectToNotify() ([]database.Subscription, error) {
-	ret := _mock.Called()
+// ResetFailures provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) ResetFailures(id int64) error {
+	ret := _mock.Called(id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SelectToNotify")
+		panic("no return value specified for ResetFailures")
 	}
 
-	var r0 []database.Subscription
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func() ([]database.Subscription, error)); ok {
-		return returnFunc()
-	}
-	if returnFunc, ok := ret.Get(0).(func() []database.Subscription); ok {
-		r0 = returnFunc()
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]database.Subscription)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func() error); ok {
-		r1 = returnFunc()
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = returnFunc(id)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
-	return r0, r1
+	return r0
 }
 
-// MockSubscriptionsQ_SelectToNotify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectToNotify'
-type MockSubscriptionsQ_SelectToNotify_Call struct {
+// MockSubscriptionsQ_ResetFailures_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetFailures'
+type MockSubscriptionsQ_ResetFailures_Call struct {
 	*mock.Call
 }
 
-// SelectToNotify is a helper method to define mock.On call
-func (_e *MockSubscriptionsQ_Expecter) SelectToNotify() *MockSubscriptionsQ_SelectToNotify_Call {
-	return &MockSubscriptionsQ_SelectToNotify_Call{Call: _e.mock.On("SelectToNotify")}
+// ResetFailures is a helper method to define mock.On call
+//   - id
+func (_e *MockSubscriptionsQ_Expecter) ResetFailures(id interface{}) *MockSubscriptionsQ_ResetFailures_Call {
+	return &MockSubscriptionsQ_ResetFailures_Call{Call: _e.mock.On("ResetFailures", id)}
 }
 
-func (_c *MockSubscriptionsQ_SelectToNotify_Call) Run(run func()) *MockSubscriptionsQ_SelectToNotify_Call {
+func (_c *MockSubscriptionsQ_ResetFailures_Call) Run(run func(id int64)) *MockSubscriptionsQ_ResetFailures_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(int64))
 	})
 	return _c
 }
 
-func (_c *MockSubscriptionsQ_SelectToNotify_Call) Return(subscriptions []database.Subscription, err error) *MockSubscriptionsQ_SelectToNotify_Call {
-	_c.Call.Return(subscriptions, err)
+func (_c *MockSubscriptionsQ_ResetFailures_Call) Return(err error) *MockSubscriptionsQ_ResetFailures_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockSubscriptionsQ_SelectToNotify_Call) RunAndReturn(run func() ([]database.Subscription, error)) *MockSubscriptionsQ_SelectToNotify_Call {
+func (_c *MockSubscriptionsQ_ResetFailures_Call) RunAndReturn(run func(id int64) error) *MockSubscriptionsQ_ResetFailures_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateConfirmed provides a mock function for the type MockSubscriptionsQ
-func (_mock *MockSubscriptionsQ) UpdateConfirmed(id int64, unsubscribeToken string) error {
-	ret := _mock.Called(id, unsubscribeToken)
+// Select provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) Select(filter database.SubscriptionsFilter, limit uint64, offset uint64) ([]database.Subscription, error) {
+	ret := _mock.Called(filter, limit, offset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateConfirmed")
+		panic("no return value specified for Select")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(int64, string) error); ok {
-		r0 = returnFunc(id, unsubscribeToken)
+	var r0 []database.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(database.SubscriptionsFilter, uint64, uint64) ([]database.Subscription, error)); ok {
+		return returnFunc(filter, limit, offset)
+	}
+	if returnFunc, ok := ret.Get(0).(func(database.SubscriptionsFilter, uint64, uint64) []database.Subscription); ok {
+		r0 = returnFunc(filter, limit, offset)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.Subscription)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(database.SubscriptionsFilter, uint64, uint64) error); ok {
+		r1 = returnFunc(filter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockSubscriptionsQ_UpdateConfirmed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateConfirmed'
-type MockSubscriptionsQ_UpdateConfirmed_Call struct {
+// MockSubscriptionsQ_Select_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Select'
+type MockSubscriptionsQ_Select_Call struct {
 	*mock.Call
 }
 
-// UpdateConfirmed is a helper method to define mock.On call
-//   - id
-//   - unsubscribeToken
-func (_e *MockSubscriptionsQ_Expecter) UpdateConfirmed(id interface{}, unsubscribeToken interface{}) *MockSubscriptionsQ_UpdateConfirmed_Call {
-	return &MockSubscriptionsQ_UpdateConfirmed_Call{Call: _e.mock.On("UpdateConfirmed", id, unsubscribeToken)}
+// Select is a helper method to define mock.On call
+//   - filter
+//   - limit
+//   - offset
+func (_e *MockSubscriptionsQ_Expecter) Select(filter interface{}, limit interface{}, offset interface{}) *MockSubscriptionsQ_Select_Call {
+	return &MockSubscriptionsQ_Select_Call{Call: _e.mock.On("Select", filter, limit, offset)}
 }
 
-func (_c *MockSubscriptionsQ_UpdateConfirmed_Call) Run(run func(id int64, unsubscribeToken string)) *MockSubscriptionsQ_UpdateConfirmed_Call {
+func (_c *MockSubscriptionsQ_Select_Call) Run(run func(filter database.SubscriptionsFilter, limit uint64, offset uint64)) *MockSubscriptionsQ_Select_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(string))
+		run(args[0].(database.SubscriptionsFilter), args[1].(uint64), args[2].(uint64))
 	})
 	return _c
 }
 
-func (_c *MockSubscriptionsQ_UpdateConfirmed_Call) Return(err error) *MockSubscriptionsQ_UpdateConfirmed_Call {
-	_c.Call.Return(err)
+func (_c *MockSubscriptionsQ_Select_Call) Return(subscriptions []database.Subscription, err error) *MockSubscriptionsQ_Select_Call {
+	_c.Call.Return(subscriptions, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_Select_Call) RunAndReturn(run func(filter database.SubscriptionsFilter, limit uint64, offset uint64) ([]database.Subscription, error)) *MockSubscriptionsQ_Select_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SelectSuspended provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) SelectSuspended() ([]database.Subscription, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SelectSuspended")
+	}
+
+	var r0 []database.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() ([]database.Subscription, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() []database.Subscription); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_SelectSuspended_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectSuspended'
+type MockSubscriptionsQ_SelectSuspended_Call struct {
+	*mock.Call
+}
+
+// SelectSuspended is a helper method to define mock.On call
+func (_e *MockSubscriptionsQ_Expecter) SelectSuspended() *MockSubscriptionsQ_SelectSuspended_Call {
+	return &MockSubscriptionsQ_SelectSuspended_Call{Call: _e.mock.On("SelectSuspended")}
+}
+
+func (_c *MockSubscriptionsQ_SelectSuspended_Call) Run(run func()) *MockSubscriptionsQ_SelectSuspended_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_SelectSuspended_Call) Return(subscriptions []database.Subscription, err error) *MockSubscriptionsQ_SelectSuspended_Call {
+	_c.Call.Return(subscriptions, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_SelectSuspended_Call) RunAndReturn(run func() ([]database.Subscription, error)) *MockSubscriptionsQ_SelectSuspended_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SelectToNotify provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) SelectToNotify(limit uint64, lease time.Duration) ([]database.Subscription, error) {
+	ret := _mock.Called(limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SelectToNotify")
+	}
+
+	var r0 []database.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(uint64, time.Duration) ([]database.Subscription, error)); ok {
+		return returnFunc(limit, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(uint64, time.Duration) []database.Subscription); ok {
+		r0 = returnFunc(limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(uint64, time.Duration) error); ok {
+		r1 = returnFunc(limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_SelectToNotify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectToNotify'
+type MockSubscriptionsQ_SelectToNotify_Call struct {
+	*mock.Call
+}
+
+// SelectToNotify is a helper method to define mock.On call
+//   - limit
+//   - lease
+func (_e *MockSubscriptionsQ_Expecter) SelectToNotify(limit interface{}, lease interface{}) *MockSubscriptionsQ_SelectToNotify_Call {
+	return &MockSubscriptionsQ_SelectToNotify_Call{Call: _e.mock.On("SelectToNotify", limit, lease)}
+}
+
+func (_c *MockSubscriptionsQ_SelectToNotify_Call) Run(run func(limit uint64, lease time.Duration)) *MockSubscriptionsQ_SelectToNotify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint64), args[1].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_SelectToNotify_Call) Return(subscriptions []database.Subscription, err error) *MockSubscriptionsQ_SelectToNotify_Call {
+	_c.Call.Return(subscriptions, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_SelectToNotify_Call) RunAndReturn(run func(limit uint64, lease time.Duration) ([]database.Subscription, error)) *MockSubscriptionsQ_SelectToNotify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SelectUnconfirmed provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) SelectUnconfirmed(subscriberId int64) ([]database.Subscription, error) {
+	ret := _mock.Called(subscriberId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SelectUnconfirmed")
+	}
+
+	var r0 []database.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int64) ([]database.Subscription, error)); ok {
+		return returnFunc(subscriberId)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64) []database.Subscription); ok {
+		r0 = returnFunc(subscriberId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]database.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = returnFunc(subscriberId)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_SelectUnconfirmed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SelectUnconfirmed'
+type MockSubscriptionsQ_SelectUnconfirmed_Call struct {
+	*mock.Call
+}
+
+// SelectUnconfirmed is a helper method to define mock.On call
+//   - subscriberId
+func (_e *MockSubscriptionsQ_Expecter) SelectUnconfirmed(subscriberId interface{}) *MockSubscriptionsQ_SelectUnconfirmed_Call {
+	return &MockSubscriptionsQ_SelectUnconfirmed_Call{Call: _e.mock.On("SelectUnconfirmed", subscriberId)}
+}
+
+func (_c *MockSubscriptionsQ_SelectUnconfirmed_Call) Run(run func(subscriberId int64)) *MockSubscriptionsQ_SelectUnconfirmed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_SelectUnconfirmed_Call) Return(subscriptions []database.Subscription, err error) *MockSubscriptionsQ_SelectUnconfirmed_Call {
+	_c.Call.Return(subscriptions, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_SelectUnconfirmed_Call) RunAndReturn(run func(subscriberId int64) ([]database.Subscription, error)) *MockSubscriptionsQ_SelectUnconfirmed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) Update(subscription database.Subscription) error {
+	ret := _mock.Called(subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(database.Subscription) error); ok {
+		r0 = returnFunc(subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockSubscriptionsQ_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - subscription
+func (_e *MockSubscriptionsQ_Expecter) Update(subscription interface{}) *MockSubscriptionsQ_Update_Call {
+	return &MockSubscriptionsQ_Update_Call{Call: _e.mock.On("Update", subscription)}
+}
+
+func (_c *MockSubscriptionsQ_Update_Call) Run(run func(subscription database.Subscription)) *MockSubscriptionsQ_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(database.Subscription))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_Update_Call) Return(err error) *MockSubscriptionsQ_Update_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_Update_Call) RunAndReturn(run func(subscription database.Subscription) error) *MockSubscriptionsQ_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateConfirmationSent provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) UpdateConfirmationSent(id int64, sentAt time.Time) error {
+	ret := _mock.Called(id, sentAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateConfirmationSent")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64, time.Time) error); ok {
+		r0 = returnFunc(id, sentAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_UpdateConfirmationSent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateConfirmationSent'
+type MockSubscriptionsQ_UpdateConfirmationSent_Call struct {
+	*mock.Call
+}
+
+// UpdateConfirmationSent is a helper method to define mock.On call
+//   - id
+//   - sentAt
+func (_e *MockSubscriptionsQ_Expecter) UpdateConfirmationSent(id interface{}, sentAt interface{}) *MockSubscriptionsQ_UpdateConfirmationSent_Call {
+	return &MockSubscriptionsQ_UpdateConfirmationSent_Call{Call: _e.mock.On("UpdateConfirmationSent", id, sentAt)}
+}
+
+func (_c *MockSubscriptionsQ_UpdateConfirmationSent_Call) Run(run func(id int64, sentAt time.Time)) *MockSubscriptionsQ_UpdateConfirmationSent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateConfirmationSent_Call) Return(err error) *MockSubscriptionsQ_UpdateConfirmationSent_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateConfirmationSent_Call) RunAndReturn(run func(id int64, sentAt time.Time) error) *MockSubscriptionsQ_UpdateConfirmationSent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateConfirmed provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) UpdateConfirmed(id int64) error {
+	ret := _mock.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateConfirmed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = returnFunc(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_UpdateConfirmed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateConfirmed'
+type MockSubscriptionsQ_UpdateConfirmed_Call struct {
+	*mock.Call
+}
+
+// UpdateConfirmed is a helper method to define mock.On call
+//   - id
+func (_e *MockSubscriptionsQ_Expecter) UpdateConfirmed(id interface{}) *MockSubscriptionsQ_UpdateConfirmed_Call {
+	return &MockSubscriptionsQ_UpdateConfirmed_Call{Call: _e.mock.On("UpdateConfirmed", id)}
+}
+
+func (_c *MockSubscriptionsQ_UpdateConfirmed_Call) Run(run func(id int64)) *MockSubscriptionsQ_UpdateConfirmed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateConfirmed_Call) Return(err error) *MockSubscriptionsQ_UpdateConfirmed_Call {
+	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockSubscriptionsQ_UpdateConfirmed_Call) RunAndReturn(run func(id int64, unsubscribeToken string) error) *MockSubscriptionsQ_UpdateConfirmed_Call {
+func (_c *MockSubscriptionsQ_UpdateConfirmed_Call) RunAndReturn(run func(id int64) error) *MockSubscriptionsQ_UpdateConfirmed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateFailure provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) UpdateFailure(id int64, lastError string, lease *time.Time) (int, error) {
+	ret := _mock.Called(id, lastError, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateFailure")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(int64, string, *time.Time) (int, error)); ok {
+		return returnFunc(id, lastError, lease)
+	}
+	if returnFunc, ok := ret.Get(0).(func(int64, string, *time.Time) int); ok {
+		r0 = returnFunc(id, lastError, lease)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(int64, string, *time.Time) error); ok {
+		r1 = returnFunc(id, lastError, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsQ_UpdateFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateFailure'
+type MockSubscriptionsQ_UpdateFailure_Call struct {
+	*mock.Call
+}
+
+// UpdateFailure is a helper method to define mock.On call
+//   - id
+//   - lastError
+//   - lease
+func (_e *MockSubscriptionsQ_Expecter) UpdateFailure(id interface{}, lastError interface{}, lease interface{}) *MockSubscriptionsQ_UpdateFailure_Call {
+	return &MockSubscriptionsQ_UpdateFailure_Call{Call: _e.mock.On("UpdateFailure", id, lastError, lease)}
+}
+
+func (_c *MockSubscriptionsQ_UpdateFailure_Call) Run(run func(id int64, lastError string, lease *time.Time)) *MockSubscriptionsQ_UpdateFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(string), args[2].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateFailure_Call) Return(consecutiveFailures int, err error) *MockSubscriptionsQ_UpdateFailure_Call {
+	_c.Call.Return(consecutiveFailures, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateFailure_Call) RunAndReturn(run func(id int64, lastError string, lease *time.Time) (int, error)) *MockSubscriptionsQ_UpdateFailure_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // UpdateLastNotified provides a mock function for the type MockSubscriptionsQ
-func (_mock *MockSubscriptionsQ) UpdateLastNotified(id int64, lastNotifiedAt time.Time) error {
-	ret := _mock.Called(id, lastNotifiedAt)
+func (_mock *MockSubscriptionsQ) UpdateLastNotified(id int64, lease time.Time, lastNotifiedAt time.Time, nextNotifyAt time.Time) error {
+	ret := _mock.Called(id, lease, lastNotifiedAt, nextNotifyAt)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateLastNotified")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(int64, time.Time) error); ok {
-		r0 = returnFunc(id, lastNotifiedAt)
+	if returnFunc, ok := ret.Get(0).(func(int64, time.Time, time.Time, time.Time) error); ok {
+		r0 = returnFunc(id, lease, lastNotifiedAt, nextNotifyAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -364,14 +1048,16 @@ type MockSubscriptionsQ_UpdateLastNotified_Call struct {
 
 // UpdateLastNotified is a helper method to define mock.On call
 //   - id
+//   - lease
 //   - lastNotifiedAt
-func (_e *MockSubscriptionsQ_Expecter) UpdateLastNotified(id interface{}, lastNotifiedAt interface{}) *MockSubscriptionsQ_UpdateLastNotified_Call {
-	return &MockSubscriptionsQ_UpdateLastNotified_Call{Call: _e.mock.On("UpdateLastNotified", id, lastNotifiedAt)}
+//   - nextNotifyAt
+func (_e *MockSubscriptionsQ_Expecter) UpdateLastNotified(id interface{}, lease interface{}, lastNotifiedAt interface{}, nextNotifyAt interface{}) *MockSubscriptionsQ_UpdateLastNotified_Call {
+	return &MockSubscriptionsQ_UpdateLastNotified_Call{Call: _e.mock.On("UpdateLastNotified", id, lease, lastNotifiedAt, nextNotifyAt)}
 }
 
-func (_c *MockSubscriptionsQ_UpdateLastNotified_Call) Run(run func(id int64, lastNotifiedAt time.Time)) *MockSubscriptionsQ_UpdateLastNotified_Call {
+func (_c *MockSubscriptionsQ_UpdateLastNotified_Call) Run(run func(id int64, lease time.Time, lastNotifiedAt time.Time, nextNotifyAt time.Time)) *MockSubscriptionsQ_UpdateLastNotified_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64), args[1].(time.Time))
+		run(args[0].(int64), args[1].(time.Time), args[2].(time.Time), args[3].(time.Time))
 	})
 	return _c
 }
@@ -381,7 +1067,192 @@ func (_c *MockSubscriptionsQ_UpdateLastNotified_Call) Return(err error) *MockSub
 	return _c
 }
 
-func (_c *MockSubscriptionsQ_UpdateLastNotified_Call) RunAndReturn(run func(id int64, lastNotifiedAt time.Time) error) *MockSubscriptionsQ_UpdateLastNotified_Call {
+func (_c *MockSubscriptionsQ_UpdateLastNotified_Call) RunAndReturn(run func(id int64, lease time.Time, lastNotifiedAt time.Time, nextNotifyAt time.Time) error) *MockSubscriptionsQ_UpdateLastNotified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNextRetry provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) UpdateNextRetry(id int64, nextRetryAt time.Time) error {
+	ret := _mock.Called(id, nextRetryAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNextRetry")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64, time.Time) error); ok {
+		r0 = returnFunc(id, nextRetryAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_UpdateNextRetry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNextRetry'
+type MockSubscriptionsQ_UpdateNextRetry_Call struct {
+	*mock.Call
+}
+
+// UpdateNextRetry is a helper method to define mock.On call
+//   - id
+//   - nextRetryAt
+func (_e *MockSubscriptionsQ_Expecter) UpdateNextRetry(id interface{}, nextRetryAt interface{}) *MockSubscriptionsQ_UpdateNextRetry_Call {
+	return &MockSubscriptionsQ_UpdateNextRetry_Call{Call: _e.mock.On("UpdateNextRetry", id, nextRetryAt)}
+}
+
+func (_c *MockSubscriptionsQ_UpdateNextRetry_Call) Run(run func(id int64, nextRetryAt time.Time)) *MockSubscriptionsQ_UpdateNextRetry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateNextRetry_Call) Return(err error) *MockSubscriptionsQ_UpdateNextRetry_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateNextRetry_Call) RunAndReturn(run func(id int64, nextRetryAt time.Time) error) *MockSubscriptionsQ_UpdateNextRetry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateNotifyNow provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) UpdateNotifyNow(id int64) error {
+	ret := _mock.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNotifyNow")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = returnFunc(id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_UpdateNotifyNow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateNotifyNow'
+type MockSubscriptionsQ_UpdateNotifyNow_Call struct {
+	*mock.Call
+}
+
+// UpdateNotifyNow is a helper method to define mock.On call
+//   - id
+func (_e *MockSubscriptionsQ_Expecter) UpdateNotifyNow(id interface{}) *MockSubscriptionsQ_UpdateNotifyNow_Call {
+	return &MockSubscriptionsQ_UpdateNotifyNow_Call{Call: _e.mock.On("UpdateNotifyNow", id)}
+}
+
+func (_c *MockSubscriptionsQ_UpdateNotifyNow_Call) Run(run func(id int64)) *MockSubscriptionsQ_UpdateNotifyNow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateNotifyNow_Call) Return(err error) *MockSubscriptionsQ_UpdateNotifyNow_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateNotifyNow_Call) RunAndReturn(run func(id int64) error) *MockSubscriptionsQ_UpdateNotifyNow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePostponed provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) UpdatePostponed(id int64, lease time.Time, lastError string, nextRetryAt time.Time) error {
+	ret := _mock.Called(id, lease, lastError, nextRetryAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePostponed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64, time.Time, string, time.Time) error); ok {
+		r0 = returnFunc(id, lease, lastError, nextRetryAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_UpdatePostponed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePostponed'
+type MockSubscriptionsQ_UpdatePostponed_Call struct {
+	*mock.Call
+}
+
+// UpdatePostponed is a helper method to define mock.On call
+//   - id
+//   - lease
+//   - lastError
+//   - nextRetryAt
+func (_e *MockSubscriptionsQ_Expecter) UpdatePostponed(id interface{}, lease interface{}, lastError interface{}, nextRetryAt interface{}) *MockSubscriptionsQ_UpdatePostponed_Call {
+	return &MockSubscriptionsQ_UpdatePostponed_Call{Call: _e.mock.On("UpdatePostponed", id, lease, lastError, nextRetryAt)}
+}
+
+func (_c *MockSubscriptionsQ_UpdatePostponed_Call) Run(run func(id int64, lease time.Time, lastError string, nextRetryAt time.Time)) *MockSubscriptionsQ_UpdatePostponed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(time.Time), args[2].(string), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdatePostponed_Call) Return(err error) *MockSubscriptionsQ_UpdatePostponed_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdatePostponed_Call) RunAndReturn(run func(id int64, lease time.Time, lastError string, nextRetryAt time.Time) error) *MockSubscriptionsQ_UpdatePostponed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSuspended provides a mock function for the type MockSubscriptionsQ
+func (_mock *MockSubscriptionsQ) UpdateSuspended(id int64, suspendedAt *time.Time) error {
+	ret := _mock.Called(id, suspendedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSuspended")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(int64, *time.Time) error); ok {
+		r0 = returnFunc(id, suspendedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsQ_UpdateSuspended_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSuspended'
+type MockSubscriptionsQ_UpdateSuspended_Call struct {
+	*mock.Call
+}
+
+// UpdateSuspended is a helper method to define mock.On call
+//   - id
+//   - suspendedAt
+func (_e *MockSubscriptionsQ_Expecter) UpdateSuspended(id interface{}, suspendedAt interface{}) *MockSubscriptionsQ_UpdateSuspended_Call {
+	return &MockSubscriptionsQ_UpdateSuspended_Call{Call: _e.mock.On("UpdateSuspended", id, suspendedAt)}
+}
+
+func (_c *MockSubscriptionsQ_UpdateSuspended_Call) Run(run func(id int64, suspendedAt *time.Time)) *MockSubscriptionsQ_UpdateSuspended_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(int64), args[1].(*time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateSuspended_Call) Return(err error) *MockSubscriptionsQ_UpdateSuspended_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsQ_UpdateSuspended_Call) RunAndReturn(run func(id int64, suspendedAt *time.Time) error) *MockSubscriptionsQ_UpdateSuspended_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type NotificationStatus string
+
+const (
+	NotificationStatusPending NotificationStatus = "pending"
+	NotificationStatusSent    NotificationStatus = "sent"
+	// NotificationStatusFailed is terminal, the notification is not retried anymore
+	NotificationStatusFailed NotificationStatus = "failed"
+)
+
+var ErrNotificationExists = errors.New("notification already exists")
+
+type NotificationsQ interface {
+	// New creates a new instance of NotificationsQ (separate conn)
+	New() NotificationsQ
+	// Insert returns ErrNotificationExists if the idempotency key is already taken,
+	// it does not abort the surrounding transaction
+	Insert(notification Notification) (id int64, err error)
+	// SelectPending claims up to limit pending notifications due for the delivery attempt, the oldest first.
+	// The claim postpones the next attempt by the lease, so other instances skip them until the attempt
+	// outcome is recorded or the lease expires.
+	SelectPending(limit uint64, lease time.Duration) ([]Notification, error)
+	UpdateSent(id int64, attempts int, sentAt time.Time) error
+	UpdateRetry(id int64, attempts int, lastError string, nextAttemptAt time.Time) error
+	UpdateFailed(id int64, attempts int, lastError string) error
+	// SelectBySubscription returns up to limit latest notifications of the subscription, the merged ones included,
+	// the newest first
+	SelectBySubscription(subscriptionId int64, limit uint64) ([]Notification, error)
+}
+
+type Notification struct {
+	Id int64 `structs:"-" db:"id"`
+	// SubscriptionId is nil once the subscription is deleted
+	SubscriptionId *int64 `structs:"subscription_id" db:"subscription_id"`
+	// MergedSubscriptionIds are the other subscriptions delivered with the merged email besides SubscriptionId
+	MergedSubscriptionIds SubscriptionIds `structs:"merged_subscription_ids" db:"merged_subscription_ids"`
+	// AlertRuleId is set instead of SubscriptionId for the alerts, it is nil once the rule is deleted
+	AlertRuleId    *int64             `structs:"alert_rule_id" db:"alert_rule_id"`
+	IdempotencyKey string             `structs:"idempotency_key" db:"idempotency_key"`
+	Email          string             `structs:"email" db:"email"`
+	Subject        string             `structs:"subject" db:"subject"`
+	Body           string             `structs:"body" db:"body"`
+	TextBody       string             `structs:"text_body" db:"text_body"`
+	Headers        MessageHeaders     `structs:"headers" db:"headers"`
+	InlineImages   MessageImages      `structs:"inline_images" db:"inline_images"`
+	Status         NotificationStatus `structs:"status" db:"status"`
+	Attempts       int                `structs:"attempts" db:"attempts"`
+	LastError      *string            `structs:"last_error" db:"last_error"`
+	NextAttemptAt  time.Time          `structs:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt      time.Time          `structs:"created_at" db:"created_at"`
+	SentAt         *time.Time         `structs:"sent_at" db:"sent_at"`
+}
+
+// MessageHeaders are the custom headers of the email, stored as a JSON object
+type MessageHeaders map[string]string
+
+func (h MessageHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(h)
+}
+
+func (h *MessageHeaders) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*h = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, h)
+	case string:
+		return json.Unmarshal([]byte(src), h)
+	default:
+		return fmt.Errorf("unsupported headers type %T", src)
+	}
+}
+
+// MessageImages are the content IDs of the images attached to the email inline, stored as a JSON array
+type MessageImages []string
+
+func (i MessageImages) Value() (driver.Value, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(i)
+}
+
+func (i *MessageImages) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*i = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, i)
+	case string:
+		return json.Unmarshal([]byte(src), i)
+	default:
+		return fmt.Errorf("unsupported inline images type %T", src)
+	}
+}
+
+// SubscriptionIds are the ids of the subscriptions, stored as a JSON array
+type SubscriptionIds []int64
+
+func (i SubscriptionIds) Value() (driver.Value, error) {
+	if i == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(i)
+}
+
+func (i *SubscriptionIds) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*i = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(src, i)
+	case string:
+		return json.Unmarshal([]byte(src), i)
+	default:
+		return fmt.Errorf("unsupported subscription ids type %T", src)
+	}
+}
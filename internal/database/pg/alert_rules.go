@@ -0,0 +1,227 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/fatih/structs"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+const (
+	alertRulesTable = "alert_rules"
+
+	columnTriggered     = "triggered"
+	columnLastCheckedAt = "last_checked_at"
+
+	constraintUniqueAlertRule = "unique_alert_rule"
+)
+
+type alertRulesQ struct {
+	db *pgdb.DB
+}
+
+func NewAlertRulesQ(db *pgdb.DB) database.AlertRulesQ {
+	return &alertRulesQ{
+		db: db,
+	}
+}
+
+func (q *alertRulesQ) New() database.AlertRulesQ {
+	return NewAlertRulesQ(q.db.Clone())
+}
+
+func (q *alertRulesQ) Insert(rule database.AlertRule) (id int64, err error) {
+	stmt := squirrel.
+		Insert(alertRulesTable).
+		SetMap(structs.Map(rule)).
+		Suffix("RETURNING id")
+
+	err = q.db.Get(&id, stmt)
+	if pgdb.IsConstraintErr(err, constraintUniqueAlertRule) {
+		return 0, database.ErrAlertRuleExists
+	}
+
+	return
+}
+
+func (q *alertRulesQ) GetByToken(token string, purpose database.TokenPurpose) (*database.AlertRule, error) {
+	stmt := squirrel.
+		Select("*").
+		From(alertRulesTable).
+		Where(squirrel.Expr("id = (?)", tokenOwner(columnAlertRuleId, token, purpose)))
+
+	var rule database.AlertRule
+	err := q.db.Get(&rule, stmt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &rule, err
+}
+
+func (q *alertRulesQ) GetByCondition(rule database.AlertRule) (*database.AlertRule, error) {
+	// matches the unique_alert_rule constraint
+	stmt := squirrel.
+		Select("*").
+		From(alertRulesTable).
+		Where(squirrel.Eq{
+			columnEmail:   rule.Email,
+			"city":        rule.City,
+			"location_id": rule.LocationId,
+			"metric":      rule.Metric,
+			"operator":    rule.Operator,
+			"threshold":   rule.Threshold,
+			"day_offset":  rule.DayOffset,
+		})
+
+	var existing database.AlertRule
+	err := q.db.Get(&existing, stmt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &existing, err
+}
+
+func (q *alertRulesQ) SelectUnconfirmed(email string) ([]database.AlertRule, error) {
+	stmt := squirrel.
+		Select("*").
+		From(alertRulesTable).
+		Where(squirrel.Eq{
+			columnEmail:     email,
+			columnConfirmed: false,
+		}).
+		OrderBy(columnId)
+
+	var rules []database.AlertRule
+	if err := q.db.Select(&rules, stmt); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (q *alertRulesQ) UpdateConfirmed(id int64) error {
+	stmt := squirrel.
+		Update(alertRulesTable).
+		Set(columnConfirmed, true).
+		Where(squirrel.Eq{
+			columnId:        id,
+			columnConfirmed: false,
+		})
+
+	if result, err := q.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+func (q *alertRulesQ) UpdateConfirmationSent(id int64, sentAt time.Time) error {
+	stmt := squirrel.
+		Update(alertRulesTable).
+		Set(columnConfirmationSentAt, sentAt).
+		Where(squirrel.Eq{
+			columnId:        id,
+			columnConfirmed: false,
+		})
+
+	if result, err := q.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+func (q *alertRulesQ) DeleteByToken(token string) error {
+	stmt := squirrel.
+		Delete(alertRulesTable).
+		Where(squirrel.Expr("id = (?)", tokenOwner(columnAlertRuleId, token, database.TokenPurposeUnsubscribe)))
+
+	if result, err := q.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+func (q *alertRulesQ) Delete(id int64) error {
+	stmt := squirrel.
+		Delete(alertRulesTable).
+		Where(squirrel.Eq{columnId: id})
+
+	if result, err := q.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+func (q *alertRulesQ) DeleteUnconfirmed(sentBefore time.Time) (int64, error) {
+	stmt := squirrel.
+		Delete(alertRulesTable).
+		Where(squirrel.Eq{columnConfirmed: false}).
+		Where(squirrel.Lt{columnConfirmationSentAt: sentBefore})
+
+	result, err := q.db.ExecWithResult(stmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (q *alertRulesQ) SelectToEvaluate(limit uint64, lease time.Duration, checkedBefore time.Time) ([]database.AlertRule, error) {
+	due := squirrel.
+		Select(columnId).
+		From(alertRulesTable).
+		Where(squirrel.Eq{columnConfirmed: true}).
+		Where(squirrel.Or{
+			squirrel.Eq{columnLeaseExpiresAt: nil},
+			squirrel.Expr("lease_expires_at <= CURRENT_TIMESTAMP"),
+		}).
+		Where(squirrel.Or{
+			squirrel.Eq{columnLastCheckedAt: nil},
+			squirrel.Lt{columnLastCheckedAt: checkedBefore},
+		}).
+		OrderBy(columnId).
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	stmt := squirrel.
+		Update(alertRulesTable).
+		Set(columnLeaseExpiresAt, squirrel.Expr("CURRENT_TIMESTAMP + ?::interval", fmt.Sprintf("%d milliseconds", lease.Milliseconds()))).
+		Where(squirrel.Expr("id IN (?)", due)).
+		Suffix("RETURNING *")
+
+	var rules []database.AlertRule
+	if err := q.db.Select(&rules, stmt); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (q *alertRulesQ) UpdateEvaluated(id int64, triggered bool, checkedAt time.Time) error {
+	stmt := squirrel.
+		Update(alertRulesTable).
+		Set(columnTriggered, triggered).
+		Set(columnLastCheckedAt, checkedAt).
+		Set(columnLeaseExpiresAt, nil).
+		Where(squirrel.Eq{columnId: id})
+
+	return q.db.Exec(stmt)
+}
@@ -17,10 +17,26 @@ func (d *db) New() database.Database {
 	return NewDatabase(d.db.Clone())
 }
 
+func (d *db) SubscribersQ() database.SubscribersQ {
+	return NewSubscribersQ(d.db)
+}
+
 func (d *db) SubscriptionsQ() database.SubscriptionsQ {
 	return NewSubscriptionsQ(d.db)
 }
 
+func (d *db) NotificationsQ() database.NotificationsQ {
+	return NewNotificationsQ(d.db)
+}
+
+func (d *db) AlertRulesQ() database.AlertRulesQ {
+	return NewAlertRulesQ(d.db)
+}
+
+func (d *db) TokensQ() database.TokensQ {
+	return NewTokensQ(d.db)
+}
+
 func (d *db) Transaction(fn func() error) error {
 	return d.db.Transaction(fn)
 }
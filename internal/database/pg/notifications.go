@@ -0,0 +1,128 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/fatih/structs"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+const (
+	notificationsTable = "notifications"
+
+	columnStatus        = "status"
+	columnAttempts      = "attempts"
+	columnLastError     = "last_error"
+	columnNextAttemptAt = "next_attempt_at"
+	columnSentAt        = "sent_at"
+)
+
+type notificationsQ struct {
+	db *pgdb.DB
+}
+
+func NewNotificationsQ(db *pgdb.DB) database.NotificationsQ {
+	return &notificationsQ{
+		db: db,
+	}
+}
+
+func (q *notificationsQ) New() database.NotificationsQ {
+	return NewNotificationsQ(q.db.Clone())
+}
+
+func (q *notificationsQ) Insert(notification database.Notification) (id int64, err error) {
+	// "ON CONFLICT DO NOTHING" instead of catching the constraint error, as the latter aborts the transaction
+	stmt := squirrel.
+		Insert(notificationsTable).
+		SetMap(structs.Map(notification)).
+		Suffix("ON CONFLICT ON CONSTRAINT unique_idempotency_key DO NOTHING RETURNING id")
+
+	err = q.db.Get(&id, stmt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, database.ErrNotificationExists
+	}
+
+	return
+}
+
+func (q *notificationsQ) SelectPending(limit uint64, lease time.Duration) ([]database.Notification, error) {
+	due := squirrel.
+		Select(columnId).
+		From(notificationsTable).
+		Where(squirrel.Eq{columnStatus: database.NotificationStatusPending}).
+		Where(squirrel.Expr("next_attempt_at <= CURRENT_TIMESTAMP")).
+		OrderBy(columnNextAttemptAt).
+		Limit(limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	stmt := squirrel.
+		Update(notificationsTable).
+		Set(columnNextAttemptAt, squirrel.Expr("CURRENT_TIMESTAMP + ?::interval", fmt.Sprintf("%d milliseconds", lease.Milliseconds()))).
+		Where(squirrel.Expr("id IN (?)", due)).
+		Suffix("RETURNING *")
+
+	var notifications []database.Notification
+	if err := q.db.Select(&notifications, stmt); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (q *notificationsQ) UpdateSent(id int64, attempts int, sentAt time.Time) error {
+	stmt := squirrel.
+		Update(notificationsTable).
+		Set(columnStatus, database.NotificationStatusSent).
+		Set(columnAttempts, attempts).
+		Set(columnSentAt, sentAt).
+		Where(squirrel.Eq{columnId: id})
+
+	return q.db.Exec(stmt)
+}
+
+func (q *notificationsQ) UpdateRetry(id int64, attempts int, lastError string, nextAttemptAt time.Time) error {
+	stmt := squirrel.
+		Update(notificationsTable).
+		Set(columnAttempts, attempts).
+		Set(columnLastError, lastError).
+		Set(columnNextAttemptAt, nextAttemptAt).
+		Where(squirrel.Eq{columnId: id})
+
+	return q.db.Exec(stmt)
+}
+
+func (q *notificationsQ) UpdateFailed(id int64, attempts int, lastError string) error {
+	stmt := squirrel.
+		Update(notificationsTable).
+		Set(columnStatus, database.NotificationStatusFailed).
+		Set(columnAttempts, attempts).
+		Set(columnLastError, lastError).
+		Where(squirrel.Eq{columnId: id})
+
+	return q.db.Exec(stmt)
+}
+
+func (q *notificationsQ) SelectBySubscription(subscriptionId int64, limit uint64) ([]database.Notification, error) {
+	stmt := squirrel.
+		Select("*").
+		From(notificationsTable).
+		Where(squirrel.Or{
+			squirrel.Eq{columnSubscriptionId: subscriptionId},
+			squirrel.Expr("merged_subscription_ids @> ?::jsonb", fmt.Sprintf("[%d]", subscriptionId)),
+		}).
+		OrderBy(columnCreatedAt+" DESC", columnId+" DESC").
+		Limit(limit)
+
+	var notifications []database.Notification
+	if err := q.db.Select(&notifications, stmt); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
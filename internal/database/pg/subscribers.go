@@ -0,0 +1,97 @@
+package pg
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+const (
+	subscribersTable = "subscribers"
+
+	columnEmail        = "email"
+	columnSubscriberId = "subscriber_id"
+)
+
+type subscribersQ struct {
+	db *pgdb.DB
+}
+
+func NewSubscribersQ(db *pgdb.DB) database.SubscribersQ {
+	return &subscribersQ{
+		db: db,
+	}
+}
+
+func (q *subscribersQ) New() database.SubscribersQ {
+	return NewSubscribersQ(q.db.Clone())
+}
+
+func (q *subscribersQ) GetOrInsert(email string) (*database.Subscriber, error) {
+	// the no-op update makes the existing row returned, and locks it till the end of the transaction,
+	// so the concurrent subscriptions of the same email are serialized
+	stmt := squirrel.
+		Insert(subscribersTable).
+		Columns(columnEmail).
+		Values(email).
+		Suffix("ON CONFLICT (email) DO UPDATE SET email = EXCLUDED.email RETURNING *")
+
+	var subscriber database.Subscriber
+	if err := q.db.Get(&subscriber, stmt); err != nil {
+		return nil, err
+	}
+
+	return &subscriber, nil
+}
+
+func (q *subscribersQ) GetByEmail(email string) (*database.Subscriber, error) {
+	stmt := squirrel.
+		Select("*").
+		From(subscribersTable).
+		Where(squirrel.Eq{columnEmail: email})
+
+	var subscriber database.Subscriber
+	err := q.db.Get(&subscriber, stmt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &subscriber, err
+}
+
+func (q *subscribersQ) UpdateConfirmed(id int64) error {
+	stmt := squirrel.
+		Update(subscribersTable).
+		Set(columnConfirmed, true).
+		Where(squirrel.Eq{
+			columnId:        id,
+			columnConfirmed: false,
+		})
+
+	return q.db.Exec(stmt)
+}
+
+func (q *subscribersQ) DeleteUnused(id int64) error {
+	stmt := squirrel.
+		Delete(subscribersTable).
+		Where(squirrel.Eq{columnId: id}).
+		Where(squirrel.Expr("NOT EXISTS (SELECT 1 FROM subscriptions WHERE subscriber_id = ?)", id))
+
+	return q.db.Exec(stmt)
+}
+
+func (q *subscribersQ) PurgeUnused() (int64, error) {
+	stmt := squirrel.
+		Delete(subscribersTable).
+		Where(squirrel.Expr("NOT EXISTS (SELECT 1 FROM subscriptions WHERE subscriber_id = subscribers.id)"))
+
+	result, err := q.db.ExecWithResult(stmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
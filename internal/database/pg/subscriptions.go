@@ -3,6 +3,8 @@ package pg
 import (
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/squirrel"
@@ -16,10 +18,22 @@ const (
 
 	columnId             = "id"
 	columnConfirmed      = "confirmed"
-	columnToken          = "token"
 	columnLastNotifiedAt = "last_notified_at"
+	columnNextNotifyAt   = "next_notify_at"
+	columnCreatedAt      = "created_at"
 
-	constraintUniqueEmail = "unique_email"
+	columnConsecutiveFailures = "consecutive_failures"
+	columnNextRetryAt         = "next_retry_at"
+	columnSuspendedAt         = "suspended_at"
+	columnLeaseExpiresAt      = "lease_expires_at"
+	columnPausedAt            = "paused_at"
+
+	columnConfirmationSentAt = "confirmation_sent_at"
+
+	constraintUniqueSubscriptionLocation = "unique_subscription_location"
+
+	// subscriptionEmail selects the email of the subscriber along with the subscription columns
+	subscriptionEmail = "(SELECT email FROM subscribers WHERE subscribers.id = subscriptions.subscriber_id) AS email"
 )
 
 type subscriptionsQ struct {
@@ -43,18 +57,52 @@ func (s *subscriptionsQ) Insert(subscription database.Subscription) (id int64, e
 		Suffix("RETURNING id")
 
 	err = s.db.Get(&id, stmt)
-	if pgdb.IsConstraintErr(err, constraintUniqueEmail) {
+	if pgdb.IsConstraintErr(err, constraintUniqueSubscriptionLocation) {
 		return 0, database.ErrSubscriptionExists
 	}
 
 	return
 }
 
-func (s *subscriptionsQ) GetByToken(token string) (*database.Subscription, error) {
+func (s *subscriptionsQ) GetByToken(token string, purpose database.TokenPurpose) (*database.Subscription, error) {
+	stmt := squirrel.
+		Select("*", subscriptionEmail).
+		From(subscriptionsTable).
+		Where(squirrel.Expr("id = (?)", tokenOwner(columnSubscriptionId, token, purpose)))
+
+	var subscription database.Subscription
+	err := s.db.Get(&subscription, stmt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &subscription, err
+}
+
+func (s *subscriptionsQ) GetById(id int64) (*database.Subscription, error) {
+	stmt := squirrel.
+		Select("*", subscriptionEmail).
+		From(subscriptionsTable).
+		Where(squirrel.Eq{columnId: id})
+
+	var subscription database.Subscription
+	err := s.db.Get(&subscription, stmt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+
+	return &subscription, err
+}
+
+func (s *subscriptionsQ) GetByLocation(subscriberId int64, city, locationId string) (*database.Subscription, error) {
 	stmt := squirrel.
-		Select("*").
+		Select("*", subscriptionEmail).
 		From(subscriptionsTable).
-		Where(squirrel.Eq{columnToken: token})
+		Where(squirrel.Eq{
+			columnSubscriberId: subscriberId,
+			"city":             city,
+			"location_id":      locationId,
+		})
 
 	var subscription database.Subscription
 	err := s.db.Get(&subscription, stmt)
@@ -65,11 +113,62 @@ func (s *subscriptionsQ) GetByToken(token string) (*database.Subscription, error
 	return &subscription, err
 }
 
-func (s *subscriptionsQ) UpdateConfirmed(id int64, unsubscribeToken string) error {
+func (s *subscriptionsQ) SelectUnconfirmed(subscriberId int64) ([]database.Subscription, error) {
+	stmt := squirrel.
+		Select("*", subscriptionEmail).
+		From(subscriptionsTable).
+		Where(squirrel.Eq{
+			columnSubscriberId: subscriberId,
+			columnConfirmed:    false,
+		}).
+		OrderBy(columnId)
+
+	var subscriptions []database.Subscription
+	if err := s.db.Select(&subscriptions, stmt); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (s *subscriptionsQ) Update(subscription database.Subscription) error {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		SetMap(map[string]interface{}{
+			columnSubscriberId: subscription.SubscriberId,
+			"city":             subscription.City,
+			"location_id":      subscription.LocationId,
+			"region":           subscription.Region,
+			"country":          subscription.Country,
+			"latitude":         subscription.Latitude,
+			"longitude":        subscription.Longitude,
+			"timezone":         subscription.Timezone,
+			"delivery_hour":    subscription.DeliveryHour,
+			"frequency":        subscription.Frequency,
+			"schedule":         subscription.Schedule,
+			"language":         subscription.Language,
+			"units":            subscription.Units,
+			columnNextNotifyAt: subscription.NextNotifyAt,
+			columnPausedAt:     subscription.PausedAt,
+			columnConfirmed:    subscription.Confirmed,
+
+			columnConfirmationSentAt: subscription.ConfirmationSentAt,
+		}).
+		// the delivery state (failures, lease, ...) is left to the notificator
+		Where(squirrel.Eq{columnId: subscription.Id})
+
+	err := s.db.Exec(stmt)
+	if pgdb.IsConstraintErr(err, constraintUniqueSubscriptionLocation) {
+		return database.ErrSubscriptionExists
+	}
+
+	return err
+}
+
+func (s *subscriptionsQ) UpdateConfirmed(id int64) error {
 	stmt := squirrel.
 		Update(subscriptionsTable).
 		Set(columnConfirmed, true).
-		Set(columnToken, unsubscribeToken).
 		Where(squirrel.Eq{
 			columnId:        id,
 			columnConfirmed: false, // generally can be omitted, but still
@@ -89,10 +188,28 @@ func (s *subscriptionsQ) UpdateConfirmed(id int64, unsubscribeToken string) erro
 	return nil
 }
 
+func (s *subscriptionsQ) UpdateConfirmationSent(id int64, sentAt time.Time) error {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnConfirmationSentAt, sentAt).
+		Where(squirrel.Eq{
+			columnId:        id,
+			columnConfirmed: false,
+		})
+
+	if result, err := s.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
 func (s *subscriptionsQ) DeleteByToken(token string) error {
 	stmt := squirrel.
 		Delete(subscriptionsTable).
-		Where(squirrel.Eq{columnToken: token})
+		Where(squirrel.Expr("id = (?)", tokenOwner(columnSubscriptionId, token, database.TokenPurposeUnsubscribe)))
 
 	if result, err := s.db.ExecWithResult(stmt); err != nil {
 		return err
@@ -103,22 +220,75 @@ func (s *subscriptionsQ) DeleteByToken(token string) error {
 	}
 }
 
-func (s *subscriptionsQ) SelectToNotify() ([]database.Subscription, error) {
+func (s *subscriptionsQ) Delete(id int64) error {
 	stmt := squirrel.
-		Select("*").
+		Delete(subscriptionsTable).
+		Where(squirrel.Eq{columnId: id})
+
+	if result, err := s.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrNoRowsAffected
+	} else {
+		return nil
+	}
+}
+
+func (s *subscriptionsQ) DeleteBySubscriber(subscriberId int64) (int64, error) {
+	stmt := squirrel.
+		Delete(subscriptionsTable).
+		Where(squirrel.Eq{columnSubscriberId: subscriberId})
+
+	result, err := s.db.ExecWithResult(stmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (s *subscriptionsQ) DeleteUnconfirmed(sentBefore time.Time) (int64, error) {
+	stmt := squirrel.
+		Delete(subscriptionsTable).
+		Where(squirrel.Eq{columnConfirmed: false}).
+		Where(squirrel.Lt{columnConfirmationSentAt: sentBefore})
+
+	result, err := s.db.ExecWithResult(stmt)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func (s *subscriptionsQ) SelectToNotify(limit uint64, lease time.Duration) ([]database.Subscription, error) {
+	due := squirrel.
+		Select(columnId).
 		From(subscriptionsTable).
-		Where(squirrel.Eq{columnConfirmed: true}).
+		Where(squirrel.Eq{
+			columnConfirmed:   true,
+			columnSuspendedAt: nil,
+			columnPausedAt:    nil,
+		}).
 		Where(squirrel.Or{
-			squirrel.Eq{columnLastNotifiedAt: nil},
-			squirrel.Expr(`
-                last_notified_at <= CURRENT_TIMESTAMP - (
-					CASE frequency
-						WHEN 'hourly' THEN INTERVAL '1 hour'
-						ELSE INTERVAL '24 hours'
-					END
-				)
-            `),
-		})
+			squirrel.Eq{columnLeaseExpiresAt: nil},
+			squirrel.Expr("lease_expires_at <= CURRENT_TIMESTAMP"),
+		}).
+		Where(squirrel.Or{
+			squirrel.Eq{columnNextRetryAt: nil},
+			squirrel.Expr("next_retry_at <= CURRENT_TIMESTAMP"),
+		}).
+		Where(squirrel.Expr("next_notify_at <= CURRENT_TIMESTAMP")).
+		OrderBy(columnSubscriberId, columnId).
+		Limit(limit).
+		// rows being claimed by other instances are skipped instead of waiting for them
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnLeaseExpiresAt, squirrel.Expr("CURRENT_TIMESTAMP + ?::interval", fmt.Sprintf("%d milliseconds", lease.Milliseconds()))).
+		Where(squirrel.Expr("id IN (?)", due)).
+		Suffix("RETURNING *, " + subscriptionEmail)
 
 	var subscriptions []database.Subscription
 	if err := s.db.Select(&subscriptions, stmt); err != nil {
@@ -128,11 +298,177 @@ func (s *subscriptionsQ) SelectToNotify() ([]database.Subscription, error) {
 	return subscriptions, nil
 }
 
-func (s *subscriptionsQ) UpdateLastNotified(id int64, lastNotifiedAt time.Time) error {
+func (s *subscriptionsQ) UpdateLastNotified(id int64, lease time.Time, lastNotifiedAt, nextNotifyAt time.Time) error {
 	stmt := squirrel.
 		Update(subscriptionsTable).
 		Set(columnLastNotifiedAt, lastNotifiedAt).
+		Set(columnNextNotifyAt, nextNotifyAt).
+		Set(columnLeaseExpiresAt, nil).
+		Where(squirrel.Eq{columnId: id}).
+		Where(leaseHeld(lease))
+
+	if result, err := s.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (s *subscriptionsQ) UpdateFailure(id int64, lastError string, lease *time.Time) (consecutiveFailures int, err error) {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnConsecutiveFailures, squirrel.Expr("consecutive_failures + 1")).
+		Set(columnLastError, lastError).
+		Where(squirrel.Eq{columnId: id}).
+		Suffix("RETURNING consecutive_failures")
+	if lease != nil {
+		stmt = stmt.
+			Set(columnLeaseExpiresAt, nil).
+			Where(leaseHeld(*lease))
+	}
+
+	err = s.db.Get(&consecutiveFailures, stmt)
+	if errors.Is(err, sql.ErrNoRows) {
+		if lease != nil {
+			return 0, database.ErrLeaseLost
+		}
+		return 0, database.ErrNoRowsAffected
+	}
+
+	return
+}
+
+// leaseHeld matches the subscription only while the lease claimed by SelectToNotify is still in effect,
+// the expired lease may be claimed by another instance at any moment
+func leaseHeld(lease time.Time) squirrel.Sqlizer {
+	return squirrel.And{
+		squirrel.Eq{columnLeaseExpiresAt: lease},
+		squirrel.Expr("lease_expires_at > CURRENT_TIMESTAMP"),
+	}
+}
+
+func (s *subscriptionsQ) UpdateNextRetry(id int64, nextRetryAt time.Time) error {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnNextRetryAt, nextRetryAt).
+		Where(squirrel.Eq{columnId: id})
+
+	return s.db.Exec(stmt)
+}
+
+func (s *subscriptionsQ) UpdatePostponed(id int64, lease time.Time, lastError string, nextRetryAt time.Time) error {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnLastError, lastError).
+		Set(columnNextRetryAt, nextRetryAt).
+		Set(columnLeaseExpiresAt, nil).
+		Where(squirrel.Eq{columnId: id}).
+		Where(leaseHeld(lease))
+
+	if result, err := s.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrLeaseLost
+	}
+
+	return nil
+}
+
+func (s *subscriptionsQ) UpdateSuspended(id int64, suspendedAt *time.Time) error {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnSuspendedAt, suspendedAt).
 		Where(squirrel.Eq{columnId: id})
 
 	return s.db.Exec(stmt)
 }
+
+func (s *subscriptionsQ) ResetFailures(id int64) error {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnConsecutiveFailures, 0).
+		Set(columnNextRetryAt, nil).
+		Set(columnLastError, nil).
+		Where(squirrel.Eq{columnId: id}).
+		// avoiding the needless writes for the healthy subscriptions
+		Where(squirrel.Gt{columnConsecutiveFailures: 0})
+
+	return s.db.Exec(stmt)
+}
+
+func (s *subscriptionsQ) SelectSuspended() ([]database.Subscription, error) {
+	stmt := squirrel.
+		Select("*", subscriptionEmail).
+		From(subscriptionsTable).
+		Where(squirrel.NotEq{columnSuspendedAt: nil}).
+		OrderBy(columnSuspendedAt + " DESC")
+
+	var subscriptions []database.Subscription
+	if err := s.db.Select(&subscriptions, stmt); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (s *subscriptionsQ) Select(filter database.SubscriptionsFilter, limit, offset uint64) ([]database.Subscription, error) {
+	stmt := applySubscriptionsFilter(squirrel.Select("*", subscriptionEmail).From(subscriptionsTable), filter).
+		OrderBy(columnCreatedAt+" DESC", columnId+" DESC").
+		Limit(limit).
+		Offset(offset)
+
+	var subscriptions []database.Subscription
+	if err := s.db.Select(&subscriptions, stmt); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (s *subscriptionsQ) Count(filter database.SubscriptionsFilter) (count int64, err error) {
+	stmt := applySubscriptionsFilter(squirrel.Select("COUNT(*)").From(subscriptionsTable), filter)
+
+	err = s.db.Get(&count, stmt)
+	return
+}
+
+func (s *subscriptionsQ) UpdateNotifyNow(id int64) error {
+	stmt := squirrel.
+		Update(subscriptionsTable).
+		Set(columnNextNotifyAt, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Set(columnNextRetryAt, nil).
+		Where(squirrel.Eq{columnId: id})
+
+	if result, err := s.db.ExecWithResult(stmt); err != nil {
+		return err
+	} else if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return database.ErrNoRowsAffected
+	}
+
+	return nil
+}
+
+func applySubscriptionsFilter(stmt squirrel.SelectBuilder, filter database.SubscriptionsFilter) squirrel.SelectBuilder {
+	if filter.Search != "" {
+		// the wildcards of the search are matched literally
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		stmt = stmt.Where(squirrel.Or{
+			squirrel.ILike{"city": pattern},
+			squirrel.Expr("subscriber_id IN (SELECT id FROM subscribers WHERE email ILIKE ?)", pattern),
+		})
+	}
+	if filter.Confirmed != nil {
+		stmt = stmt.Where(squirrel.Eq{columnConfirmed: *filter.Confirmed})
+	}
+	if filter.Suspended != nil && *filter.Suspended {
+		stmt = stmt.Where(squirrel.NotEq{columnSuspendedAt: nil})
+	} else if filter.Suspended != nil {
+		stmt = stmt.Where(squirrel.Eq{columnSuspendedAt: nil})
+	}
+
+	return stmt
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
@@ -0,0 +1,72 @@
+package pg
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/fatih/structs"
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+const (
+	tokensTable = "tokens"
+
+	columnHash           = "hash"
+	columnPurpose        = "purpose"
+	columnSubscriptionId = "subscription_id"
+	columnAlertRuleId    = "alert_rule_id"
+)
+
+type tokensQ struct {
+	db *pgdb.DB
+}
+
+func NewTokensQ(db *pgdb.DB) database.TokensQ {
+	return &tokensQ{
+		db: db,
+	}
+}
+
+func (q *tokensQ) New() database.TokensQ {
+	return NewTokensQ(q.db.Clone())
+}
+
+func (q *tokensQ) Insert(token database.Token) error {
+	stmt := squirrel.
+		Insert(tokensTable).
+		SetMap(structs.Map(token))
+
+	return q.db.Exec(stmt)
+}
+
+func (q *tokensQ) DeleteBySubscription(subscriptionId int64, purpose database.TokenPurpose) error {
+	stmt := squirrel.
+		Delete(tokensTable).
+		Where(squirrel.Eq{
+			columnSubscriptionId: subscriptionId,
+			columnPurpose:        purpose,
+		})
+
+	return q.db.Exec(stmt)
+}
+
+func (q *tokensQ) DeleteByAlertRule(alertRuleId int64, purpose database.TokenPurpose) error {
+	stmt := squirrel.
+		Delete(tokensTable).
+		Where(squirrel.Eq{
+			columnAlertRuleId: alertRuleId,
+			columnPurpose:     purpose,
+		})
+
+	return q.db.Exec(stmt)
+}
+
+// tokenOwner selects the id of the owner of the token with the purpose, column is either subscription_id or alert_rule_id
+func tokenOwner(column, token string, purpose database.TokenPurpose) squirrel.SelectBuilder {
+	return squirrel.
+		Select(column).
+		From(tokensTable).
+		Where(squirrel.Eq{
+			columnHash:    database.HashToken(token),
+			columnPurpose: purpose,
+		})
+}
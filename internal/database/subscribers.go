@@ -0,0 +1,26 @@
+package database
+
+import "time"
+
+// SubscribersQ manages the email identities, a subscriber owns one or several subscriptions
+type SubscribersQ interface {
+	// New creates a new instance of SubscribersQ (separate conn)
+	New() SubscribersQ
+	// GetOrInsert returns the subscriber of the email, creating an unconfirmed one if there is none
+	GetOrInsert(email string) (subscriber *Subscriber, err error)
+	// GetByEmail returns nil if there is no subscriber with the email
+	GetByEmail(email string) (subscriber *Subscriber, err error)
+	// UpdateConfirmed marks the email as verified, it is a no-op for the verified one
+	UpdateConfirmed(id int64) error
+	// DeleteUnused deletes the subscriber if it has no subscriptions left, so the address is verified again on return
+	DeleteUnused(id int64) error
+	// PurgeUnused deletes all the subscribers without subscriptions, e.g. after the unconfirmed ones are purged
+	PurgeUnused() (deleted int64, err error)
+}
+
+type Subscriber struct {
+	Id        int64     `structs:"-" db:"id"`
+	Email     string    `structs:"email" db:"email"`
+	Confirmed bool      `structs:"confirmed" db:"confirmed"`
+	CreatedAt time.Time `structs:"created_at" db:"created_at"`
+}
@@ -2,48 +2,169 @@ package database
 
 import (
 	"errors"
+	"fmt"
 	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 )
 
 type SubscriptionFrequency string
 
 func (f SubscriptionFrequency) Valid() bool {
 	switch f {
-	case SubscriptionFrequencyDaily, SubscriptionFrequencyHourly:
+	case SubscriptionFrequencyDaily, SubscriptionFrequencyHourly, SubscriptionFrequencyWeekly, SubscriptionFrequencyCustom:
 		return true
 	default:
 		return false
 	}
 }
 
+// Schedule returns the cron schedule of the frequency, the daily and weekly ones are delivered
+// at the deliveryHour (weekly ones on Sundays). The custom frequency has no predefined schedule.
+func (f SubscriptionFrequency) Schedule(deliveryHour int) string {
+	switch f {
+	case SubscriptionFrequencyHourly:
+		return "0 * * * *"
+	case SubscriptionFrequencyDaily:
+		return fmt.Sprintf("0 %d * * *", deliveryHour)
+	case SubscriptionFrequencyWeekly:
+		return fmt.Sprintf("0 %d * * 0", deliveryHour)
+	default:
+		return ""
+	}
+}
+
 const (
 	SubscriptionFrequencyDaily  SubscriptionFrequency = "daily"
 	SubscriptionFrequencyHourly SubscriptionFrequency = "hourly"
+	SubscriptionFrequencyWeekly SubscriptionFrequency = "weekly"
+	// SubscriptionFrequencyCustom is defined by the cron expression of the subscriber
+	SubscriptionFrequencyCustom SubscriptionFrequency = "custom"
 )
 
+// DefaultDeliveryHour is the local hour of the daily and weekly notifications unless the subscriber prefers another one
+const DefaultDeliveryHour = 8
+
 var (
+	// ErrSubscriptionExists is returned if the subscriber already follows the location
 	ErrSubscriptionExists = errors.New("subscription already exists")
 	ErrNoRowsAffected     = errors.New("no rows affected")
+	// ErrLeaseLost is returned if the lease of the claimed subscription has expired, so it may be claimed by another instance
+	ErrLeaseLost = errors.New("lease lost")
 )
 
 type SubscriptionsQ interface {
 	// New creates a new instance of SubscriptionsQ (separate conn)
 	New() SubscriptionsQ
+	// Insert returns ErrSubscriptionExists if the subscriber already follows the location
 	Insert(subscription Subscription) (id int64, err error)
-	GetByToken(token string) (subscription *Subscription, err error)
-	UpdateConfirmed(id int64, unsubscribeToken string) (err error)
+	// GetByToken returns the subscription owning the token of the purpose, nil if there is none
+	GetByToken(token string, purpose TokenPurpose) (subscription *Subscription, err error)
+	// GetById returns nil if there is no subscription with the id
+	GetById(id int64) (subscription *Subscription, err error)
+	// GetByLocation returns the subscription of the subscriber to the location, nil if there is none
+	GetByLocation(subscriberId int64, city, locationId string) (subscription *Subscription, err error)
+	SelectUnconfirmed(subscriberId int64) ([]Subscription, error)
+	// Update saves the settings changed by the owner: the subscriber, location, schedule, pause and confirmation.
+	// It returns ErrSubscriptionExists if the subscriber already follows the location.
+	Update(subscription Subscription) error
+	UpdateConfirmed(id int64) (err error)
+	// UpdateConfirmationSent records the reissue of the confirmation token of the unconfirmed subscription
+	UpdateConfirmationSent(id int64, sentAt time.Time) error
+	// DeleteByToken deletes the subscription owning the unsubscribe token
 	DeleteByToken(token string) (err error)
-	SelectToNotify() ([]Subscription, error)
-	UpdateLastNotified(id int64, lastNotifiedAt time.Time) error
+	// Delete returns ErrNoRowsAffected if there is no subscription with the id
+	Delete(id int64) error
+	// DeleteBySubscriber deletes all the subscriptions of the subscriber
+	DeleteBySubscriber(subscriberId int64) (deleted int64, err error)
+	// DeleteUnconfirmed deletes the unconfirmed subscriptions whose last confirmation token was sent before sentBefore
+	DeleteUnconfirmed(sentBefore time.Time) (deleted int64, err error)
+	// SelectToNotify claims up to limit subscriptions whose NextNotifyAt has come for the lease duration,
+	// skipping the suspended and paused ones, the ones waiting for a retry and the ones claimed by other instances.
+	// The subscriptions of the same subscriber go one after another.
+	// The lease is released by UpdateLastNotified, UpdateFailure or UpdatePostponed, or expires if the instance dies.
+	SelectToNotify(limit uint64, lease time.Duration) ([]Subscription, error)
+	// UpdateLastNotified schedules the next notification and releases the lease. The lease is the LeaseExpiresAt
+	// claimed by SelectToNotify, ErrLeaseLost is returned if it has expired or has been claimed again since.
+	UpdateLastNotified(id int64, lease time.Time, lastNotifiedAt, nextNotifyAt time.Time) error
+	// UpdateFailure increments the consecutive failures counter and returns its new value. The given lease is
+	// released the same way as by UpdateLastNotified, nil one is left untouched, e.g. for the failed delivery
+	// of the subscription released long ago.
+	UpdateFailure(id int64, lastError string, lease *time.Time) (consecutiveFailures int, err error)
+	UpdateNextRetry(id int64, nextRetryAt time.Time) error
+	// UpdatePostponed retries the subscription at nextRetryAt without counting the failure, e.g. after the outage
+	// of the weather provider, and releases the lease the same way as UpdateLastNotified
+	UpdatePostponed(id int64, lease time.Time, lastError string, nextRetryAt time.Time) error
+	// UpdateSuspended suspends the subscription, nil suspendedAt lifts the suspension
+	UpdateSuspended(id int64, suspendedAt *time.Time) error
+	// ResetFailures clears the failure tracking after a successful delivery
+	ResetFailures(id int64) error
+	SelectSuspended() ([]Subscription, error)
+	// Select returns up to limit subscriptions matching the filter starting from the offset, the newest first
+	Select(filter SubscriptionsFilter, limit, offset uint64) ([]Subscription, error)
+	// Count returns the number of the subscriptions matching the filter
+	Count(filter SubscriptionsFilter) (int64, error)
+	// UpdateNotifyNow makes the subscription due right away, skipping the retry backoff if there is one.
+	// It returns ErrNoRowsAffected if there is no subscription with the id.
+	UpdateNotifyNow(id int64) error
+}
+
+// SubscriptionsFilter narrows down the subscriptions listed by the admin, the zero value matches all of them
+type SubscriptionsFilter struct {
+	// Search is matched case-insensitively against the email and the city
+	Search    string
+	Confirmed *bool
+	Suspended *bool
 }
 
 type Subscription struct {
-	Id             int64                 `structs:"-" db:"id"`
-	Email          string                `structs:"email" db:"email"`
-	City           string                `structs:"city" db:"city"`
-	Frequency      SubscriptionFrequency `structs:"frequency" db:"frequency"`
-	Confirmed      bool                  `structs:"confirmed" db:"confirmed"`
-	Token          string                `structs:"token" db:"token"`
-	CreatedAt      time.Time             `structs:"created_at" db:"created_at"`
-	LastNotifiedAt *time.Time            `structs:"last_notified_at" db:"last_notified_at"`
+	Id           int64 `structs:"-" db:"id"`
+	SubscriberId int64 `structs:"subscriber_id" db:"subscriber_id"`
+	// Email is the address of the subscriber, it is read-only
+	Email      string  `structs:"-" db:"email"`
+	City       string  `structs:"city" db:"city"`
+	LocationId string  `structs:"location_id" db:"location_id"`
+	Region     string  `structs:"region" db:"region"`
+	Country    string  `structs:"country" db:"country"`
+	Latitude   float64 `structs:"latitude" db:"latitude"`
+	Longitude  float64 `structs:"longitude" db:"longitude"`
+	// Timezone is an IANA name of the location, empty means UTC
+	Timezone     string                `structs:"timezone" db:"timezone"`
+	DeliveryHour int                   `structs:"delivery_hour" db:"delivery_hour"`
+	Frequency    SubscriptionFrequency `structs:"frequency" db:"frequency"`
+	// Schedule is a cron expression evaluated in the Timezone (see schedule.Parse)
+	Schedule     string     `structs:"schedule" db:"schedule"`
+	NextNotifyAt *time.Time `structs:"next_notify_at" db:"next_notify_at"`
+	// Language and Units of the emails
+	Language  i18n.Language    `structs:"language" db:"language"`
+	Units     weatherapi.Units `structs:"units" db:"units"`
+	Confirmed bool             `structs:"confirmed" db:"confirmed"`
+	// ConfirmationSentAt is the issue time of the confirmation token, the token expires after the configured TTL
+	ConfirmationSentAt *time.Time `structs:"confirmation_sent_at" db:"confirmation_sent_at"`
+	CreatedAt          time.Time  `structs:"created_at" db:"created_at"`
+	LastNotifiedAt     *time.Time `structs:"last_notified_at" db:"last_notified_at"`
+
+	ConsecutiveFailures int        `structs:"consecutive_failures" db:"consecutive_failures"`
+	NextRetryAt         *time.Time `structs:"next_retry_at" db:"next_retry_at"`
+	LastError           *string    `structs:"last_error" db:"last_error"`
+	SuspendedAt         *time.Time `structs:"suspended_at" db:"suspended_at"`
+	LeaseExpiresAt      *time.Time `structs:"lease_expires_at" db:"lease_expires_at"`
+	// PausedAt is set while the owner pauses the notifications
+	PausedAt *time.Time `structs:"paused_at" db:"paused_at"`
+}
+
+// ConfirmationExpired reports whether the confirmation token of the unconfirmed subscription has expired
+func (s Subscription) ConfirmationExpired(ttl time.Duration, now time.Time) bool {
+	return !s.Confirmed && s.ConfirmationSentAt != nil && now.After(s.ConfirmationSentAt.Add(ttl))
+}
+
+// Location returns the subscription timezone, UTC is used if it is unknown
+func (s Subscription) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
 }
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const tokenLengthRaw = 16
+
+// TokenPurpose restricts the endpoints the token is accepted by
+type TokenPurpose string
+
+const (
+	// TokenPurposeConfirmation confirms the subscription or the alert rule
+	TokenPurposeConfirmation TokenPurpose = "confirmation"
+	// TokenPurposeUnsubscribe manages and deletes the confirmed subscription or alert rule
+	TokenPurposeUnsubscribe TokenPurpose = "unsubscribe"
+)
+
+// TokensQ stores the issued tokens as SHA-256 hashes, the plaintext ones are only sent by email.
+// The tokens are looked up by the owners queries (see SubscriptionsQ.GetByToken and AlertRulesQ.GetByToken).
+type TokensQ interface {
+	// New creates a new instance of TokensQ (separate conn)
+	New() TokensQ
+	// Insert stores the token, the owner may have several tokens of the same purpose
+	Insert(token Token) error
+	// DeleteBySubscription revokes the tokens of the purpose issued for the subscription
+	DeleteBySubscription(subscriptionId int64, purpose TokenPurpose) error
+	// DeleteByAlertRule revokes the tokens of the purpose issued for the alert rule
+	DeleteByAlertRule(alertRuleId int64, purpose TokenPurpose) error
+}
+
+// Token is the stored form of the issued token, it is owned by either the subscription or the alert rule
+type Token struct {
+	Hash           string       `structs:"hash" db:"hash"`
+	Purpose        TokenPurpose `structs:"purpose" db:"purpose"`
+	SubscriptionId *int64       `structs:"subscription_id" db:"subscription_id"`
+	AlertRuleId    *int64       `structs:"alert_rule_id" db:"alert_rule_id"`
+	CreatedAt      time.Time    `structs:"created_at" db:"created_at"`
+}
+
+// NewSubscriptionToken generates the token of the subscription, returning the plaintext one along with its stored form
+func NewSubscriptionToken(subscriptionId int64, purpose TokenPurpose) (string, Token) {
+	token := GenerateToken()
+	return token, Token{
+		Hash:           HashToken(token),
+		Purpose:        purpose,
+		SubscriptionId: &subscriptionId,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// NewAlertRuleToken generates the token of the alert rule, returning the plaintext one along with its stored form
+func NewAlertRuleToken(alertRuleId int64, purpose TokenPurpose) (string, Token) {
+	token := GenerateToken()
+	return token, Token{
+		Hash:        HashToken(token),
+		Purpose:     purpose,
+		AlertRuleId: &alertRuleId,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// HashToken returns the hex encoded SHA-256 hash the token is stored and looked up by
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func GenerateToken() string {
+	b := make([]byte, tokenLengthRaw)
+	if _, err := rand.Read(b); err != nil {
+		panic("failed to generate token: " + err.Error())
+	}
+
+	return hex.EncodeToString(b)
+}
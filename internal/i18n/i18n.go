@@ -0,0 +1,71 @@
+package i18n
+
+import (
+	"golang.org/x/text/language"
+)
+
+// Language is the ISO 639-1 code of the supported language
+type Language string
+
+const (
+	English   Language = "en"
+	Ukrainian Language = "uk"
+
+	// Default is used when the preferred language is unknown or not supported
+	Default = English
+)
+
+// supported is in the order of the matcher preference, the first one is the fallback
+var supported = []Language{English, Ukrainian}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Ukrainian})
+
+func (l Language) Valid() bool {
+	for _, s := range supported {
+		if l == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OrDefault replaces the unsupported language (e.g. empty one of the records created before) with the Default
+func (l Language) OrDefault() Language {
+	if l.Valid() {
+		return l
+	}
+
+	return Default
+}
+
+func Supported() []Language {
+	return append([]Language(nil), supported...)
+}
+
+// FromAcceptLanguage returns the supported language best matching the Accept-Language header, Default if there is none
+func FromAcceptLanguage(header string) Language {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+
+	return supported[index]
+}
+
+// Catalog translates the messages keyed by their English text, the English ones are not listed
+type Catalog map[Language]map[string]string
+
+// Translate returns the message in the language, or the message itself if there is no translation
+func (c Catalog) Translate(lang Language, message string) string {
+	if translated, ok := c[lang][message]; ok {
+		return translated
+	}
+
+	return message
+}
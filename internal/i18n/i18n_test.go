@@ -0,0 +1,39 @@
+package i18n
+
+import "testing"
+
+func TestFromAcceptLanguage(t *testing.T) {
+	testCases := map[string]struct {
+		header   string
+		expected Language
+	}{
+		"must default without the header": {
+			header:   "",
+			expected: Default,
+		},
+		"must match the regional variant": {
+			header:   "uk-UA,uk;q=0.9,en;q=0.8",
+			expected: Ukrainian,
+		},
+		"must respect the quality values": {
+			header:   "en;q=0.5,uk;q=0.9",
+			expected: Ukrainian,
+		},
+		"must default for the unsupported language": {
+			header:   "fr-FR",
+			expected: Default,
+		},
+		"must default for the malformed header": {
+			header:   ";;;",
+			expected: Default,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if lang := FromAcceptLanguage(tc.header); lang != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, lang)
+			}
+		})
+	}
+}
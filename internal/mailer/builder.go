@@ -3,10 +3,13 @@ package mailer
 import (
 	"bytes"
 	"fmt"
-	"html/template"
+	htmltemplate "html/template"
 	"strings"
+	texttemplate "text/template"
 
 	"github.com/slbmax/ses-weather-app/assets"
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 )
 
 // ensure that the builder will be initialized with all required templates
@@ -15,65 +18,160 @@ func init() {
 	b.BuildConfirmationEmail(ConfirmationEmail{})
 }
 
-type EmailBuilder struct {
-	confirmationTemplate        *template.Template
-	notificationTemplate        *template.Template
-	confirmationSuccessTemplate *template.Template
+// Content is the rendered email, Text is the plain-text alternative of the HTML
+type Content struct {
+	HTML string
+	Text string
+}
+
+// emailTemplate is the pair of the HTML template and its plain-text alternative
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// templateSet is the set of the mail templates of one locale
+type templateSet struct {
+	confirmation        emailTemplate
+	notification        emailTemplate
+	confirmationSuccess emailTemplate
+	dailyDigest         emailTemplate
+	alert               emailTemplate
+	merged              emailTemplate
+}
+
+func (s templateSet) initialized() bool {
+	for _, tmpl := range []emailTemplate{
+		s.confirmation,
+		s.notification,
+		s.confirmationSuccess,
+		s.dailyDigest,
+		s.alert,
+		s.merged,
+	} {
+		if tmpl.html == nil || tmpl.text == nil {
+			return false
+		}
+	}
+
+	return true
 }
 
-func (b *EmailBuilder) initialized() bool {
-	return b.confirmationTemplate != nil
+// EmailBuilder renders the emails in their language, every supported one has its own set of the templates
+type EmailBuilder struct {
+	locales map[i18n.Language]templateSet
 }
 
 func NewBuilder() *EmailBuilder {
-	templates, err := template.
-		New("").
-		Funcs(template.FuncMap{"title": capitalize}).
-		ParseFS(assets.MailTemplates, assets.MailTemplatesDir+"/*.html")
-	if err != nil {
-		panic(err)
+	builder := &EmailBuilder{locales: make(map[i18n.Language]templateSet)}
+	for _, lang := range i18n.Supported() {
+		locale, err := parseTemplateSet(lang)
+		if err != nil {
+			panic(fmt.Errorf("failed to parse %s mail templates: %w", lang, err))
+		}
+		if !locale.initialized() {
+			panic(fmt.Sprintf("builder was not initialized with all required %s templates", lang))
+		}
+
+		builder.locales[lang] = locale
 	}
 
-	builder := &EmailBuilder{}
-	for _, tmpl := range templates.Templates() {
-		switch tmpl.Name() {
-		case assets.TemplateConfirmation:
-			builder.confirmationTemplate = tmpl
-		case assets.TemplateNotification:
-			builder.notificationTemplate = tmpl
-		case assets.TemplateConfirmationSuccess:
-			builder.confirmationSuccessTemplate = tmpl
-		default:
-			continue
+	return builder
+}
+
+func parseTemplateSet(lang i18n.Language) (templateSet, error) {
+	var (
+		dir   = assets.MailTemplatesDir + "/" + string(lang)
+		funcs = map[string]any{
+			"title": capitalize,
+			// t translates the values rendered into the templates, e.g. the frequency
+			"t": func(message string) string { return catalog.Translate(lang, message) },
+			// temp and speed format the values in the units of the subscription
+			"temp": func(t Temperature) string {
+				if t.Units == weatherapi.UnitsImperial {
+					return fmt.Sprintf("%v°F", t.Fahrenheit)
+				}
+				return fmt.Sprintf("%v°C", t.Celsius)
+			},
+			"speed": func(s Speed) string {
+				if s.Units == weatherapi.UnitsImperial {
+					return fmt.Sprintf("%v %s", s.Mph, catalog.Translate(lang, "mph"))
+				}
+				return fmt.Sprintf("%v %s", s.Kph, catalog.Translate(lang, "km/h"))
+			},
 		}
+	)
+
+	htmlTemplates, err := htmltemplate.
+		New("").
+		Funcs(funcs).
+		ParseFS(assets.MailTemplates, dir+"/*"+assets.TemplateExtHTML)
+	if err != nil {
+		return templateSet{}, err
+	}
+	textTemplates, err := texttemplate.
+		New("").
+		Funcs(funcs).
+		ParseFS(assets.MailTemplates, dir+"/*"+assets.TemplateExtText)
+	if err != nil {
+		return templateSet{}, err
 	}
 
-	if !builder.initialized() {
-		panic("builder was not initialized with all required templates")
+	var locale templateSet
+	for name, tmpl := range map[string]*emailTemplate{
+		assets.TemplateConfirmation:        &locale.confirmation,
+		assets.TemplateNotification:        &locale.notification,
+		assets.TemplateConfirmationSuccess: &locale.confirmationSuccess,
+		assets.TemplateDailyDigest:         &locale.dailyDigest,
+		assets.TemplateAlert:               &locale.alert,
+		assets.TemplateMerged:              &locale.merged,
+	} {
+		tmpl.html = htmlTemplates.Lookup(name + assets.TemplateExtHTML)
+		tmpl.text = textTemplates.Lookup(name + assets.TemplateExtText)
 	}
 
-	return builder
+	return locale, nil
 }
 
-func (b *EmailBuilder) BuildConfirmationEmail(message ConfirmationEmail) []byte {
-	return b.build(b.confirmationTemplate, message)
+// locale returns the templates of the language, falling back to the default ones
+func (b *EmailBuilder) locale(lang i18n.Language) templateSet {
+	return b.locales[lang.OrDefault()]
 }
 
-func (b *EmailBuilder) BuildNotificationEmail(message NotificationEmail) []byte {
-	return b.build(b.notificationTemplate, message)
+func (b *EmailBuilder) BuildConfirmationEmail(message ConfirmationEmail) Content {
+	return b.build(b.locale(message.Language).confirmation, message)
 }
 
-func (b *EmailBuilder) BuildConfirmationSuccessEmail(message ConfirmationSuccessEmail) []byte {
-	return b.build(b.confirmationSuccessTemplate, message)
+func (b *EmailBuilder) BuildNotificationEmail(message NotificationEmail) Content {
+	return b.build(b.locale(message.Language).notification, message)
 }
 
-func (b *EmailBuilder) build(tmpl *template.Template, msg any) []byte {
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, msg); err != nil {
+func (b *EmailBuilder) BuildConfirmationSuccessEmail(message ConfirmationSuccessEmail) Content {
+	return b.build(b.locale(message.Language).confirmationSuccess, message)
+}
+
+func (b *EmailBuilder) BuildDailyDigestEmail(message DailyDigestEmail) Content {
+	return b.build(b.locale(message.Language).dailyDigest, message)
+}
+
+func (b *EmailBuilder) BuildAlertEmail(message AlertEmail) Content {
+	return b.build(b.locale(message.Language).alert, message)
+}
+
+func (b *EmailBuilder) BuildMergedEmail(message MergedEmail) Content {
+	return b.build(b.locale(message.Language).merged, message)
+}
+
+func (b *EmailBuilder) build(tmpl emailTemplate, msg any) Content {
+	var html, text bytes.Buffer
+	if err := tmpl.html.Execute(&html, msg); err != nil {
 		panic(fmt.Errorf("error executing template: %w", err))
 	}
+	if err := tmpl.text.Execute(&text, msg); err != nil {
+		panic(fmt.Errorf("error executing text template: %w", err))
+	}
 
-	return buf.Bytes()
+	return Content{HTML: html.String(), Text: text.String()}
 }
 
 func capitalize(s string) string {
@@ -0,0 +1,142 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+func TestEmailBuilder_PlainTextAlternative(t *testing.T) {
+	builder := NewBuilder()
+	celsius := Temperature{Celsius: 21.5, Fahrenheit: 70.7, Units: weatherapi.UnitsMetric}
+	digest := DailyDigestEmail{
+		City:        "Kyiv",
+		Date:        "2025-06-02",
+		Description: "Sunny",
+		Hours:       []DailyDigestHour{{Time: "09:00", Temperature: celsius, ChanceOfRain: 10, Description: "Clear"}},
+	}
+
+	testCases := map[string]struct {
+		content  Content
+		expected []string
+	}{
+		"confirmation": {
+			content:  builder.BuildConfirmationEmail(ConfirmationEmail{City: "Kyiv", Frequency: "daily", Token: "token"}),
+			expected: []string{"Kyiv", "Confirmation token: token"},
+		},
+		"confirmation success": {
+			content:  builder.BuildConfirmationSuccessEmail(ConfirmationSuccessEmail{City: "Kyiv", Frequency: "daily", Token: "token"}),
+			expected: []string{"Kyiv", "Unsubscribe token: token"},
+		},
+		"notification": {
+			content:  builder.BuildNotificationEmail(NotificationEmail{City: "Kyiv", Temperature: celsius, Humidity: 40, Description: "Sunny", Frequency: "hourly"}),
+			expected: []string{"Hourly Weather Notification for Kyiv", "Temperature: 21.5°C", "Humidity: 40%"},
+		},
+		"merged": {
+			content:  builder.BuildMergedEmail(MergedEmail{Digests: []DailyDigestEmail{digest}}),
+			expected: []string{"Daily Weather Digest for Kyiv", "09:00  21.5°C, rain 10%, Clear"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if tc.content.HTML == "" {
+				t.Fatal("expected the HTML part")
+			}
+			if strings.Contains(tc.content.Text, "<") {
+				t.Fatalf("expected no markup in the text part, got %s", tc.content.Text)
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(tc.content.Text, expected) {
+					t.Fatalf("expected the text part to contain %q, got %s", expected, tc.content.Text)
+				}
+			}
+		})
+	}
+}
+
+func TestEmailBuilder_Localized(t *testing.T) {
+	var (
+		builder = NewBuilder()
+		email   = NotificationEmail{City: "Kyiv", Humidity: 40, Description: "Сонячно", Frequency: "hourly"}
+	)
+
+	testCases := map[string]struct {
+		language i18n.Language
+		expected string
+	}{
+		"ukrainian": {
+			language: i18n.Ukrainian,
+			expected: "Сповіщення про погоду (щогодини) для Kyiv",
+		},
+		"unsupported falls back to english": {
+			language: "de",
+			expected: "Hourly Weather Notification for Kyiv",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			email.Language = tc.language
+			content := builder.BuildNotificationEmail(email)
+			if !strings.Contains(content.Text, tc.expected) {
+				t.Fatalf("expected the text part to contain %q, got %s", tc.expected, content.Text)
+			}
+		})
+	}
+
+	alert := builder.BuildConfirmationEmail(ConfirmationEmail{Language: i18n.Ukrainian, City: "Kyiv", Frequency: "weather alert", Token: "token"})
+	if expected := "сповіщення (погодне попередження) для Kyiv"; !strings.Contains(alert.Text, expected) {
+		t.Fatalf("expected the alert confirmation to contain %q, got %s", expected, alert.Text)
+	}
+
+	if subject := catalog.Translate(i18n.Ukrainian, EmailSubjectNotification); subject == EmailSubjectNotification {
+		t.Fatal("expected the translated subject")
+	}
+}
+
+func TestEmailBuilder_Units(t *testing.T) {
+	builder := NewBuilder()
+
+	testCases := map[string]struct {
+		language i18n.Language
+		units    weatherapi.Units
+		expected []string
+	}{
+		"metric": {
+			units:    weatherapi.UnitsMetric,
+			expected: []string{"Temperature: 21.5°C (feels like 20°C)", "Wind: 18 km/h NW, gusts 25 km/h"},
+		},
+		"imperial": {
+			units:    weatherapi.UnitsImperial,
+			expected: []string{"Temperature: 70.7°F (feels like 68°F)", "Wind: 11.2 mph NW, gusts 15.5 mph"},
+		},
+		"imperial ukrainian": {
+			language: i18n.Ukrainian,
+			units:    weatherapi.UnitsImperial,
+			expected: []string{"Температура: 70.7°F (відчувається як 68°F)", "Вітер: 11.2 миль/год NW, пориви до 15.5 миль/год"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			content := builder.BuildNotificationEmail(NotificationEmail{
+				Language:      tc.language,
+				City:          "Kyiv",
+				Temperature:   Temperature{Celsius: 21.5, Fahrenheit: 70.7, Units: tc.units},
+				FeelsLike:     Temperature{Celsius: 20, Fahrenheit: 68, Units: tc.units},
+				Wind:          Speed{Kph: 18, Mph: 11.2, Units: tc.units},
+				Gust:          Speed{Kph: 25, Mph: 15.5, Units: tc.units},
+				WindDirection: "NW",
+				Frequency:     "hourly",
+			})
+			for _, expected := range tc.expected {
+				if !strings.Contains(content.Text, expected) || !strings.Contains(content.HTML, expected[strings.Index(expected, ":")+2:]) {
+					t.Fatalf("expected the content to contain %q, got %s", expected, content.Text)
+				}
+			}
+		})
+	}
+}
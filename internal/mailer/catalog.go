@@ -0,0 +1,35 @@
+package mailer
+
+import "github.com/slbmax/ses-weather-app/internal/i18n"
+
+// catalog translates the subjects and the values rendered into the templates (see the "t" template function)
+var catalog = i18n.Catalog{
+	i18n.Ukrainian: {
+		EmailSubjectConfirmation:        "Weather App - Підтвердіть вашу пошту",
+		EmailSubjectNotification:        "Weather App - Сповіщення про погоду",
+		EmailSubjectConfirmationSuccess: "Weather App - Підписку підтверджено",
+		EmailSubjectDailyDigest:         "Weather App - Щоденний прогноз погоди",
+		EmailSubjectAlert:               "Weather App - Погодне попередження",
+		EmailSubjectMerged:              "Weather App - Ваше оновлення погоди",
+
+		// frequencies
+		"hourly": "щогодини",
+		"daily":  "щодня",
+		"weekly": "щотижня",
+		"custom": "за розкладом",
+		// the alert rules are confirmed with the same emails as the subscriptions
+		"weather alert": "погодне попередження",
+
+		// units
+		"km/h": "км/год",
+		"mph":  "миль/год",
+
+		// alert metrics and operators
+		"chance of rain":      "ймовірність дощу",
+		"minimal temperature": "мінімальна температура",
+		"maximal temperature": "максимальна температура",
+		"maximal wind speed":  "максимальна швидкість вітру",
+		"above":               "вище",
+		"below":               "нижче",
+	},
+}
@@ -0,0 +1,95 @@
+package mailer
+
+import (
+	htmltemplate "html/template"
+	"io/fs"
+	"strings"
+
+	"github.com/slbmax/ses-weather-app/assets"
+)
+
+const iconContentType = "image/png"
+
+// InlineImage is the image attached to the message and referenced from its HTML part by the "cid:" URL
+type InlineImage struct {
+	ContentID   string
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// conditionIcons maps the weatherapi.com condition codes to the embedded icons,
+// the other providers translate their conditions into these codes as well
+var conditionIcons = map[int]string{
+	1000: "clear",
+	1003: "partly-cloudy",
+	1006: "cloudy", 1009: "cloudy",
+	1030: "fog", 1135: "fog", 1147: "fog",
+	1063: "rain", 1150: "rain", 1153: "rain", 1180: "rain", 1183: "rain", 1186: "rain",
+	1189: "rain", 1192: "rain", 1195: "rain", 1240: "rain", 1243: "rain", 1246: "rain",
+	1066: "snow", 1114: "snow", 1117: "snow", 1210: "snow", 1213: "snow", 1216: "snow",
+	1219: "snow", 1222: "snow", 1225: "snow", 1255: "snow", 1258: "snow",
+	1069: "sleet", 1072: "sleet", 1168: "sleet", 1171: "sleet", 1198: "sleet", 1201: "sleet",
+	1204: "sleet", 1207: "sleet", 1237: "sleet", 1249: "sleet", 1252: "sleet", 1261: "sleet", 1264: "sleet",
+	1087: "thunder", 1273: "thunder", 1276: "thunder", 1279: "thunder", 1282: "thunder",
+}
+
+// conditionIcon returns the file name of the embedded icon of the condition, or "" if there is none.
+// Clear and partly cloudy skies have separate day and night icons.
+func conditionIcon(code int, isDay bool) string {
+	icon, ok := conditionIcons[code]
+	if !ok {
+		return ""
+	}
+
+	if icon == "clear" || icon == "partly-cloudy" {
+		if isDay {
+			icon += "-day"
+		} else {
+			icon += "-night"
+		}
+	}
+
+	return icon + ".png"
+}
+
+// IconSrc is the source of the condition icon in the HTML template. The embedded icon is attached inline,
+// so it is displayed even if the remote images are blocked, the provider one is the fallback.
+func (e NotificationEmail) IconSrc() htmltemplate.URL {
+	if icon := e.inlineImage(); icon != "" {
+		// the "cid:" scheme is not considered safe by html/template, so the value is trusted explicitly
+		return htmltemplate.URL("cid:" + icon)
+	}
+	if strings.HasPrefix(e.ConditionIcon, "//") {
+		// weatherapi.com returns the protocol-relative URLs
+		return htmltemplate.URL("https:" + e.ConditionIcon)
+	}
+
+	return htmltemplate.URL(e.ConditionIcon)
+}
+
+// inlineImage is the content ID of the embedded icon attached to the notification, or "" if there is none
+func (e NotificationEmail) inlineImage() string {
+	return conditionIcon(e.ConditionCode, e.IsDay)
+}
+
+// loadInlineImages reads the embedded images referenced by the message. The unknown ones are skipped,
+// e.g. the ones renamed after the message was stored in the outbox, as a missing icon is not worth a failed delivery.
+func loadInlineImages(names []string) []InlineImage {
+	images := make([]InlineImage, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(assets.Icons, assets.IconsDir+"/"+name)
+		if err != nil {
+			continue
+		}
+
+		images = append(images, InlineImage{
+			ContentID:   name,
+			ContentType: iconContentType,
+			Filename:    name,
+			Data:        data,
+		})
+	}
+
+	return images
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNotificationMessage_Icons(t *testing.T) {
+	m := NewMailer(nil)
+
+	testCases := map[string]struct {
+		email          NotificationEmail
+		expectedSrc    string
+		expectedImages []string
+	}{
+		"embedded day icon": {
+			email:          NotificationEmail{ConditionCode: 1003, IsDay: true, ConditionIcon: "//cdn.weatherapi.com/weather/64x64/day/116.png"},
+			expectedSrc:    `src="cid:partly-cloudy-day.png"`,
+			expectedImages: []string{"partly-cloudy-day.png"},
+		},
+		"embedded night icon": {
+			email:          NotificationEmail{ConditionCode: 1000},
+			expectedSrc:    `src="cid:clear-night.png"`,
+			expectedImages: []string{"clear-night.png"},
+		},
+		"provider icon fallback": {
+			email:       NotificationEmail{ConditionCode: 42, ConditionIcon: "//cdn.weatherapi.com/weather/64x64/day/116.png"},
+			expectedSrc: `src="https://cdn.weatherapi.com/weather/64x64/day/116.png"`,
+		},
+		"no icon": {
+			email: NotificationEmail{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			message := m.NotificationMessage("max@gmail.com", tc.email)
+			if tc.expectedSrc == "" && strings.Contains(message.Body, "<img") {
+				t.Fatalf("expected no icon, got %s", message.Body)
+			}
+			if !strings.Contains(message.Body, tc.expectedSrc) {
+				t.Fatalf("expected the body to contain %s, got %s", tc.expectedSrc, message.Body)
+			}
+			if !reflect.DeepEqual(message.InlineImages, tc.expectedImages) {
+				t.Fatalf("expected inline images %v, got %v", tc.expectedImages, message.InlineImages)
+			}
+			if images := loadInlineImages(message.InlineImages); len(images) != len(tc.expectedImages) {
+				t.Fatalf("expected the inline images to be embedded, got %d", len(images))
+			}
+		})
+	}
+}
+
+func TestMergedMessage_InlineImagesDeduplicated(t *testing.T) {
+	message := NewMailer(nil).MergedMessage("max@gmail.com", MergedEmail{Notifications: []NotificationEmail{
+		{City: "Kyiv", ConditionCode: 1183},
+		{City: "Lviv", ConditionCode: 1189},
+		{City: "Odesa", ConditionCode: 1087},
+	}})
+
+	if expected := []string{"rain.png", "thunder.png"}; !reflect.DeepEqual(message.InlineImages, expected) {
+		t.Fatalf("expected inline images %v, got %v", expected, message.InlineImages)
+	}
+}
+
+func TestConditionIcons_Embedded(t *testing.T) {
+	for code := range conditionIcons {
+		for _, isDay := range []bool{true, false} {
+			icon := conditionIcon(code, isDay)
+			if images := loadInlineImages([]string{icon}); len(images) != 1 || len(images[0].Data) == 0 {
+				t.Fatalf("expected the icon %s of the code %d to be embedded", icon, code)
+			}
+		}
+	}
+}
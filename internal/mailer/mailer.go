@@ -2,50 +2,112 @@ package mailer
 
 import (
 	"fmt"
-
-	"github.com/slbmax/ses-weather-app/pkg/mailjet"
+	"slices"
 )
 
+// Email subjects are the English ones, they are translated with the catalog
 const (
 	EmailSubjectConfirmation        = "Weather App - Confirm your email"
 	EmailSubjectNotification        = "Weather App - Weather Notification"
 	EmailSubjectConfirmationSuccess = "Weather App - Confirmation Success"
+	EmailSubjectDailyDigest         = "Weather App - Daily Weather Digest"
+	EmailSubjectAlert               = "Weather App - Weather Alert"
+	EmailSubjectMerged              = "Weather App - Your Weather Update"
 )
 
+// Message is a rendered email. Notifications are rendered ahead and stored
+// in the outbox, so they are delivered with Send later on.
+type Message struct {
+	To      string
+	Subject string
+	// Body is the HTML part, TextBody is its plain-text alternative
+	Body     string
+	TextBody string
+	// IdempotencyKey identifies the message across the delivery attempts
+	IdempotencyKey string
+	// Headers are passed to the transport as is, e.g. the one-click unsubscribe ones
+	Headers map[string]string
+	// InlineImages are the content IDs of the embedded images referenced from the Body, e.g. rain.png,
+	// the transport attaches them inline
+	InlineImages []string
+}
+
 type Mailer interface {
 	SendConfirmationEmail(to string, email ConfirmationEmail) error
-	SendNotificationEmail(to string, email NotificationEmail) error
 	SendConfirmationSuccessEmail(to string, message ConfirmationSuccessEmail) error
+	NotificationMessage(to string, email NotificationEmail) Message
+	DailyDigestMessage(to string, email DailyDigestEmail) Message
+	AlertMessage(to string, email AlertEmail) Message
+	MergedMessage(to string, email MergedEmail) Message
+	Send(message Message) error
 }
 
 type mailer struct {
-	builder *EmailBuilder
-	client  *mailjet.Client
+	builder   *EmailBuilder
+	transport Transport
 }
 
-func NewMailer(client *mailjet.Client) Mailer {
+func NewMailer(transport Transport) Mailer {
 	return &mailer{
-		builder: NewBuilder(),
-		client:  client,
+		builder:   NewBuilder(),
+		transport: transport,
 	}
 }
 
-func (m *mailer) sendEmail(to, subject string, body []byte) error {
-	if err := m.client.Send(to, subject, string(body)); err != nil {
+func (m *mailer) Send(message Message) error {
+	if err := m.transport.Send(message); err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 
 	return nil
 }
 
+func (m *mailer) sendEmail(to, subject string, content Content) error {
+	return m.Send(newMessage(to, subject, content))
+}
+
 func (m *mailer) SendConfirmationEmail(to string, email ConfirmationEmail) error {
-	return m.sendEmail(to, EmailSubjectConfirmation, m.builder.BuildConfirmationEmail(email))
+	return m.sendEmail(to, catalog.Translate(email.Language, EmailSubjectConfirmation), m.builder.BuildConfirmationEmail(email))
 }
 
-func (m *mailer) SendNotificationEmail(to string, email NotificationEmail) error {
-	return m.sendEmail(to, EmailSubjectNotification, m.builder.BuildNotificationEmail(email))
+func (m *mailer) NotificationMessage(to string, email NotificationEmail) Message {
+	message := newMessage(to, catalog.Translate(email.Language, EmailSubjectNotification), m.builder.BuildNotificationEmail(email))
+	message.InlineImages = inlineImages([]NotificationEmail{email})
+
+	return message
 }
 
 func (m *mailer) SendConfirmationSuccessEmail(to string, email ConfirmationSuccessEmail) error {
-	return m.sendEmail(to, EmailSubjectConfirmationSuccess, m.builder.BuildConfirmationSuccessEmail(email))
+	return m.sendEmail(to, catalog.Translate(email.Language, EmailSubjectConfirmationSuccess), m.builder.BuildConfirmationSuccessEmail(email))
+}
+
+func (m *mailer) DailyDigestMessage(to string, email DailyDigestEmail) Message {
+	return newMessage(to, catalog.Translate(email.Language, EmailSubjectDailyDigest), m.builder.BuildDailyDigestEmail(email))
+}
+
+func (m *mailer) AlertMessage(to string, email AlertEmail) Message {
+	return newMessage(to, catalog.Translate(email.Language, EmailSubjectAlert), m.builder.BuildAlertEmail(email))
+}
+
+func (m *mailer) MergedMessage(to string, email MergedEmail) Message {
+	message := newMessage(to, catalog.Translate(email.Language, EmailSubjectMerged), m.builder.BuildMergedEmail(email))
+	message.InlineImages = inlineImages(email.Notifications)
+
+	return message
+}
+
+func newMessage(to, subject string, content Content) Message {
+	return Message{To: to, Subject: subject, Body: content.HTML, TextBody: content.Text}
+}
+
+// inlineImages returns the icons of the notifications, each one is attached once
+func inlineImages(notifications []NotificationEmail) []string {
+	var images []string
+	for _, notification := range notifications {
+		if image := notification.inlineImage(); image != "" && !slices.Contains(images, image) {
+			images = append(images, image)
+		}
+	}
+
+	return images
 }
@@ -19,15 +19,30 @@ func (m *MockMailer) SendConfirmationEmail(_ string, email ConfirmationEmail) er
 	return nil
 }
 
-func (m *MockMailer) SendNotificationEmail(_ string, email NotificationEmail) error {
-	m.builder.BuildNotificationEmail(email)
+func (m *MockMailer) NotificationMessage(to string, email NotificationEmail) Message {
+	return newMessage(to, catalog.Translate(email.Language, EmailSubjectNotification), m.builder.BuildNotificationEmail(email))
+}
+
+func (m *MockMailer) SendConfirmationSuccessEmail(_ string, email ConfirmationSuccessEmail) error {
+	m.builder.BuildConfirmationSuccessEmail(email)
 	fmt.Println("email sent")
 
 	return nil
 }
 
-func (m *MockMailer) SendConfirmationSuccessEmail(_ string, email ConfirmationSuccessEmail) error {
-	m.builder.BuildConfirmationSuccessEmail(email)
+func (m *MockMailer) DailyDigestMessage(to string, email DailyDigestEmail) Message {
+	return newMessage(to, catalog.Translate(email.Language, EmailSubjectDailyDigest), m.builder.BuildDailyDigestEmail(email))
+}
+
+func (m *MockMailer) AlertMessage(to string, email AlertEmail) Message {
+	return newMessage(to, catalog.Translate(email.Language, EmailSubjectAlert), m.builder.BuildAlertEmail(email))
+}
+
+func (m *MockMailer) MergedMessage(to string, email MergedEmail) Message {
+	return newMessage(to, catalog.Translate(email.Language, EmailSubjectMerged), m.builder.BuildMergedEmail(email))
+}
+
+func (m *MockMailer) Send(_ Message) error {
 	fmt.Println("email sent")
 
 	return nil
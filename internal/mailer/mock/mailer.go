@@ -36,108 +36,245 @@ func (_m *MockMailer) EXPECT() *MockMailer_Expecter {
 	return &MockMailer_Expecter{mock: &_m.Mock}
 }
 
-// SendConfirmationEmail provides a mock function for the type MockMailer
-func (_mock *MockMailer) SendConfirmationEmail(to string, email mailer.ConfirmationEmail) error {
+// AlertMessage provides a mock function for the type MockMailer
+func (_mock *MockMailer) AlertMessage(to string, email mailer.AlertEmail) mailer.Message {
 	ret := _mock.Called(to, email)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SendConfirmationEmail")
+		panic("no return value specified for AlertMessage")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, mailer.ConfirmationEmail) error); ok {
+	var r0 mailer.Message
+	if returnFunc, ok := ret.Get(0).(func(string, mailer.AlertEmail) mailer.Message); ok {
 		r0 = returnFunc(to, email)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(mailer.Message)
 	}
 	return r0
 }
 
-// MockMailer_SendConfirmationEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendConfirmationEmail'
-type MockMailer_SendConfirmationEmail_Call struct {
+// MockMailer_AlertMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AlertMessage'
+type MockMailer_AlertMessage_Call struct {
 	*mock.Call
 }
 
-// SendConfirmationEmail is a helper method to define mock.On call
+// AlertMessage is a helper method to define mock.On call
 //   - to
 //   - email
-func (_e *MockMailer_Expecter) SendConfirmationEmail(to interface{}, email interface{}) *MockMailer_SendConfirmationEmail_Call {
-	return &MockMailer_SendConfirmationEmail_Call{Call: _e.mock.On("SendConfirmationEmail", to, email)}
+func (_e *MockMailer_Expecter) AlertMessage(to interface{}, email interface{}) *MockMailer_AlertMessage_Call {
+	return &MockMailer_AlertMessage_Call{Call: _e.mock.On("AlertMessage", to, email)}
 }
 
-func (_c *MockMailer_SendConfirmationEmail_Call) Run(run func(to string, email mailer.ConfirmationEmail)) *MockMailer_SendConfirmationEmail_Call {
+func (_c *MockMailer_AlertMessage_Call) Run(run func(to string, email mailer.AlertEmail)) *MockMailer_AlertMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(mailer.ConfirmationEmail))
+		run(args[0].(string), args[1].(mailer.AlertEmail))
 	})
 	return _c
 }
 
-func (_c *MockMailer_SendConfirmationEmail_Call) Return(err error) *MockMailer_SendConfirmationEmail_Call {
-	_c.Call.Return(err)
+func (_c *MockMailer_AlertMessage_Call) Return(message mailer.Message) *MockMailer_AlertMessage_Call {
+	_c.Call.Return(message)
 	return _c
 }
 
-func (_c *MockMailer_SendConfirmationEmail_Call) RunAndReturn(run func(to string, email mailer.ConfirmationEmail) error) *MockMailer_SendConfirmationEmail_Call {
+func (_c *MockMailer_AlertMessage_Call) RunAndReturn(run func(to string, email mailer.AlertEmail) mailer.Message) *MockMailer_AlertMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SendConfirmationSuccessEmail provides a mock function for the type MockMailer
-func (_mock *MockMailer) SendConfirmationSuccessEmail(to string, message mailer.ConfirmationSuccessEmail) error {
-	ret := _mock.Called(to, message)
+// DailyDigestMessage provides a mock function for the type MockMailer
+func (_mock *MockMailer) DailyDigestMessage(to string, email mailer.DailyDigestEmail) mailer.Message {
+	ret := _mock.Called(to, email)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SendConfirmationSuccessEmail")
+		panic("no return value specified for DailyDigestMessage")
+	}
+
+	var r0 mailer.Message
+	if returnFunc, ok := ret.Get(0).(func(string, mailer.DailyDigestEmail) mailer.Message); ok {
+		r0 = returnFunc(to, email)
+	} else {
+		r0 = ret.Get(0).(mailer.Message)
+	}
+	return r0
+}
+
+// MockMailer_DailyDigestMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DailyDigestMessage'
+type MockMailer_DailyDigestMessage_Call struct {
+	*mock.Call
+}
+
+// DailyDigestMessage is a helper method to define mock.On call
+//   - to
+//   - email
+func (_e *MockMailer_Expecter) DailyDigestMessage(to interface{}, email interface{}) *MockMailer_DailyDigestMessage_Call {
+	return &MockMailer_DailyDigestMessage_Call{Call: _e.mock.On("DailyDigestMessage", to, email)}
+}
+
+func (_c *MockMailer_DailyDigestMessage_Call) Run(run func(to string, email mailer.DailyDigestEmail)) *MockMailer_DailyDigestMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(mailer.DailyDigestEmail))
+	})
+	return _c
+}
+
+func (_c *MockMailer_DailyDigestMessage_Call) Return(message mailer.Message) *MockMailer_DailyDigestMessage_Call {
+	_c.Call.Return(message)
+	return _c
+}
+
+func (_c *MockMailer_DailyDigestMessage_Call) RunAndReturn(run func(to string, email mailer.DailyDigestEmail) mailer.Message) *MockMailer_DailyDigestMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MergedMessage provides a mock function for the type MockMailer
+func (_mock *MockMailer) MergedMessage(to string, email mailer.MergedEmail) mailer.Message {
+	ret := _mock.Called(to, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergedMessage")
+	}
+
+	var r0 mailer.Message
+	if returnFunc, ok := ret.Get(0).(func(string, mailer.MergedEmail) mailer.Message); ok {
+		r0 = returnFunc(to, email)
+	} else {
+		r0 = ret.Get(0).(mailer.Message)
+	}
+	return r0
+}
+
+// MockMailer_MergedMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergedMessage'
+type MockMailer_MergedMessage_Call struct {
+	*mock.Call
+}
+
+// MergedMessage is a helper method to define mock.On call
+//   - to
+//   - email
+func (_e *MockMailer_Expecter) MergedMessage(to interface{}, email interface{}) *MockMailer_MergedMessage_Call {
+	return &MockMailer_MergedMessage_Call{Call: _e.mock.On("MergedMessage", to, email)}
+}
+
+func (_c *MockMailer_MergedMessage_Call) Run(run func(to string, email mailer.MergedEmail)) *MockMailer_MergedMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(mailer.MergedEmail))
+	})
+	return _c
+}
+
+func (_c *MockMailer_MergedMessage_Call) Return(message mailer.Message) *MockMailer_MergedMessage_Call {
+	_c.Call.Return(message)
+	return _c
+}
+
+func (_c *MockMailer_MergedMessage_Call) RunAndReturn(run func(to string, email mailer.MergedEmail) mailer.Message) *MockMailer_MergedMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NotificationMessage provides a mock function for the type MockMailer
+func (_mock *MockMailer) NotificationMessage(to string, email mailer.NotificationEmail) mailer.Message {
+	ret := _mock.Called(to, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NotificationMessage")
+	}
+
+	var r0 mailer.Message
+	if returnFunc, ok := ret.Get(0).(func(string, mailer.NotificationEmail) mailer.Message); ok {
+		r0 = returnFunc(to, email)
+	} else {
+		r0 = ret.Get(0).(mailer.Message)
+	}
+	return r0
+}
+
+// MockMailer_NotificationMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'NotificationMessage'
+type MockMailer_NotificationMessage_Call struct {
+	*mock.Call
+}
+
+// NotificationMessage is a helper method to define mock.On call
+//   - to
+//   - email
+func (_e *MockMailer_Expecter) NotificationMessage(to interface{}, email interface{}) *MockMailer_NotificationMessage_Call {
+	return &MockMailer_NotificationMessage_Call{Call: _e.mock.On("NotificationMessage", to, email)}
+}
+
+func (_c *MockMailer_NotificationMessage_Call) Run(run func(to string, email mailer.NotificationEmail)) *MockMailer_NotificationMessage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(mailer.NotificationEmail))
+	})
+	return _c
+}
+
+func (_c *MockMailer_NotificationMessage_Call) Return(message mailer.Message) *MockMailer_NotificationMessage_Call {
+	_c.Call.Return(message)
+	return _c
+}
+
+func (_c *MockMailer_NotificationMessage_Call) RunAndReturn(run func(to string, email mailer.NotificationEmail) mailer.Message) *MockMailer_NotificationMessage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Send provides a mock function for the type MockMailer
+func (_mock *MockMailer) Send(message mailer.Message) error {
+	ret := _mock.Called(message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, mailer.ConfirmationSuccessEmail) error); ok {
-		r0 = returnFunc(to, message)
+	if returnFunc, ok := ret.Get(0).(func(mailer.Message) error); ok {
+		r0 = returnFunc(message)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockMailer_SendConfirmationSuccessEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendConfirmationSuccessEmail'
-type MockMailer_SendConfirmationSuccessEmail_Call struct {
+// MockMailer_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type MockMailer_Send_Call struct {
 	*mock.Call
 }
 
-// SendConfirmationSuccessEmail is a helper method to define mock.On call
-//   - to
+// Send is a helper method to define mock.On call
 //   - message
-func (_e *MockMailer_Expecter) SendConfirmationSuccessEmail(to interface{}, message interface{}) *MockMailer_SendConfirmationSuccessEmail_Call {
-	return &MockMailer_SendConfirmationSuccessEmail_Call{Call: _e.mock.On("SendConfirmationSuccessEmail", to, message)}
+func (_e *MockMailer_Expecter) Send(message interface{}) *MockMailer_Send_Call {
+	return &MockMailer_Send_Call{Call: _e.mock.On("Send", message)}
 }
 
-func (_c *MockMailer_SendConfirmationSuccessEmail_Call) Run(run func(to string, message mailer.ConfirmationSuccessEmail)) *MockMailer_SendConfirmationSuccessEmail_Call {
+func (_c *MockMailer_Send_Call) Run(run func(message mailer.Message)) *MockMailer_Send_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(mailer.ConfirmationSuccessEmail))
+		run(args[0].(mailer.Message))
 	})
 	return _c
 }
 
-func (_c *MockMailer_SendConfirmationSuccessEmail_Call) Return(err error) *MockMailer_SendConfirmationSuccessEmail_Call {
+func (_c *MockMailer_Send_Call) Return(err error) *MockMailer_Send_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMailer_SendConfirmationSuccessEmail_Call) RunAndReturn(run func(to string, message mailer.ConfirmationSuccessEmail) error) *MockMailer_SendConfirmationSuccessEmail_Call {
+func (_c *MockMailer_Send_Call) RunAndReturn(run func(message mailer.Message) error) *MockMailer_Send_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// SendNotificationEmail provides a mock function for the type MockMailer
-func (_mock *MockMailer) SendNotificationEmail(to string, email mailer.NotificationEmail) error {
+// SendConfirmationEmail provides a mock function for the type MockMailer
+func (_mock *MockMailer) SendConfirmationEmail(to string, email mailer.ConfirmationEmail) error {
 	ret := _mock.Called(to, email)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SendNotificationEmail")
+		panic("no return value specified for SendConfirmationEmail")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, mailer.NotificationEmail) error); ok {
+	if returnFunc, ok := ret.Get(0).(func(string, mailer.ConfirmationEmail) error); ok {
 		r0 = returnFunc(to, email)
 	} else {
 		r0 = ret.Error(0)
@@ -145,31 +282,77 @@ func (_mock *MockMailer) SendNotificationEmail(to string, email mailer.Notificat
 	return r0
 }
 
-// MockMailer_SendNotificationEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendNotificationEmail'
-type MockMailer_SendNotificationEmail_Call struct {
+// MockMailer_SendConfirmationEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendConfirmationEmail'
+type MockMailer_SendConfirmationEmail_Call struct {
 	*mock.Call
 }
 
-// SendNotificationEmail is a helper method to define mock.On call
+// SendConfirmationEmail is a helper method to define mock.On call
 //   - to
 //   - email
-func (_e *MockMailer_Expecter) SendNotificationEmail(to interface{}, email interface{}) *MockMailer_SendNotificationEmail_Call {
-	return &MockMailer_SendNotificationEmail_Call{Call: _e.mock.On("SendNotificationEmail", to, email)}
+func (_e *MockMailer_Expecter) SendConfirmationEmail(to interface{}, email interface{}) *MockMailer_SendConfirmationEmail_Call {
+	return &MockMailer_SendConfirmationEmail_Call{Call: _e.mock.On("SendConfirmationEmail", to, email)}
 }
 
-func (_c *MockMailer_SendNotificationEmail_Call) Run(run func(to string, email mailer.NotificationEmail)) *MockMailer_SendNotificationEmail_Call {
+func (_c *MockMailer_SendConfirmationEmail_Call) Run(run func(to string, email mailer.ConfirmationEmail)) *MockMailer_SendConfirmationEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(mailer.NotificationEmail))
+		run(args[0].(string), args[1].(mailer.ConfirmationEmail))
 	})
 	return _c
 }
 
-func (_c *MockMailer_SendNotificationEmail_Call) Return(err error) *MockMailer_SendNotificationEmail_Call {
+func (_c *MockMailer_SendConfirmationEmail_Call) Return(err error) *MockMailer_SendConfirmationEmail_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockMailer_SendNotificationEmail_Call) RunAndReturn(run func(to string, email mailer.NotificationEmail) error) *MockMailer_SendNotificationEmail_Call {
+func (_c *MockMailer_SendConfirmationEmail_Call) RunAndReturn(run func(to string, email mailer.ConfirmationEmail) error) *MockMailer_SendConfirmationEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SendConfirmationSuccessEmail provides a mock function for the type MockMailer
+func (_mock *MockMailer) SendConfirmationSuccessEmail(to string, message mailer.ConfirmationSuccessEmail) error {
+	ret := _mock.Called(to, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendConfirmationSuccessEmail")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(string, mailer.ConfirmationSuccessEmail) error); ok {
+		r0 = returnFunc(to, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockMailer_SendConfirmationSuccessEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendConfirmationSuccessEmail'
+type MockMailer_SendConfirmationSuccessEmail_Call struct {
+	*mock.Call
+}
+
+// SendConfirmationSuccessEmail is a helper method to define mock.On call
+//   - to
+//   - message
+func (_e *MockMailer_Expecter) SendConfirmationSuccessEmail(to interface{}, message interface{}) *MockMailer_SendConfirmationSuccessEmail_Call {
+	return &MockMailer_SendConfirmationSuccessEmail_Call{Call: _e.mock.On("SendConfirmationSuccessEmail", to, message)}
+}
+
+func (_c *MockMailer_SendConfirmationSuccessEmail_Call) Run(run func(to string, message mailer.ConfirmationSuccessEmail)) *MockMailer_SendConfirmationSuccessEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(mailer.ConfirmationSuccessEmail))
+	})
+	return _c
+}
+
+func (_c *MockMailer_SendConfirmationSuccessEmail_Call) Return(err error) *MockMailer_SendConfirmationSuccessEmail_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockMailer_SendConfirmationSuccessEmail_Call) RunAndReturn(run func(to string, message mailer.ConfirmationSuccessEmail) error) *MockMailer_SendConfirmationSuccessEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,61 @@
+package mailer
+
+import (
+	"github.com/slbmax/ses-weather-app/pkg/mailjet"
+	"github.com/slbmax/ses-weather-app/pkg/smtp"
+)
+
+// Transport delivers the rendered messages, e.g. through the Mailjet API or an SMTP server
+type Transport interface {
+	Send(message Message) error
+}
+
+type mailjetTransport struct {
+	client *mailjet.Client
+}
+
+func NewMailjetTransport(client *mailjet.Client) Transport {
+	return &mailjetTransport{client: client}
+}
+
+func (t *mailjetTransport) Send(message Message) error {
+	var images []mailjet.InlineImage
+	for _, image := range loadInlineImages(message.InlineImages) {
+		images = append(images, mailjet.InlineImage(image))
+	}
+
+	return t.client.Send(mailjet.Message{
+		To:           message.To,
+		Subject:      message.Subject,
+		HTMLPart:     message.Body,
+		TextPart:     message.TextBody,
+		CustomID:     message.IdempotencyKey,
+		Headers:      message.Headers,
+		InlineImages: images,
+	})
+}
+
+// smtpTransport has no use for the IdempotencyKey, as there are no delivery events to trace back
+type smtpTransport struct {
+	client *smtp.Client
+}
+
+func NewSMTPTransport(client *smtp.Client) Transport {
+	return &smtpTransport{client: client}
+}
+
+func (t *smtpTransport) Send(message Message) error {
+	var images []smtp.InlineImage
+	for _, image := range loadInlineImages(message.InlineImages) {
+		images = append(images, smtp.InlineImage(image))
+	}
+
+	return t.client.Send(smtp.Message{
+		To:           message.To,
+		Subject:      message.Subject,
+		HTMLPart:     message.Body,
+		TextPart:     message.TextBody,
+		Headers:      message.Headers,
+		InlineImages: images,
+	})
+}
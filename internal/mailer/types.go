@@ -1,21 +1,97 @@
 package mailer
 
+import (
+	"github.com/slbmax/ses-weather-app/internal/i18n"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+// Temperature carries both scales, it is rendered in the Units with the "temp" template function
+type Temperature struct {
+	Celsius    float32
+	Fahrenheit float32
+	Units      weatherapi.Units
+}
+
+// Speed carries both km/h and mph, it is rendered in the Units with the "speed" template function
+type Speed struct {
+	Kph   float32
+	Mph   float32
+	Units weatherapi.Units
+}
+
 type ConfirmationEmail struct {
+	Language  i18n.Language
 	City      string
 	Frequency string
 	Token     string
 }
 
 type NotificationEmail struct {
-	City        string
-	Temperature float32
-	Description string
-	Humidity    uint8
-	Frequency   string
+	Language      i18n.Language
+	City          string
+	Temperature   Temperature
+	FeelsLike     Temperature
+	Wind          Speed
+	Gust          Speed
+	WindDirection string
+	Description   string
+	// ConditionCode is the weatherapi.com one, it picks the embedded icon (see IconSrc)
+	ConditionCode int
+	// ConditionIcon is the icon URL of the provider, used if there is no embedded one for the code
+	ConditionIcon string
+	IsDay         bool
+	Humidity      uint8
+	UV            float32
+	Frequency     string
+	// Current is the full current conditions, for the templates needing more than the above
+	Current weatherapi.CurrentWeather
 }
 
 type ConfirmationSuccessEmail struct {
+	Language  i18n.Language
 	City      string
 	Frequency string
 	Token     string
 }
+
+type DailyDigestEmail struct {
+	Language       i18n.Language
+	City           string
+	Date           string
+	Description    string
+	MaxTemperature Temperature
+	MinTemperature Temperature
+	ChanceOfRain   uint8
+	Sunrise        string
+	Sunset         string
+	Hours          []DailyDigestHour
+}
+
+type DailyDigestHour struct {
+	Time         string
+	Temperature  Temperature
+	ChanceOfRain uint8
+	Description  string
+}
+
+// AlertEmail describes the fired alert rule, e.g. "Chance of rain is expected to be above 70% on 2025-06-02"
+type AlertEmail struct {
+	Language    i18n.Language
+	City        string
+	Date        string
+	Metric      string
+	Operator    string
+	Threshold   float64
+	Value       float64
+	Unit        string
+	Description string
+	Token       string
+}
+
+// MergedEmail combines the notifications of several subscriptions of the same subscriber,
+// it is rendered in its own Language, the ones of the parts are ignored
+type MergedEmail struct {
+	Language      i18n.Language
+	Digests       []DailyDigestEmail
+	Notifications []NotificationEmail
+}
@@ -0,0 +1,228 @@
+package notificator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+const (
+	defaultAlertInterval      = time.Minute
+	defaultAlertCheckInterval = 30 * time.Minute
+	defaultAlertBatchSize     = 100
+	defaultAlertLease         = 5 * time.Minute
+)
+
+// AlertEvaluatorOpts is optional, zero values are replaced with defaults
+type AlertEvaluatorOpts struct {
+	Interval time.Duration
+	// CheckInterval is the period of checking every rule against the forecast
+	CheckInterval time.Duration
+	BatchSize     uint64
+	// Lease is the time the claimed rules are hidden from other instances, it must exceed the time needed to check the batch
+	Lease time.Duration
+	// Unsubscribe signs the one-click unsubscribe headers of the alerts, they are omitted if nil
+	Unsubscribe *unsubscribe.Links
+}
+
+// alertMetric describes the metric in the alert emails
+type alertMetric struct {
+	label string
+	unit  string
+	value func(day weatherapi.DayForecast) float64
+}
+
+var alertMetrics = map[database.AlertMetric]alertMetric{
+	database.AlertMetricRainChance: {
+		label: "chance of rain",
+		unit:  "%",
+		value: func(day weatherapi.DayForecast) float64 { return float64(day.ChanceOfRain) },
+	},
+	database.AlertMetricMinTemperature: {
+		label: "minimal temperature",
+		unit:  "°C",
+		value: func(day weatherapi.DayForecast) float64 { return float64(day.MinTemperature) },
+	},
+	database.AlertMetricMaxTemperature: {
+		label: "maximal temperature",
+		unit:  "°C",
+		value: func(day weatherapi.DayForecast) float64 { return float64(day.MaxTemperature) },
+	},
+	database.AlertMetricMaxWind: {
+		label: "maximal wind speed",
+		unit:  " kph",
+		value: func(day weatherapi.DayForecast) float64 { return float64(day.MaxWind) },
+	},
+}
+
+// AlertEvaluator periodically checks the confirmed alert rules against the forecast and enqueues
+// the alerts into the outbox (see Dispatcher). A rule fires once its condition becomes true and
+// is not fired again until the condition turns false. Several instances may run concurrently,
+// as every rule is claimed by one of them (see database.AlertRulesQ.SelectToEvaluate).
+type AlertEvaluator struct {
+	db         database.Database
+	weatherApi weatherapi.WeatherProvider
+	mailer     mailer.Mailer
+	logger     *logan.Entry
+	opts       AlertEvaluatorOpts
+}
+
+func NewAlertEvaluator(
+	db database.Database,
+	weatherApi weatherapi.WeatherProvider,
+	mailer mailer.Mailer,
+	logger *logan.Entry,
+	opts AlertEvaluatorOpts,
+) *AlertEvaluator {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultAlertInterval
+	}
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = defaultAlertCheckInterval
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultAlertBatchSize
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = defaultAlertLease
+	}
+
+	return &AlertEvaluator{
+		db:         db,
+		weatherApi: weatherApi,
+		mailer:     mailer,
+		logger:     logger,
+		opts:       opts,
+	}
+}
+
+func (e *AlertEvaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.opts.Interval)
+	defer ticker.Stop()
+
+	for ; ; waitForTickerOrCtx(ctx, ticker) {
+		// a full batch means there may be more rules to check, so they are claimed without waiting
+		for !isCancelled(ctx) && e.evaluateBatch(ctx) == int(e.opts.BatchSize) {
+		}
+
+		if isCancelled(ctx) {
+			e.logger.Info("alert evaluator stopped")
+			return
+		}
+	}
+}
+
+// evaluateBatch claims and checks the next batch of rules, returning the batch size
+func (e *AlertEvaluator) evaluateBatch(ctx context.Context) int {
+	rules, err := e.db.AlertRulesQ().SelectToEvaluate(e.opts.BatchSize, e.opts.Lease, time.Now().Add(-e.opts.CheckInterval))
+	if err != nil {
+		e.logger.WithError(err).Error("failed to select alert rules to evaluate")
+		return 0
+	} else if len(rules) == 0 {
+		return 0
+	}
+
+	semaphore := make(chan struct{}, notificationParallelism)
+	wg := new(sync.WaitGroup)
+	wg.Add(len(rules))
+	for _, rule := range rules {
+		semaphore <- struct{}{}
+		go func(rule database.AlertRule) {
+			defer func() { <-semaphore; wg.Done() }()
+
+			// the failed rule is checked again once its lease expires
+			if err := e.evaluate(ctx, rule); err != nil && !errors.Is(err, context.Canceled) {
+				e.logger.WithError(err).WithField("alert_rule_id", rule.Id).Error("failed to evaluate alert rule")
+			}
+		}(rule)
+	}
+	wg.Wait()
+
+	e.logger.Infof("evaluated %v alert rules", len(rules))
+
+	return len(rules)
+}
+
+// evaluate checks the rule against the forecast of its day, enqueuing the alert
+// if the condition has become true since the previous check
+func (e *AlertEvaluator) evaluate(ctx context.Context, rule database.AlertRule) error {
+	metric, ok := alertMetrics[rule.Metric]
+	if !ok {
+		return fmt.Errorf("unknown metric %s", rule.Metric)
+	}
+
+	response, err := e.weatherApi.GetForecast(weatherapi.WithLanguage(ctx, string(rule.Language.OrDefault())), locationQuery(rule.City, rule.LocationId, rule.Latitude, rule.Longitude), rule.DayOffset+1)
+	if err != nil {
+		return fmt.Errorf("failed to get forecast for city %s: %w", rule.City, err)
+	} else if len(response.Forecast.Days) <= rule.DayOffset {
+		return fmt.Errorf("incomplete forecast for city %s", rule.City)
+	}
+
+	day := response.Forecast.Days[rule.DayOffset]
+	value := metric.value(day.Day)
+	holds := rule.Operator.Holds(value, rule.Threshold)
+	now := time.Now()
+
+	if !holds || rule.Triggered {
+		// nothing to send, but the condition turned false resets the de-duplication
+		return e.db.New().AlertRulesQ().UpdateEvaluated(rule.Id, holds, now)
+	}
+
+	// every alert gets its own unsubscribe link, as the stored tokens cannot be sent again
+	unsubToken, storedToken := database.NewAlertRuleToken(rule.Id, database.TokenPurposeUnsubscribe)
+	message := e.mailer.AlertMessage(rule.Email, mailer.AlertEmail{
+		Language:    rule.Language,
+		City:        rule.City,
+		Date:        day.Date,
+		Metric:      metric.label,
+		Operator:    string(rule.Operator),
+		Threshold:   rule.Threshold,
+		Value:       value,
+		Unit:        metric.unit,
+		Description: day.Day.Condition.Text,
+		Token:       unsubToken,
+	})
+
+	alertRuleId := rule.Id
+	notification := database.Notification{
+		AlertRuleId:    &alertRuleId,
+		IdempotencyKey: fmt.Sprintf("alert:%d:%s", rule.Id, day.Date),
+		Email:          message.To,
+		Subject:        message.Subject,
+		Body:           message.Body,
+		TextBody:       message.TextBody,
+		Headers:        unsubscribeHeaders(e.opts.Unsubscribe, unsubscribe.Target{Kind: unsubscribe.KindAlertRule, Id: rule.Id}),
+		Status:         database.NotificationStatusPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+
+	db := e.db.New()
+	return db.Transaction(func() error {
+		_, err := db.NotificationsQ().Insert(notification)
+		switch {
+		case errors.Is(err, database.ErrNotificationExists):
+			// the alert is already enqueued along with its token
+		case err != nil:
+			return fmt.Errorf("failed to enqueue alert for id %v: %w", rule.Id, err)
+		default:
+			if err = db.TokensQ().Insert(storedToken); err != nil {
+				return fmt.Errorf("failed to insert unsubscribe token for alert rule id %v: %w", rule.Id, err)
+			}
+		}
+
+		if err := db.AlertRulesQ().UpdateEvaluated(rule.Id, true, now); err != nil {
+			return fmt.Errorf("failed to update alert rule id %v: %w", rule.Id, err)
+		}
+
+		return nil
+	})
+}
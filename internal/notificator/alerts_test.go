@@ -0,0 +1,109 @@
+package notificator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	dbMock "github.com/slbmax/ses-weather-app/internal/database/mock"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	mailerMock "github.com/slbmax/ses-weather-app/internal/mailer/mock"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	weatherMock "github.com/slbmax/ses-weather-app/pkg/weatherapi/mock"
+	"github.com/stretchr/testify/mock"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+func TestAlertEvaluator_Evaluate(t *testing.T) {
+	newRule := func(triggered bool) database.AlertRule {
+		return database.AlertRule{
+			Id:        7,
+			Email:     "max@gmail.com",
+			City:      "Kyiv",
+			Metric:    database.AlertMetricRainChance,
+			Operator:  database.AlertOperatorAbove,
+			Threshold: 70,
+			DayOffset: 1,
+			Confirmed: true,
+			Triggered: triggered,
+		}
+	}
+	forecast := func(chanceOfRain uint8) *weatherapi.WeatherForecastResponse {
+		return &weatherapi.WeatherForecastResponse{
+			Forecast: weatherapi.Forecast{Days: []weatherapi.ForecastDay{
+				{Date: "2025-06-01"},
+				{Date: "2025-06-02", Day: weatherapi.DayForecast{ChanceOfRain: chanceOfRain}},
+			}},
+		}
+	}
+	message := mailer.Message{To: "max@gmail.com", Subject: mailer.EmailSubjectAlert, Body: "body"}
+
+	testCases := map[string]struct {
+		rule        database.AlertRule
+		preparation func(
+			alertRules *dbMock.MockAlertRulesQ,
+			notifications *dbMock.MockNotificationsQ,
+			tokens *dbMock.MockTokensQ,
+			weather *weatherMock.MockWeatherProvider,
+			mail *mailerMock.MockMailer,
+		)
+	}{
+		"must enqueue alert when condition becomes true": {
+			rule: newRule(false),
+			preparation: func(alertRules *dbMock.MockAlertRulesQ, notifications *dbMock.MockNotificationsQ, tokens *dbMock.MockTokensQ, weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer) {
+				weather.On("GetForecast", mock.Anything, "Kyiv", 2).Return(forecast(80), nil)
+				var sentToken string
+				mail.On("AlertMessage", "max@gmail.com", mock.MatchedBy(func(email mailer.AlertEmail) bool {
+					sentToken = email.Token
+					return email.Date == "2025-06-02" && email.Value == 80 && email.Threshold == 70
+				})).Return(message)
+				notifications.On("Insert", mock.MatchedBy(func(notification database.Notification) bool {
+					return notification.IdempotencyKey == "alert:7:2025-06-02" &&
+						notification.AlertRuleId != nil && *notification.AlertRuleId == 7 && notification.SubscriptionId == nil
+				})).Return(int64(1), nil)
+				tokens.On("Insert", mock.MatchedBy(func(token database.Token) bool {
+					return token.Purpose == database.TokenPurposeUnsubscribe && token.AlertRuleId != nil && *token.AlertRuleId == 7 &&
+						token.Hash == database.HashToken(sentToken)
+				})).Return(nil)
+				alertRules.On("UpdateEvaluated", int64(7), true, mock.Anything).Return(nil)
+			},
+		},
+		"must not enqueue alert again while condition holds": {
+			rule: newRule(true),
+			preparation: func(alertRules *dbMock.MockAlertRulesQ, _ *dbMock.MockNotificationsQ, _ *dbMock.MockTokensQ, weather *weatherMock.MockWeatherProvider, _ *mailerMock.MockMailer) {
+				weather.On("GetForecast", mock.Anything, "Kyiv", 2).Return(forecast(90), nil)
+				alertRules.On("UpdateEvaluated", int64(7), true, mock.Anything).Return(nil)
+			},
+		},
+		"must reset triggered rule when condition turns false": {
+			rule: newRule(true),
+			preparation: func(alertRules *dbMock.MockAlertRulesQ, _ *dbMock.MockNotificationsQ, _ *dbMock.MockTokensQ, weather *weatherMock.MockWeatherProvider, _ *mailerMock.MockMailer) {
+				weather.On("GetForecast", mock.Anything, "Kyiv", 2).Return(forecast(70), nil)
+				alertRules.On("UpdateEvaluated", int64(7), false, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			alertRules := dbMock.NewMockAlertRulesQ(t)
+			notifications := dbMock.NewMockNotificationsQ(t)
+			tokens := dbMock.NewMockTokensQ(t)
+			weather := weatherMock.NewMockWeatherProvider(t)
+			mail := mailerMock.NewMockMailer(t)
+			tc.preparation(alertRules, notifications, tokens, weather, mail)
+
+			evaluator := NewAlertEvaluator(
+				dbMock.NewDatabase(nil, notifications, alertRules, nil, tokens),
+				weather,
+				mail,
+				logan.New().Level(logan.ErrorLevel),
+				AlertEvaluatorOpts{},
+			)
+
+			if err := evaluator.evaluate(context.Background(), tc.rule); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package notificator
+
+import (
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+// digestHours are the local hours of the day included into the daily digest
+var digestHours = []int{6, 9, 12, 15, 18, 21}
+
+func newDailyDigestEmail(sub database.Subscription, day weatherapi.ForecastDay) mailer.DailyDigestEmail {
+	units := sub.Units.OrDefault()
+	email := mailer.DailyDigestEmail{
+		Language:       sub.Language,
+		City:           sub.City,
+		Date:           day.Date,
+		Description:    day.Day.Condition.Text,
+		MaxTemperature: mailer.Temperature{Celsius: day.Day.MaxTemperature, Fahrenheit: day.Day.MaxTemperatureF, Units: units},
+		MinTemperature: mailer.Temperature{Celsius: day.Day.MinTemperature, Fahrenheit: day.Day.MinTemperatureF, Units: units},
+		ChanceOfRain:   day.Day.ChanceOfRain,
+		Sunrise:        day.Astro.Sunrise,
+		Sunset:         day.Astro.Sunset,
+	}
+
+	for _, forecast := range pickDigestHours(day.Hours) {
+		email.Hours = append(email.Hours, mailer.DailyDigestHour{
+			Time:         forecast.Time.Format("15:04"),
+			Temperature:  mailer.Temperature{Celsius: forecast.Temperature, Fahrenheit: forecast.TemperatureF, Units: units},
+			ChanceOfRain: forecast.ChanceOfRain,
+			Description:  forecast.Condition.Text,
+		})
+	}
+
+	return email
+}
+
+type digestHour struct {
+	weatherapi.HourForecast
+	Time time.Time
+}
+
+// pickDigestHours selects the entries closest to digestHours, as providers
+// can return either hourly or coarser (e.g. 3-hour) steps
+func pickDigestHours(hours []weatherapi.HourForecast) []digestHour {
+	parsed := make([]digestHour, 0, len(hours))
+	for _, hour := range hours {
+		at, err := time.Parse(weatherapi.HourTimeLayout, hour.Time)
+		if err != nil {
+			continue
+		}
+		parsed = append(parsed, digestHour{HourForecast: hour, Time: at})
+	}
+
+	var (
+		picked []digestHour
+		last   = -1
+	)
+	for _, target := range digestHours {
+		closest, distance := -1, 24
+		for i, hour := range parsed {
+			if d := abs(hour.Time.Hour() - target); d < distance {
+				closest, distance = i, d
+			}
+		}
+
+		if closest > last {
+			picked = append(picked, parsed[closest])
+			last = closest
+		}
+	}
+
+	return picked
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,59 @@
+package notificator
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+func TestPickDigestHours(t *testing.T) {
+	hours := func(step int) []weatherapi.HourForecast {
+		var forecast []weatherapi.HourForecast
+		for hour := 0; hour < 24; hour += step {
+			forecast = append(forecast, weatherapi.HourForecast{Time: fmt.Sprintf("2025-06-02 %02d:00", hour)})
+		}
+		return forecast
+	}
+
+	testCases := map[string]struct {
+		hours    []weatherapi.HourForecast
+		expected []string
+	}{
+		"hourly steps": {
+			hours:    hours(1),
+			expected: []string{"06:00", "09:00", "12:00", "15:00", "18:00", "21:00"},
+		},
+		"3-hour steps": {
+			hours:    hours(3),
+			expected: []string{"06:00", "09:00", "12:00", "15:00", "18:00", "21:00"},
+		},
+		"6-hour steps are not repeated": {
+			hours:    hours(6),
+			expected: []string{"06:00", "12:00", "18:00"},
+		},
+		"empty list": {},
+		"malformed times are skipped": {
+			hours: []weatherapi.HourForecast{
+				{Time: "2025-06-02T09:00"},
+				{Time: "09:00"},
+				{Time: "2025-06-02 12:00"},
+			},
+			expected: []string{"12:00"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var picked []string
+			for _, hour := range pickDigestHours(tc.hours) {
+				picked = append(picked, hour.Time.Format("15:04"))
+			}
+
+			if !reflect.DeepEqual(picked, tc.expected) {
+				t.Fatalf("expected hours %v, got %v", tc.expected, picked)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package notificator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+const (
+	defaultDispatchInterval     = 10 * time.Second
+	defaultDispatchBatchSize    = 100
+	defaultDispatchMaxAttempts  = 5
+	defaultDispatchRetryBackoff = time.Minute
+	defaultDispatchLease        = time.Minute
+	maxDispatchRetryBackoff     = time.Hour
+)
+
+// DispatcherOpts is optional, zero values are replaced with defaults
+type DispatcherOpts struct {
+	Interval  time.Duration
+	BatchSize uint64
+	// Lease postpones the claimed notifications for other instances, it must exceed the time needed to send the batch
+	Lease time.Duration
+	// MaxAttempts is the number of delivery attempts after which the notification is marked as failed
+	MaxAttempts int
+	// RetryBackoff is the delay after the first failed attempt, doubled with every next one
+	RetryBackoff time.Duration
+	// FailurePolicy is applied to the subscription once its notification has failed permanently
+	FailurePolicy FailurePolicy
+}
+
+// Dispatcher delivers the notifications enqueued into the outbox. The delivery is at-least-once:
+// a message is marked as sent only after the mail provider has accepted it.
+type Dispatcher struct {
+	db     database.Database
+	mailer mailer.Mailer
+	logger *logan.Entry
+	opts   DispatcherOpts
+}
+
+func NewDispatcher(db database.Database, mailer mailer.Mailer, logger *logan.Entry, opts DispatcherOpts) *Dispatcher {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultDispatchInterval
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultDispatchBatchSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultDispatchMaxAttempts
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = defaultDispatchRetryBackoff
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = defaultDispatchLease
+	}
+	opts.FailurePolicy = opts.FailurePolicy.withDefaults()
+
+	return &Dispatcher{
+		db:     db,
+		mailer: mailer,
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+
+	for ; ; waitForTickerOrCtx(ctx, ticker) {
+		// a full batch means there may be more pending notifications, so they are claimed without waiting
+		for !isCancelled(ctx) && d.dispatchBatch() == int(d.opts.BatchSize) {
+		}
+
+		if isCancelled(ctx) {
+			d.logger.Info("dispatcher stopped")
+			return
+		}
+	}
+}
+
+// dispatchBatch claims and delivers the next batch of pending notifications, returning the batch size
+func (d *Dispatcher) dispatchBatch() int {
+	notifications, err := d.db.NotificationsQ().SelectPending(d.opts.BatchSize, d.opts.Lease)
+	if err != nil {
+		d.logger.WithError(err).Error("failed to select pending notifications")
+		return 0
+	} else if len(notifications) == 0 {
+		return 0
+	}
+
+	sent := d.dispatch(notifications)
+	d.logger.Infof("sent %v of %v pending notifications", sent, len(notifications))
+
+	return len(notifications)
+}
+
+// dispatch delivers the batch with the same parallelism limit as the scheduler uses
+func (d *Dispatcher) dispatch(notifications []database.Notification) (sent int) {
+	semaphore := make(chan struct{}, notificationParallelism)
+	results := make(chan bool, len(notifications))
+
+	wg := new(sync.WaitGroup)
+	wg.Add(len(notifications))
+	for _, notification := range notifications {
+		semaphore <- struct{}{}
+		go func(notification database.Notification) {
+			defer func() { <-semaphore; wg.Done() }()
+
+			ok, err := d.deliver(notification)
+			if err != nil {
+				d.logger.WithError(err).WithField("notification_id", notification.Id).Error("failed to update notification")
+			}
+			results <- ok
+		}(notification)
+	}
+	wg.Wait()
+	close(results)
+
+	for ok := range results {
+		if ok {
+			sent++
+		}
+	}
+
+	return sent
+}
+
+// deliver sends the notification and records the attempt outcome
+func (d *Dispatcher) deliver(notification database.Notification) (sent bool, err error) {
+	notificationsQ := d.db.New().NotificationsQ()
+	attempts := notification.Attempts + 1
+
+	subscriptionIds := deliveredSubscriptions(notification)
+	if len(subscriptionIds) == 0 && notification.AlertRuleId == nil {
+		return false, notificationsQ.UpdateFailed(notification.Id, attempts, "subscription or alert rule was deleted before the delivery")
+	}
+
+	sendErr := d.mailer.Send(mailer.Message{
+		To:             notification.Email,
+		Subject:        notification.Subject,
+		Body:           notification.Body,
+		TextBody:       notification.TextBody,
+		Headers:        notification.Headers,
+		InlineImages:   notification.InlineImages,
+		IdempotencyKey: notification.IdempotencyKey,
+	})
+	if sendErr == nil {
+		if err = notificationsQ.UpdateSent(notification.Id, attempts, time.Now()); err != nil {
+			// the message is sent anyway, it is going to be re-sent on the next run (at-least-once)
+			return true, fmt.Errorf("failed to mark notification as sent: %w", err)
+		}
+		// the failures are tracked for the subscriptions only, the alerts are not suspended
+		for _, subscriptionId := range subscriptionIds {
+			if err = d.db.New().SubscriptionsQ().ResetFailures(subscriptionId); err != nil {
+				return true, fmt.Errorf("failed to reset subscription failures: %w", err)
+			}
+		}
+		return true, nil
+	}
+
+	if attempts < d.opts.MaxAttempts {
+		nextAttemptAt := time.Now().Add(backoff(d.opts.RetryBackoff, maxDispatchRetryBackoff, attempts))
+		return false, notificationsQ.UpdateRetry(notification.Id, attempts, sendErr.Error(), nextAttemptAt)
+	}
+
+	d.logger.WithError(sendErr).WithField("notification_id", notification.Id).Warn("notification delivery failed permanently")
+	if err = notificationsQ.UpdateFailed(notification.Id, attempts, sendErr.Error()); err != nil {
+		return false, err
+	}
+
+	// the subscription lease has been released on enqueue, so it is not touched here
+	var errs []error
+	for _, subscriptionId := range subscriptionIds {
+		suspended, err := recordFailure(d.db, d.opts.FailurePolicy, subscriptionId, nil, sendErr)
+		switch {
+		case errors.Is(err, database.ErrNoRowsAffected):
+			// the merged subscription is deleted since, unlike the first one it is not unset by the database
+		case err != nil:
+			errs = append(errs, err)
+		case suspended:
+			d.logger.WithField("subscription_id", subscriptionId).Warn("subscription suspended after repeated delivery failures")
+		}
+	}
+
+	return false, errors.Join(errs...)
+}
+
+// deliveredSubscriptions are the subscriptions the notification is delivered for,
+// the merged one covers several subscriptions of the address
+func deliveredSubscriptions(notification database.Notification) []int64 {
+	ids := make([]int64, 0, 1+len(notification.MergedSubscriptionIds))
+	if notification.SubscriptionId != nil {
+		ids = append(ids, *notification.SubscriptionId)
+	}
+
+	return append(ids, notification.MergedSubscriptionIds...)
+}
@@ -0,0 +1,191 @@
+package notificator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	dbMock "github.com/slbmax/ses-weather-app/internal/database/mock"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	mailerMock "github.com/slbmax/ses-weather-app/internal/mailer/mock"
+	"github.com/stretchr/testify/mock"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+func TestBackoff(t *testing.T) {
+	for attempt, expected := range map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		4: 8 * time.Minute,
+		8: time.Hour,
+	} {
+		if got := backoff(time.Minute, time.Hour, attempt); got != expected {
+			t.Fatalf("attempt %d: expected %s, got %s", attempt, expected, got)
+		}
+	}
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	subscriptionId := int64(1)
+	notification := database.Notification{
+		Id:             10,
+		SubscriptionId: &subscriptionId,
+		IdempotencyKey: "1:hourly:2025-06-01T10:00:00Z",
+		Email:          "max@gmail.com",
+		Subject:        mailer.EmailSubjectNotification,
+		Body:           "<p>weather</p>",
+		TextBody:       "weather",
+		Headers:        database.MessageHeaders{"List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+		InlineImages:   database.MessageImages{"rain.png"},
+		Attempts:       2,
+	}
+	message := mailer.Message{
+		To:             notification.Email,
+		Subject:        notification.Subject,
+		Body:           notification.Body,
+		TextBody:       notification.TextBody,
+		Headers:        notification.Headers,
+		InlineImages:   notification.InlineImages,
+		IdempotencyKey: notification.IdempotencyKey,
+	}
+
+	testCases := map[string]struct {
+		notification func() database.Notification
+		maxAttempts  int
+		preparation  func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer)
+		expectedSent bool
+	}{
+		"must mark as sent": {
+			maxAttempts: 5,
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer) {
+				mail.On("Send", message).Return(nil)
+				notifications.On("UpdateSent", notification.Id, 3, mock.Anything).Return(nil)
+				subscriptions.On("ResetFailures", subscriptionId).Return(nil)
+			},
+			expectedSent: true,
+		},
+		"must schedule a retry": {
+			maxAttempts: 5,
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer) {
+				mail.On("Send", message).Return(errors.New("mailjet is down"))
+				notifications.On("UpdateRetry", notification.Id, 3, "mailjet is down", mock.MatchedBy(func(next time.Time) bool {
+					// third attempt, so the backoff is doubled twice
+					return time.Until(next) > 3*time.Minute && time.Until(next) <= 4*time.Minute
+				})).Return(nil)
+			},
+		},
+		"must mark as failed after the last attempt": {
+			maxAttempts: 3,
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer) {
+				mail.On("Send", message).Return(errors.New("mailjet is down"))
+				notifications.On("UpdateFailed", notification.Id, 3, "mailjet is down").Return(nil)
+				// the delivery fails long after the lease is released, so it is left untouched
+				subscriptions.On("UpdateFailure", subscriptionId, "mailjet is down", (*time.Time)(nil)).Return(1, nil)
+				subscriptions.On("UpdateNextRetry", subscriptionId, mock.Anything).Return(nil)
+			},
+		},
+		"must suspend the subscription after the last failure": {
+			maxAttempts: 3,
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer) {
+				mail.On("Send", message).Return(errors.New("mailbox does not exist"))
+				notifications.On("UpdateFailed", notification.Id, 3, "mailbox does not exist").Return(nil)
+				subscriptions.On("UpdateFailure", subscriptionId, "mailbox does not exist", (*time.Time)(nil)).Return(2, nil)
+				subscriptions.On("UpdateSuspended", subscriptionId, mock.Anything).Return(nil)
+			},
+		},
+		"must reset failures of all merged subscriptions": {
+			notification: func() database.Notification {
+				merged := notification
+				merged.MergedSubscriptionIds = database.SubscriptionIds{2, 3}
+				return merged
+			},
+			maxAttempts: 5,
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer) {
+				mail.On("Send", message).Return(nil)
+				notifications.On("UpdateSent", notification.Id, 3, mock.Anything).Return(nil)
+				for _, id := range []int64{subscriptionId, 2, 3} {
+					subscriptions.On("ResetFailures", id).Return(nil).Once()
+				}
+			},
+			expectedSent: true,
+		},
+		"must record failure for all merged subscriptions": {
+			notification: func() database.Notification {
+				merged := notification
+				merged.MergedSubscriptionIds = database.SubscriptionIds{2, 3}
+				return merged
+			},
+			maxAttempts: 3,
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer) {
+				mail.On("Send", message).Return(errors.New("mailbox does not exist"))
+				notifications.On("UpdateFailed", notification.Id, 3, "mailbox does not exist").Return(nil)
+				for _, id := range []int64{subscriptionId, 2} {
+					subscriptions.On("UpdateFailure", id, "mailbox does not exist", (*time.Time)(nil)).Return(1, nil).Once()
+					subscriptions.On("UpdateNextRetry", id, mock.Anything).Return(nil).Once()
+				}
+				// the merged subscription deleted since is skipped
+				subscriptions.On("UpdateFailure", int64(3), "mailbox does not exist", (*time.Time)(nil)).Return(0, database.ErrNoRowsAffected).Once()
+			},
+		},
+		"must not reset failures for alert": {
+			notification: func() database.Notification {
+				alertRuleId := int64(7)
+				alert := notification
+				alert.SubscriptionId = nil
+				alert.AlertRuleId = &alertRuleId
+				return alert
+			},
+			maxAttempts: 5,
+			preparation: func(_ *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, mail *mailerMock.MockMailer) {
+				mail.On("Send", message).Return(nil)
+				notifications.On("UpdateSent", notification.Id, 3, mock.Anything).Return(nil)
+			},
+			expectedSent: true,
+		},
+		"must fail for deleted subscription": {
+			notification: func() database.Notification {
+				deleted := notification
+				deleted.SubscriptionId = nil
+				return deleted
+			},
+			maxAttempts: 5,
+			preparation: func(_ *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, _ *mailerMock.MockMailer) {
+				notifications.On("UpdateFailed", notification.Id, 3, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			subscriptions := dbMock.NewMockSubscriptionsQ(t)
+			notifications := dbMock.NewMockNotificationsQ(t)
+			mail := mailerMock.NewMockMailer(t)
+			tc.preparation(subscriptions, notifications, mail)
+
+			dispatcher := NewDispatcher(
+				dbMock.NewDatabase(subscriptions, notifications, nil, nil, nil),
+				mail,
+				logan.New().Level(logan.ErrorLevel),
+				DispatcherOpts{
+					MaxAttempts:   tc.maxAttempts,
+					RetryBackoff:  time.Minute,
+					FailurePolicy: FailurePolicy{MaxFailures: 2},
+				},
+			)
+
+			toDeliver := notification
+			if tc.notification != nil {
+				toDeliver = tc.notification()
+			}
+
+			sent, err := dispatcher.deliver(toDeliver)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if sent != tc.expectedSent {
+				t.Fatalf("expected sent %v, got %v", tc.expectedSent, sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package notificator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+)
+
+const (
+	defaultMaxFailures     = 5
+	defaultRetryBackoff    = 5 * time.Minute
+	defaultMaxRetryBackoff = 12 * time.Hour
+)
+
+// FailurePolicy defines how the subscriptions with failing notifications are retried and suspended,
+// zero values are replaced with defaults
+type FailurePolicy struct {
+	// MaxFailures is the number of consecutive failures after which the subscription is suspended
+	MaxFailures int
+	// RetryBackoff is the delay after the first failure, doubled with every next one
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration
+}
+
+func (p FailurePolicy) withDefaults() FailurePolicy {
+	if p.MaxFailures <= 0 {
+		p.MaxFailures = defaultMaxFailures
+	}
+	if p.RetryBackoff <= 0 {
+		p.RetryBackoff = defaultRetryBackoff
+	}
+	if p.MaxRetryBackoff <= 0 {
+		p.MaxRetryBackoff = defaultMaxRetryBackoff
+	}
+
+	return p
+}
+
+// recordFailure tracks the failed notification of the subscription, postponing its next
+// notification or suspending it once the failures limit is reached. The lease of the subscription
+// claimed by the caller is released, nil lease means the caller holds none.
+func recordFailure(
+	db database.Database,
+	policy FailurePolicy,
+	subscriptionId int64,
+	lease *time.Time,
+	cause error,
+) (suspended bool, err error) {
+	db = db.New()
+	err = db.Transaction(func() error {
+		failures, err := db.SubscriptionsQ().UpdateFailure(subscriptionId, cause.Error(), lease)
+		if err != nil {
+			return fmt.Errorf("failed to update failure: %w", err)
+		}
+
+		now := time.Now()
+		if failures >= policy.MaxFailures {
+			suspended = true
+			if err = db.SubscriptionsQ().UpdateSuspended(subscriptionId, &now); err != nil {
+				return fmt.Errorf("failed to suspend: %w", err)
+			}
+			return nil
+		}
+
+		nextRetryAt := now.Add(backoff(policy.RetryBackoff, policy.MaxRetryBackoff, failures))
+		if err = db.SubscriptionsQ().UpdateNextRetry(subscriptionId, nextRetryAt); err != nil {
+			return fmt.Errorf("failed to update next retry: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record failure for subscription %v: %w", subscriptionId, err)
+	}
+
+	return suspended, nil
+}
+
+// ownFault reports whether the notification failed because of the subscription itself, e.g. its location
+// is not known to the provider anymore. Only such failures and the failed deliveries lead to the suspension,
+// the outages of the weather provider or the database postpone the subscription only.
+func ownFault(cause error) bool {
+	return errors.Is(cause, weatherapi.ErrCityNotFound)
+}
+
+// backoff doubles the base delay with every attempt after the first one, capped at max
+func backoff(base, max time.Duration, attempt int) time.Duration {
+	delay := base
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, max)
+}
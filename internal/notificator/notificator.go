@@ -2,6 +2,7 @@ package notificator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -9,138 +10,395 @@ import (
 
 	"github.com/slbmax/ses-weather-app/internal/database"
 	"github.com/slbmax/ses-weather-app/internal/mailer"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
+	"github.com/slbmax/ses-weather-app/pkg/schedule"
 	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 	"gitlab.com/distributed_lab/logan/v3"
 )
 
 const (
-	notificatorInterval     = 30 * time.Second
-	notificationParallelism = 10
+	defaultNotificatorInterval  = 30 * time.Second
+	defaultNotificatorBatchSize = 100
+	defaultNotificatorLease     = 5 * time.Minute
+	notificationParallelism     = 10
 )
 
+// Opts is optional, zero values are replaced with defaults
+type Opts struct {
+	Interval time.Duration
+	// BatchSize bounds the number of subscriptions claimed at once
+	BatchSize uint64
+	// Lease is the time the claimed subscriptions are hidden from other instances,
+	// it must exceed the time needed to process the batch
+	Lease         time.Duration
+	FailurePolicy FailurePolicy
+	// MergeSubscriptions combines the due subscriptions of the same subscriber claimed at once into one email
+	MergeSubscriptions bool
+	// Unsubscribe signs the one-click unsubscribe headers of the emails, they are omitted if nil
+	Unsubscribe *unsubscribe.Links
+}
+
+// Notificator schedules the notifications of the due subscriptions. Several instances
+// may run concurrently, as every subscription is claimed by one of them (see database.SubscriptionsQ.SelectToNotify).
 type Notificator struct {
 	db         database.Database
 	weatherApi weatherapi.WeatherProvider
 	mailer     mailer.Mailer
 	logger     *logan.Entry
+	opts       Opts
 }
 
-func New(db database.Database, weatherApi weatherapi.WeatherProvider, mailer mailer.Mailer, logger *logan.Entry) *Notificator {
+func New(
+	db database.Database,
+	weatherApi weatherapi.WeatherProvider,
+	mailer mailer.Mailer,
+	logger *logan.Entry,
+	opts Opts,
+) *Notificator {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultNotificatorInterval
+	}
+	if opts.BatchSize == 0 {
+		opts.BatchSize = defaultNotificatorBatchSize
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = defaultNotificatorLease
+	}
+	opts.FailurePolicy = opts.FailurePolicy.withDefaults()
+
 	return &Notificator{
 		db:         db,
 		logger:     logger,
 		mailer:     mailer,
 		weatherApi: weatherApi,
+		opts:       opts,
 	}
 }
 
 func (n *Notificator) Run(ctx context.Context) {
-	ticker := time.NewTicker(notificatorInterval)
+	ticker := time.NewTicker(n.opts.Interval)
 	defer ticker.Stop()
 
 	// trick to bypass initial tick delay
 	for ; ; waitForTickerOrCtx(ctx, ticker) {
+		// a full batch means there may be more due subscriptions, so they are claimed without waiting
+		for !isCancelled(ctx) && n.processBatch(ctx) == int(n.opts.BatchSize) {
+		}
+
 		if isCancelled(ctx) {
 			n.logger.Info("notificator stopped")
 			return
 		}
+	}
+}
 
-		subs, err := n.db.SubscriptionsQ().SelectToNotify()
-		if err != nil {
-			n.logger.WithError(err).Error("failed to select subscriptions to notify")
-			continue
-		} else if len(subs) == 0 {
-			n.logger.Info("no subscriptions to notify, sleeping...")
-			continue
-		}
-
-		n.logger.Infof("got %v notifications to process", len(subs))
-		processed := n.processPendingNotifications(subs)
-		n.logger.Infof("successfully processed %v notifications", processed)
+// processBatch claims and processes the next batch of due subscriptions, returning the batch size
+func (n *Notificator) processBatch(ctx context.Context) int {
+	subs, err := n.db.SubscriptionsQ().SelectToNotify(n.opts.BatchSize, n.opts.Lease)
+	if err != nil {
+		n.logger.WithError(err).Error("failed to select subscriptions to notify")
+		return 0
+	} else if len(subs) == 0 {
+		n.logger.Info("no subscriptions to notify, sleeping...")
+		return 0
 	}
+
+	n.logger.Infof("got %v notifications to process", len(subs))
+	processed := n.processPendingNotifications(ctx, subs)
+	n.logger.Infof("successfully processed %v notifications", processed)
+
+	return len(subs)
 }
 
-// processPendingNotifications processes notifications in parallel
+// processPendingNotifications renders and enqueues notifications in parallel, see Dispatcher for the delivery.
 // it can (in a production env, must) be enhanced by using batch notification sending,
 // bulk weather querying, and bulk updating, but, for this small project, it will be kept simple.
+// Weather data is expected to be cached by the provider (see weatherapi.CachedProvider).
 // Semaphore is used to limit the number of concurrent goroutines and possible rate limiting from third-party APIs
-func (n *Notificator) processPendingNotifications(subs []database.Subscription) (processed int) {
-	cache := newWeatherCache()
+func (n *Notificator) processPendingNotifications(ctx context.Context, subs []database.Subscription) (processed int) {
 	semaphore := make(chan struct{}, notificationParallelism)
 	successNotifications := new(atomic.Int32)
 
+	groups := n.groupSubscriptions(subs)
 	wg := new(sync.WaitGroup)
-	wg.Add(len(subs))
-	for _, sub := range subs {
+	wg.Add(len(groups))
+	for _, group := range groups {
 		semaphore <- struct{}{}
-		go func(sub database.Subscription) {
+		go func(group []database.Subscription) {
 			defer func() { <-semaphore; wg.Done() }()
 
-			weather, ok := cache.Get(sub.City)
-			if !ok {
-				response, err := n.weatherApi.GetCurrentWeather(sub.City)
+			prepared := make([]preparedNotification, 0, len(group))
+			for _, sub := range group {
+				p, err := n.prepare(ctx, sub)
 				if err != nil {
-					n.logger.WithError(err).Errorf("failed to get weather for city %s", sub.City)
-					return
+					n.handleFailure(sub, err)
+					continue
 				}
-				weather = response.CurrentWeather
-				cache.Set(sub.City, weather)
+				prepared = append(prepared, *p)
+			}
+			if len(prepared) == 0 {
+				return
 			}
 
-			db := n.db.New()
-			txErr := db.Transaction(func() error {
-				if err := db.SubscriptionsQ().UpdateLastNotified(sub.Id, time.Now()); err != nil {
-					return fmt.Errorf("failed to update last notified for id %v: %w", sub.Id, err)
-				}
-
-				if err := n.mailer.SendNotificationEmail(sub.Email, mailer.NotificationEmail{
-					City:        sub.City,
-					Temperature: weather.Temperature,
-					Description: weather.Condition.Text,
-					Humidity:    weather.Humidity,
-					Frequency:   string(sub.Frequency),
-				}); err != nil {
-					return fmt.Errorf("failed to send notification email: %w", err)
+			if err := n.enqueue(prepared); err != nil {
+				for _, p := range prepared {
+					n.handleFailure(p.sub, err)
 				}
-
-				return nil
-			})
-			if txErr != nil {
-				n.logger.WithError(txErr).Error("failed to process notification")
 				return
 			}
 
-			successNotifications.Add(1)
-		}(sub)
+			successNotifications.Add(int32(len(prepared)))
+		}(group)
 	}
 	wg.Wait()
 
 	return int(successNotifications.Load())
 }
 
-type weatherCache struct {
-	cache map[string]weatherapi.CurrentWeather
-	mu    *sync.Mutex
+// groupSubscriptions groups the subscriptions of the same subscriber if the merging is enabled,
+// every subscription makes its own group otherwise
+func (n *Notificator) groupSubscriptions(subs []database.Subscription) [][]database.Subscription {
+	groups := make([][]database.Subscription, 0, len(subs))
+	if !n.opts.MergeSubscriptions {
+		for _, sub := range subs {
+			groups = append(groups, []database.Subscription{sub})
+		}
+		return groups
+	}
+
+	indexes := make(map[int64]int)
+	for _, sub := range subs {
+		if i, ok := indexes[sub.SubscriberId]; ok {
+			groups[i] = append(groups[i], sub)
+			continue
+		}
+		indexes[sub.SubscriberId] = len(groups)
+		groups = append(groups, []database.Subscription{sub})
+	}
+
+	return groups
 }
 
-func newWeatherCache() *weatherCache {
-	return &weatherCache{
-		cache: make(map[string]weatherapi.CurrentWeather),
-		mu:    new(sync.Mutex),
+func (n *Notificator) handleFailure(sub database.Subscription, err error) {
+	logger := n.logger.WithField("subscription_id", sub.Id)
+	if errors.Is(err, database.ErrLeaseLost) {
+		// the subscription is up to another instance now, the failure is not ours to record
+		logger.WithError(err).Warn("subscription lease expired before the notification was processed")
+		return
+	}
+
+	logger.WithError(err).Error("failed to process notification")
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	lease := claimedLease(sub)
+	if !ownFault(err) {
+		nextRetryAt := time.Now().Add(n.opts.FailurePolicy.RetryBackoff)
+		err = n.db.New().SubscriptionsQ().UpdatePostponed(sub.Id, lease, err.Error(), nextRetryAt)
+		switch {
+		case errors.Is(err, database.ErrLeaseLost):
+			logger.WithError(err).Warn("subscription lease expired before the notification was postponed")
+		case err != nil:
+			logger.WithError(err).Error("failed to postpone notification")
+		}
+		return
+	}
+
+	suspended, err := recordFailure(n.db, n.opts.FailurePolicy, sub.Id, &lease, err)
+	switch {
+	case errors.Is(err, database.ErrLeaseLost):
+		logger.WithError(err).Warn("subscription lease expired before the failure was recorded")
+	case err != nil:
+		logger.WithError(err).Error("failed to record notification failure")
+	case suspended:
+		logger.Warn("subscription suspended after repeated notification failures")
 	}
 }
 
-func (w *weatherCache) Get(city string) (weatherapi.CurrentWeather, bool) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
+// claimedLease is the lease of the subscription claimed by SelectToNotify,
+// the zero one of the unclaimed subscription is never held
+func claimedLease(sub database.Subscription) time.Time {
+	if sub.LeaseExpiresAt == nil {
+		return time.Time{}
+	}
 
-	weather, ok := w.cache[city]
-	return weather, ok
+	return *sub.LeaseExpiresAt
 }
 
-func (w *weatherCache) Set(city string, weather weatherapi.CurrentWeather) {
-	w.mu.Lock()
-	defer w.mu.Unlock()
+// preparedNotification is the weather content of the due subscription, either the current weather or the daily digest
+type preparedNotification struct {
+	sub          database.Subscription
+	nextNotifyAt time.Time
+	current      *mailer.NotificationEmail
+	digest       *mailer.DailyDigestEmail
+}
+
+// notify enqueues the notification of the single subscription
+func (n *Notificator) notify(ctx context.Context, sub database.Subscription) error {
+	p, err := n.prepare(ctx, sub)
+	if err != nil {
+		return err
+	}
+
+	return n.enqueue([]preparedNotification{*p})
+}
+
+// prepare gets the forecast for the day if the subscription schedule fires at most once a day,
+// and the current weather otherwise
+func (n *Notificator) prepare(ctx context.Context, sub database.Subscription) (*preparedNotification, error) {
+	sched, err := schedule.Parse(sub.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule of id %v: %w", sub.Id, err)
+	}
+	// the missed activations (e.g. after a downtime) are not caught up, the next one is counted from now
+	p := &preparedNotification{
+		sub:          sub,
+		nextNotifyAt: sched.Next(time.Now().In(sub.Location())),
+	}
+
+	if sched.AtMostDaily() {
+		p.digest, err = n.dailyDigest(ctx, sub)
+	} else {
+		p.current, err = n.currentWeather(ctx, sub)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// currentWeather is a compact current-conditions snapshot, used for the frequent subscriptions
+func (n *Notificator) currentWeather(ctx context.Context, sub database.Subscription) (*mailer.NotificationEmail, error) {
+	response, err := n.weatherApi.GetCurrentWeather(weatherapi.WithLanguage(ctx, string(sub.Language.OrDefault())), weatherQuery(sub))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weather for city %s: %w", sub.City, err)
+	}
+	weather, units := response.CurrentWeather, sub.Units.OrDefault()
+
+	return &mailer.NotificationEmail{
+		Language:      sub.Language,
+		City:          sub.City,
+		Temperature:   mailer.Temperature{Celsius: weather.Temperature, Fahrenheit: weather.TemperatureF, Units: units},
+		FeelsLike:     mailer.Temperature{Celsius: weather.FeelsLike, Fahrenheit: weather.FeelsLikeF, Units: units},
+		Wind:          mailer.Speed{Kph: weather.WindKph, Mph: weather.WindMph, Units: units},
+		Gust:          mailer.Speed{Kph: weather.GustKph, Mph: weather.GustMph, Units: units},
+		WindDirection: weather.WindDirection,
+		Description:   weather.Condition.Text,
+		ConditionCode: weather.Condition.Code,
+		ConditionIcon: weather.Condition.Icon,
+		IsDay:         weather.IsDay == 1,
+		Humidity:      weather.Humidity,
+		UV:            weather.UV,
+		Frequency:     string(sub.Frequency),
+		Current:       weather,
+	}, nil
+}
+
+// dailyDigest is the forecast for the current (location-local) day, used for daily, weekly and alike subscriptions
+func (n *Notificator) dailyDigest(ctx context.Context, sub database.Subscription) (*mailer.DailyDigestEmail, error) {
+	response, err := n.weatherApi.GetForecast(weatherapi.WithLanguage(ctx, string(sub.Language.OrDefault())), weatherQuery(sub), 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get forecast for city %s: %w", sub.City, err)
+	} else if len(response.Forecast.Days) == 0 {
+		return nil, fmt.Errorf("empty forecast for city %s", sub.City)
+	}
+	digest := newDailyDigestEmail(sub, response.Forecast.Days[0])
+
+	return &digest, nil
+}
+
+func weatherQuery(sub database.Subscription) string {
+	return locationQuery(sub.City, sub.LocationId, sub.Latitude, sub.Longitude)
+}
+
+// locationQuery prefers the resolved coordinates, as they are unambiguous and supported by every provider
+func locationQuery(city, locationId string, latitude, longitude float64) string {
+	if locationId == "" {
+		return city
+	}
+
+	return weatherapi.CoordinatesQuery(latitude, longitude)
+}
+
+// enqueue renders the prepared notifications of one subscriber into one message, stores it into the outbox
+// and schedules the next notifications of the subscriptions within one transaction, the delivery itself is done by the Dispatcher.
+// The merged message is attributed to the first subscription and lists the others in MergedSubscriptionIds,
+// so its delivery outcome is tracked by all of them.
+func (n *Notificator) enqueue(prepared []preparedNotification) error {
+	var (
+		first     = prepared[0]
+		message   mailer.Message
+		key       = idempotencyKey(first.sub)
+		target    = unsubscribe.Target{Kind: unsubscribe.KindSubscription, Id: first.sub.Id}
+		mergedIds database.SubscriptionIds
+	)
+	switch {
+	case len(prepared) > 1:
+		// the merged subscriptions may differ in language, the first one is taken
+		merged := mailer.MergedEmail{Language: first.sub.Language}
+		for _, p := range prepared {
+			if p.digest != nil {
+				merged.Digests = append(merged.Digests, *p.digest)
+			} else {
+				merged.Notifications = append(merged.Notifications, *p.current)
+			}
+		}
+		message = n.mailer.MergedMessage(first.sub.Email, merged)
+		key = "merged:" + key
+		for _, p := range prepared[1:] {
+			mergedIds = append(mergedIds, p.sub.Id)
+		}
+		// the merged email covers several subscriptions, so its one-click link unsubscribes the subscriber from all of them
+		target = unsubscribe.Target{Kind: unsubscribe.KindSubscriber, Id: first.sub.SubscriberId}
+	case first.digest != nil:
+		message = n.mailer.DailyDigestMessage(first.sub.Email, *first.digest)
+	default:
+		message = n.mailer.NotificationMessage(first.sub.Email, *first.current)
+	}
+
+	now := time.Now()
+	subscriptionId := first.sub.Id
+	notification := database.Notification{
+		SubscriptionId:        &subscriptionId,
+		MergedSubscriptionIds: mergedIds,
+		IdempotencyKey:        key,
+		Email:                 message.To,
+		Subject:               message.Subject,
+		Body:                  message.Body,
+		TextBody:              message.TextBody,
+		Headers:               unsubscribeHeaders(n.opts.Unsubscribe, target),
+		InlineImages:          message.InlineImages,
+		Status:                database.NotificationStatusPending,
+		NextAttemptAt:         now,
+		CreatedAt:             now,
+	}
+
+	db := n.db.New()
+	return db.Transaction(func() error {
+		// the period is already enqueued (e.g. the previous run has died before updating the subscription),
+		// so only the subscriptions are brought up to date
+		if _, err := db.NotificationsQ().Insert(notification); err != nil && !errors.Is(err, database.ErrNotificationExists) {
+			return fmt.Errorf("failed to enqueue notification for id %v: %w", subscriptionId, err)
+		}
+
+		for _, p := range prepared {
+			if err := db.SubscriptionsQ().UpdateLastNotified(p.sub.Id, claimedLease(p.sub), now, p.nextNotifyAt); err != nil {
+				return fmt.Errorf("failed to update last notified for id %v: %w", p.sub.Id, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// idempotencyKey identifies the notified schedule activation, e.g. "42:2025-06-01T10:00:00Z"
+func idempotencyKey(sub database.Subscription) string {
+	var slot time.Time
+	if sub.NextNotifyAt != nil {
+		slot = *sub.NextNotifyAt
+	}
 
-	w.cache[city] = weather
+	return fmt.Sprintf("%d:%s", sub.Id, slot.UTC().Format(time.RFC3339))
 }
@@ -0,0 +1,233 @@
+package notificator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	dbMock "github.com/slbmax/ses-weather-app/internal/database/mock"
+	"github.com/slbmax/ses-weather-app/internal/mailer"
+	mailerMock "github.com/slbmax/ses-weather-app/internal/mailer/mock"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
+	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
+	weatherMock "github.com/slbmax/ses-weather-app/pkg/weatherapi/mock"
+	"github.com/stretchr/testify/mock"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+func TestNotificator_Notify(t *testing.T) {
+	slot := time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC)
+	lease := time.Now().Add(time.Minute)
+	newSubscription := func(frequency database.SubscriptionFrequency, schedule string) database.Subscription {
+		return database.Subscription{
+			Id:             42,
+			Email:          "max@gmail.com",
+			City:           "New York",
+			Timezone:       "America/New_York",
+			Frequency:      frequency,
+			Schedule:       schedule,
+			NextNotifyAt:   &slot,
+			LeaseExpiresAt: &lease,
+		}
+	}
+	message := mailer.Message{To: "max@gmail.com", Subject: "subject", Body: "<p>body</p>", TextBody: "body"}
+	forecast := &weatherapi.WeatherForecastResponse{
+		Forecast: weatherapi.Forecast{Days: []weatherapi.ForecastDay{{Date: "2025-06-02"}}},
+	}
+
+	testCases := map[string]struct {
+		sub          database.Subscription
+		preparation  func(weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer)
+		expectedNext func(next time.Time) bool
+		expectErr    bool
+	}{
+		"must send current weather for hourly": {
+			sub: newSubscription(database.SubscriptionFrequencyHourly, "0 * * * *"),
+			preparation: func(weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer) {
+				weather.On("GetCurrentWeather", mock.Anything, "New York").Return(&weatherapi.WeatherCurrentResponse{}, nil)
+				mail.On("NotificationMessage", "max@gmail.com", mock.Anything).Return(message)
+			},
+			expectedNext: func(next time.Time) bool {
+				return next.Minute() == 0 && time.Until(next) <= time.Hour
+			},
+		},
+		"must send current weather for every 3 hours": {
+			sub: newSubscription(database.SubscriptionFrequencyCustom, "0 */3 * * *"),
+			preparation: func(weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer) {
+				weather.On("GetCurrentWeather", mock.Anything, "New York").Return(&weatherapi.WeatherCurrentResponse{}, nil)
+				mail.On("NotificationMessage", "max@gmail.com", mock.Anything).Return(message)
+			},
+			expectedNext: func(next time.Time) bool {
+				return next.Hour()%3 == 0 && time.Until(next) <= 3*time.Hour
+			},
+		},
+		"must send digest for weekdays": {
+			sub: newSubscription(database.SubscriptionFrequencyCustom, "0 7 * * MON-FRI"),
+			preparation: func(weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer) {
+				weather.On("GetForecast", mock.Anything, "New York", 1).Return(forecast, nil)
+				mail.On("DailyDigestMessage", "max@gmail.com", mock.Anything).Return(message)
+			},
+			expectedNext: func(next time.Time) bool {
+				next = next.In(newSubscription("", "").Location())
+				return next.Hour() == 7 && next.Weekday() != time.Saturday && next.Weekday() != time.Sunday
+			},
+		},
+		"must fail for invalid schedule": {
+			sub:       newSubscription(database.SubscriptionFrequencyCustom, "0 7 * *"),
+			expectErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			subscriptions := dbMock.NewMockSubscriptionsQ(t)
+			notifications := dbMock.NewMockNotificationsQ(t)
+			weather := weatherMock.NewMockWeatherProvider(t)
+			mail := mailerMock.NewMockMailer(t)
+			if tc.preparation != nil {
+				tc.preparation(weather, mail)
+				notifications.On("Insert", mock.MatchedBy(func(notification database.Notification) bool {
+					return notification.IdempotencyKey == "42:2025-06-02T11:00:00Z" &&
+						notification.Body == message.Body && notification.TextBody == message.TextBody
+				})).Return(int64(1), nil)
+				subscriptions.On("UpdateLastNotified", int64(42), lease, mock.Anything, mock.MatchedBy(tc.expectedNext)).Return(nil)
+			}
+
+			n := New(dbMock.NewDatabase(subscriptions, notifications, nil, nil, nil), weather, mail, logan.New().Level(logan.ErrorLevel), Opts{})
+
+			err := n.notify(context.Background(), tc.sub)
+			if tc.expectErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestNotificator_MergeSubscriptions(t *testing.T) {
+	slot := time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC)
+	lease := time.Now().Add(time.Minute)
+	newSubscription := func(id, subscriberId int64, email, city string) database.Subscription {
+		return database.Subscription{
+			Id:             id,
+			SubscriberId:   subscriberId,
+			Email:          email,
+			City:           city,
+			Frequency:      database.SubscriptionFrequencyHourly,
+			Schedule:       "0 * * * *",
+			NextNotifyAt:   &slot,
+			LeaseExpiresAt: &lease,
+		}
+	}
+	subs := []database.Subscription{
+		newSubscription(1, 10, "max@gmail.com", "Kyiv"),
+		newSubscription(2, 20, "ann@gmail.com", "Lviv"),
+		newSubscription(3, 10, "max@gmail.com", "Odesa"),
+	}
+
+	subscriptions := dbMock.NewMockSubscriptionsQ(t)
+	notifications := dbMock.NewMockNotificationsQ(t)
+	weather := weatherMock.NewMockWeatherProvider(t)
+	mail := mailerMock.NewMockMailer(t)
+	links := unsubscribe.NewLinks("signing-key", "http://localhost:8090/api")
+
+	weather.On("GetCurrentWeather", mock.Anything, mock.Anything).Return(&weatherapi.WeatherCurrentResponse{}, nil)
+	mail.On("MergedMessage", "max@gmail.com", mock.MatchedBy(func(email mailer.MergedEmail) bool {
+		return len(email.Notifications) == 2 && len(email.Digests) == 0
+	})).Return(mailer.Message{To: "max@gmail.com"}).Once()
+	mail.On("NotificationMessage", "ann@gmail.com", mock.Anything).Return(mailer.Message{To: "ann@gmail.com"}).Once()
+	notifications.On("Insert", mock.MatchedBy(func(notification database.Notification) bool {
+		// the merged email unsubscribes the subscriber from all the merged subscriptions
+		// the delivery outcome is tracked by all the merged subscriptions
+		return notification.IdempotencyKey == "merged:1:2025-06-02T11:00:00Z" && *notification.SubscriptionId == 1 &&
+			reflect.DeepEqual(notification.MergedSubscriptionIds, database.SubscriptionIds{3}) &&
+			reflect.DeepEqual(notification.Headers, database.MessageHeaders(links.Headers(unsubscribe.Target{Kind: unsubscribe.KindSubscriber, Id: 10})))
+	})).Return(int64(1), nil).Once()
+	notifications.On("Insert", mock.MatchedBy(func(notification database.Notification) bool {
+		return notification.IdempotencyKey == "2:2025-06-02T11:00:00Z" && notification.MergedSubscriptionIds == nil &&
+			reflect.DeepEqual(notification.Headers, database.MessageHeaders(links.Headers(unsubscribe.Target{Kind: unsubscribe.KindSubscription, Id: 2})))
+	})).Return(int64(2), nil).Once()
+	for _, sub := range subs {
+		subscriptions.On("UpdateLastNotified", sub.Id, lease, mock.Anything, mock.Anything).Return(nil).Once()
+	}
+
+	n := New(dbMock.NewDatabase(subscriptions, notifications, nil, nil, nil), weather, mail,
+		logan.New().Level(logan.ErrorLevel), Opts{MergeSubscriptions: true, Unsubscribe: links})
+
+	if processed := n.processPendingNotifications(context.Background(), subs); processed != len(subs) {
+		t.Fatalf("expected %d processed subscriptions, got %d", len(subs), processed)
+	}
+}
+
+func TestNotificator_ProcessBatch(t *testing.T) {
+	slot := time.Date(2025, 6, 2, 11, 0, 0, 0, time.UTC)
+	lease := time.Now().Add(time.Minute)
+	sub := database.Subscription{
+		Id:             42,
+		Email:          "max@gmail.com",
+		City:           "Kyiv",
+		Frequency:      database.SubscriptionFrequencyHourly,
+		Schedule:       "0 * * * *",
+		NextNotifyAt:   &slot,
+		LeaseExpiresAt: &lease,
+	}
+	opts := Opts{BatchSize: 10, Lease: time.Minute}
+
+	testCases := map[string]struct {
+		preparation func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer)
+	}{
+		"must release the claimed lease": {
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer) {
+				weather.On("GetCurrentWeather", mock.Anything, "Kyiv").Return(&weatherapi.WeatherCurrentResponse{}, nil)
+				mail.On("NotificationMessage", "max@gmail.com", mock.Anything).Return(mailer.Message{To: "max@gmail.com"})
+				notifications.On("Insert", mock.Anything).Return(int64(1), nil)
+				subscriptions.On("UpdateLastNotified", sub.Id, lease, mock.Anything, mock.Anything).Return(nil)
+			},
+		},
+		"must postpone without suspending on provider error": {
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, _ *dbMock.MockNotificationsQ, weather *weatherMock.MockWeatherProvider, _ *mailerMock.MockMailer) {
+				weather.On("GetCurrentWeather", mock.Anything, "Kyiv").Return(nil, errors.New("weather is down"))
+				// the outage is not counted, so neither UpdateFailure nor UpdateSuspended is called
+				subscriptions.On("UpdatePostponed", sub.Id, lease, mock.Anything, mock.MatchedBy(func(nextRetryAt time.Time) bool {
+					return nextRetryAt.After(time.Now())
+				})).Return(nil)
+			},
+		},
+		"must count the failure of unknown location": {
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, _ *dbMock.MockNotificationsQ, weather *weatherMock.MockWeatherProvider, _ *mailerMock.MockMailer) {
+				weather.On("GetCurrentWeather", mock.Anything, "Kyiv").Return(nil, weatherapi.ErrCityNotFound)
+				subscriptions.On("UpdateFailure", sub.Id, mock.Anything, &lease).Return(1, nil)
+				subscriptions.On("UpdateNextRetry", sub.Id, mock.Anything).Return(nil)
+			},
+		},
+		"must not record failure once the lease is lost": {
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, notifications *dbMock.MockNotificationsQ, weather *weatherMock.MockWeatherProvider, mail *mailerMock.MockMailer) {
+				weather.On("GetCurrentWeather", mock.Anything, "Kyiv").Return(&weatherapi.WeatherCurrentResponse{}, nil)
+				mail.On("NotificationMessage", "max@gmail.com", mock.Anything).Return(mailer.Message{To: "max@gmail.com"})
+				notifications.On("Insert", mock.Anything).Return(int64(1), nil)
+				// the subscription has been claimed by another instance, so it is neither released nor failed here
+				subscriptions.On("UpdateLastNotified", sub.Id, lease, mock.Anything, mock.Anything).Return(database.ErrLeaseLost)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			subscriptions := dbMock.NewMockSubscriptionsQ(t)
+			notifications := dbMock.NewMockNotificationsQ(t)
+			weather := weatherMock.NewMockWeatherProvider(t)
+			mail := mailerMock.NewMockMailer(t)
+			subscriptions.On("SelectToNotify", opts.BatchSize, opts.Lease).Return([]database.Subscription{sub}, nil)
+			tc.preparation(subscriptions, notifications, weather, mail)
+
+			n := New(dbMock.NewDatabase(subscriptions, notifications, nil, nil, nil), weather, mail, logan.New().Level(logan.PanicLevel), opts)
+
+			// the lease is released or lost as expected by the mocks
+			if claimed := n.processBatch(context.Background()); claimed != 1 {
+				t.Fatalf("expected 1 claimed subscription, got %d", claimed)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package notificator
+
+import (
+	"context"
+	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+const (
+	defaultPurgeInterval = time.Hour
+	defaultPurgeAfter    = 7 * 24 * time.Hour
+)
+
+// PurgerOpts is optional, zero values are replaced with defaults
+type PurgerOpts struct {
+	Interval time.Duration
+	// PurgeAfter is the time the unconfirmed subscriptions and alert rules are kept after the last confirmation email
+	PurgeAfter time.Duration
+}
+
+// Purger periodically deletes the subscriptions and alert rules never confirmed and the subscribers left without subscriptions.
+// The deletion is idempotent, so several instances may run concurrently.
+type Purger struct {
+	db     database.Database
+	logger *logan.Entry
+	opts   PurgerOpts
+}
+
+func NewPurger(db database.Database, logger *logan.Entry, opts PurgerOpts) *Purger {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultPurgeInterval
+	}
+	if opts.PurgeAfter <= 0 {
+		opts.PurgeAfter = defaultPurgeAfter
+	}
+
+	return &Purger{
+		db:     db,
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for ; ; waitForTickerOrCtx(ctx, ticker) {
+		if isCancelled(ctx) {
+			p.logger.Info("purger stopped")
+			return
+		}
+
+		p.purge()
+	}
+}
+
+func (p *Purger) purge() {
+	sentBefore := time.Now().Add(-p.opts.PurgeAfter)
+	subscriptions, err := p.db.SubscriptionsQ().DeleteUnconfirmed(sentBefore)
+	if err != nil {
+		p.logger.WithError(err).Error("failed to delete unconfirmed subscriptions")
+		return
+	}
+
+	alertRules, err := p.db.AlertRulesQ().DeleteUnconfirmed(sentBefore)
+	if err != nil {
+		p.logger.WithError(err).Error("failed to delete unconfirmed alert rules")
+		return
+	}
+
+	subscribers, err := p.db.SubscribersQ().PurgeUnused()
+	if err != nil {
+		p.logger.WithError(err).Error("failed to delete unused subscribers")
+		return
+	}
+
+	if subscriptions > 0 || alertRules > 0 || subscribers > 0 {
+		p.logger.WithFields(logan.F{
+			"subscriptions": subscriptions,
+			"alert_rules":   alertRules,
+			"subscribers":   subscribers,
+		}).Info("purged unconfirmed subscriptions")
+	}
+}
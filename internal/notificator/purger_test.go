@@ -0,0 +1,54 @@
+package notificator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	dbMock "github.com/slbmax/ses-weather-app/internal/database/mock"
+	"github.com/stretchr/testify/mock"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+func TestPurger_Purge(t *testing.T) {
+	testCases := map[string]struct {
+		preparation func(subscriptions *dbMock.MockSubscriptionsQ, alertRules *dbMock.MockAlertRulesQ, subscribers *dbMock.MockSubscribersQ)
+	}{
+		"must delete unconfirmed subscriptions and alert rules and then unused subscribers": {
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, alertRules *dbMock.MockAlertRulesQ, subscribers *dbMock.MockSubscribersQ) {
+				sentBefore := mock.MatchedBy(func(sentBefore time.Time) bool {
+					return sentBefore.Before(time.Now().Add(-defaultPurgeAfter + time.Minute))
+				})
+				subscriptions.On("DeleteUnconfirmed", sentBefore).Return(int64(2), nil)
+				alertRules.On("DeleteUnconfirmed", sentBefore).Return(int64(1), nil)
+				subscribers.On("PurgeUnused").Return(int64(1), nil)
+			},
+		},
+		"must not delete subscribers if subscriptions are not deleted": {
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, _ *dbMock.MockAlertRulesQ, _ *dbMock.MockSubscribersQ) {
+				subscriptions.On("DeleteUnconfirmed", mock.Anything).Return(int64(0), errors.New("db error"))
+			},
+		},
+		"must not delete subscribers if alert rules are not deleted": {
+			preparation: func(subscriptions *dbMock.MockSubscriptionsQ, alertRules *dbMock.MockAlertRulesQ, _ *dbMock.MockSubscribersQ) {
+				subscriptions.On("DeleteUnconfirmed", mock.Anything).Return(int64(0), nil)
+				alertRules.On("DeleteUnconfirmed", mock.Anything).Return(int64(0), errors.New("db error"))
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			subscriptions := dbMock.NewMockSubscriptionsQ(t)
+			alertRules := dbMock.NewMockAlertRulesQ(t)
+			subscribers := dbMock.NewMockSubscribersQ(t)
+			tc.preparation(subscriptions, alertRules, subscribers)
+
+			NewPurger(
+				dbMock.NewDatabase(subscriptions, nil, alertRules, subscribers, nil),
+				logan.New().Level(logan.FatalLevel),
+				PurgerOpts{},
+			).purge()
+		})
+	}
+}
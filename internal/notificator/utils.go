@@ -3,6 +3,9 @@ package notificator
 import (
 	"context"
 	"time"
+
+	"github.com/slbmax/ses-weather-app/internal/database"
+	"github.com/slbmax/ses-weather-app/internal/unsubscribe"
 )
 
 func waitForTickerOrCtx(ctx context.Context, ticker *time.Ticker) {
@@ -20,3 +23,12 @@ func isCancelled(ctx context.Context) bool {
 		return false
 	}
 }
+
+// unsubscribeHeaders returns the one-click unsubscribe headers of the target, or nil if the links are not configured
+func unsubscribeHeaders(links *unsubscribe.Links, target unsubscribe.Target) database.MessageHeaders {
+	if links == nil {
+		return nil
+	}
+
+	return links.Headers(target)
+}
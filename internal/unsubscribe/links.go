@@ -0,0 +1,106 @@
+package unsubscribe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of the list the link unsubscribes from
+type Kind string
+
+const (
+	KindSubscription Kind = "s"
+	KindAlertRule    Kind = "a"
+	// KindSubscriber unsubscribes all the subscriptions of the subscriber, it is used by the merged emails
+	KindSubscriber Kind = "u"
+)
+
+const (
+	signatureLength = 32
+	// signingContext separates these signatures from any other use of the key
+	signingContext = "unsubscribe:"
+
+	HeaderListUnsubscribe     = "List-Unsubscribe"
+	HeaderListUnsubscribePost = "List-Unsubscribe-Post"
+	// OneClickBody is the RFC 8058 value of the List-Unsubscribe-Post header and of the POST request body
+	OneClickBody = "List-Unsubscribe=One-Click"
+)
+
+// TokenRegex matches the signed tokens, e.g. "s-42.<signature>"
+var TokenRegex = regexp.MustCompile(`^[sau]-[0-9]+\.[a-f0-9]{32}$`)
+
+var ErrInvalidToken = errors.New("invalid unsubscribe token")
+
+// Target is the subscription, alert rule or subscriber the link unsubscribes
+type Target struct {
+	Kind Kind
+	Id   int64
+}
+
+func (t Target) String() string {
+	return fmt.Sprintf("%s-%d", t.Kind, t.Id)
+}
+
+// Links signs the one-click unsubscribe links. The links are stateless: nothing is stored,
+// the token is the target signed with the HMAC-SHA256 key, so it is valid while the key is.
+type Links struct {
+	key     []byte
+	baseURL string
+}
+
+// NewLinks creates the signer of the links to baseURL + "/unsubscribe/{token}", baseURL is the public API URL
+func NewLinks(signingKey, baseURL string) *Links {
+	return &Links{
+		key:     []byte(signingKey),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (l *Links) Token(target Target) string {
+	return target.String() + "." + l.sign(target.String())
+}
+
+// Parse verifies the signed token, returning ErrInvalidToken if it is malformed or not signed with the key
+func (l *Links) Parse(token string) (Target, error) {
+	if !TokenRegex.MatchString(token) {
+		return Target{}, ErrInvalidToken
+	}
+
+	payload, signature, _ := strings.Cut(token, ".")
+	if !hmac.Equal([]byte(signature), []byte(l.sign(payload))) {
+		return Target{}, ErrInvalidToken
+	}
+
+	kind, id, _ := strings.Cut(payload, "-")
+	targetId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return Target{}, ErrInvalidToken
+	}
+
+	return Target{Kind: Kind(kind), Id: targetId}, nil
+}
+
+func (l *Links) URL(target Target) string {
+	return l.baseURL + "/unsubscribe/" + l.Token(target)
+}
+
+// Headers returns the RFC 8058 one-click unsubscribe headers of the message
+func (l *Links) Headers(target Target) map[string]string {
+	return map[string]string{
+		HeaderListUnsubscribe:     "<" + l.URL(target) + ">",
+		HeaderListUnsubscribePost: OneClickBody,
+	}
+}
+
+func (l *Links) sign(payload string) string {
+	mac := hmac.New(sha256.New, l.key)
+	mac.Write([]byte(signingContext + payload))
+
+	return hex.EncodeToString(mac.Sum(nil))[:signatureLength]
+}
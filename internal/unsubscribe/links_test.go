@@ -0,0 +1,66 @@
+package unsubscribe
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLinks_Parse(t *testing.T) {
+	links := NewLinks("signing-key", "http://localhost:8090/api/")
+	target := Target{Kind: KindSubscription, Id: 42}
+	token := links.Token(target)
+
+	testCases := map[string]struct {
+		links    *Links
+		token    string
+		expected Target
+		err      error
+	}{
+		"must parse the signed token": {
+			links:    links,
+			token:    token,
+			expected: target,
+		},
+		"must reject the tampered target": {
+			links: links,
+			token: strings.Replace(token, "s-42", "s-43", 1),
+			err:   ErrInvalidToken,
+		},
+		"must reject the token signed with another key": {
+			links: NewLinks("other-key", ""),
+			token: token,
+			err:   ErrInvalidToken,
+		},
+		"must reject the malformed token": {
+			links: links,
+			token: "x-42." + strings.Repeat("0", signatureLength),
+			err:   ErrInvalidToken,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := tc.links.Parse(tc.token)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if parsed != tc.expected {
+				t.Fatalf("expected target %v, got %v", tc.expected, parsed)
+			}
+		})
+	}
+}
+
+func TestLinks_Headers(t *testing.T) {
+	links := NewLinks("signing-key", "http://localhost:8090/api/")
+	target := Target{Kind: KindAlertRule, Id: 7}
+
+	headers := links.Headers(target)
+	if expected := "<http://localhost:8090/api/unsubscribe/" + links.Token(target) + ">"; headers[HeaderListUnsubscribe] != expected {
+		t.Fatalf("expected %s header %s, got %s", HeaderListUnsubscribe, expected, headers[HeaderListUnsubscribe])
+	}
+	if headers[HeaderListUnsubscribePost] != OneClickBody {
+		t.Fatalf("expected %s header %s, got %s", HeaderListUnsubscribePost, OneClickBody, headers[HeaderListUnsubscribePost])
+	}
+}
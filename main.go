@@ -1,6 +1,11 @@
 package main
 
-import "github.com/slbmax/ses-weather-app/cmd"
+import (
+	// the subscription schedules rely on the IANA timezones, which are missing in the slim images
+	_ "time/tzdata"
+
+	"github.com/slbmax/ses-weather-app/cmd"
+)
 
 func main() {
 	cmd.Execute()
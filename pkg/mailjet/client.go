@@ -1,6 +1,7 @@
 package mailjet
 
 import (
+	"encoding/base64"
 	"fmt"
 
 	"github.com/mailjet/mailjet-apiv3-go/v4"
@@ -11,6 +12,27 @@ type From struct {
 	Name  string
 }
 
+type Message struct {
+	To       string
+	Subject  string
+	HTMLPart string
+	// TextPart is the plain-text alternative of the HTMLPart
+	TextPart string
+	// CustomID is attached to the message events, so the deliveries can be traced back
+	CustomID string
+	// Headers are added to the message as is, e.g. List-Unsubscribe
+	Headers map[string]string
+	// InlineImages are referenced from the HTMLPart by their "cid:" URLs
+	InlineImages []InlineImage
+}
+
+type InlineImage struct {
+	ContentID   string
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
 type Client struct {
 	mailjet *mailjet.Client
 	from    From
@@ -23,7 +45,30 @@ func NewClient(mailjetKey, mailjetSecret string, from From) *Client {
 	}
 }
 
-func (c *Client) Send(to, subject string, bodyHtml string) error {
+func (c *Client) Send(message Message) error {
+	var headers map[string]interface{}
+	if len(message.Headers) > 0 {
+		headers = make(map[string]interface{}, len(message.Headers))
+		for name, value := range message.Headers {
+			headers[name] = value
+		}
+	}
+
+	var inlined *mailjet.InlinedAttachmentsV31
+	if len(message.InlineImages) > 0 {
+		inlined = &mailjet.InlinedAttachmentsV31{}
+		for _, image := range message.InlineImages {
+			*inlined = append(*inlined, mailjet.InlinedAttachmentV31{
+				AttachmentV31: mailjet.AttachmentV31{
+					ContentType:   image.ContentType,
+					Filename:      image.Filename,
+					Base64Content: base64.StdEncoding.EncodeToString(image.Data),
+				},
+				ContentID: image.ContentID,
+			})
+		}
+	}
+
 	msgInfo := []mailjet.InfoMessagesV31{
 		{
 			From: &mailjet.RecipientV31{
@@ -32,11 +77,15 @@ func (c *Client) Send(to, subject string, bodyHtml string) error {
 			},
 			To: &mailjet.RecipientsV31{
 				{
-					Email: to,
+					Email: message.To,
 				},
 			},
-			Subject:  subject,
-			HTMLPart: bodyHtml,
+			Subject:            message.Subject,
+			HTMLPart:           message.HTMLPart,
+			TextPart:           message.TextPart,
+			CustomID:           message.CustomID,
+			Headers:            headers,
+			InlinedAttachments: inlined,
 		},
 	}
 
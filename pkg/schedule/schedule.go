@@ -0,0 +1,247 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchHorizon bounds the search of the next activation, the schedules that never fire (e.g. "0 0 30 2 *")
+// are rejected by Parse
+const searchHorizon = 5 * 366
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+var macros = map[string]string{
+	"@hourly": "0 * * * *",
+	"@daily":  "0 0 * * *",
+	"@weekly": "0 0 * * 0",
+}
+
+// field describes the range of the cron field and its optional value names
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12,
+		names: []string{"", "JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}}
+	// 7 is an alias of Sunday
+	dowField = field{name: "day of week", min: 0, max: 7,
+		names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}}
+)
+
+// Schedule is a parsed cron expression of five fields: minute, hour, day of month, month and day of week.
+// Every field supports "*", values, names (JAN-DEC, SUN-SAT), ranges, steps and lists, e.g. "0 7 * * MON-FRI"
+// or "0 */3 * * *". The @hourly, @daily and @weekly macros are supported as well.
+type Schedule struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	domRestricted, dowRestricted  bool
+}
+
+// Parse parses the cron expression, the schedule must fire at least once within a few years
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidSchedule, len(fields))
+	}
+
+	s := &Schedule{
+		expr:          strings.Join(fields, " "),
+		domRestricted: fields[2] != "*",
+		dowRestricted: fields[4] != "*",
+	}
+
+	var err error
+	for i, target := range []struct {
+		bits  *uint64
+		field field
+	}{
+		{&s.minute, minuteField},
+		{&s.hour, hourField},
+		{&s.dom, domField},
+		{&s.month, monthField},
+		{&s.dow, dowField},
+	} {
+		if *target.bits, err = parseField(fields[i], target.field); err != nil {
+			return nil, err
+		}
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	if s.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, fmt.Errorf("%w: schedule never fires", ErrInvalidSchedule)
+	}
+
+	return s, nil
+}
+
+// String returns the normalized expression, it can be parsed back
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// MaxPerHour returns the maximum number of activations within an hour
+func (s *Schedule) MaxPerHour() int {
+	return bits.OnesCount64(s.minute)
+}
+
+// AtMostDaily reports whether the schedule fires at most once a day
+func (s *Schedule) AtMostDaily() bool {
+	return bits.OnesCount64(s.minute) == 1 && bits.OnesCount64(s.hour) == 1
+}
+
+// Next returns the first activation strictly after the given time, evaluated in its location,
+// or zero time if there is none within the search horizon. The activations follow the wall clock,
+// so the ones falling into a DST gap are shifted forward by the gap, and the ones
+// in a repeated hour fire once.
+func (s *Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	year, month, day := after.Date()
+
+	for i := 0; i < searchHorizon; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, loc)
+		if !s.matchesDay(date) {
+			continue
+		}
+
+		for h := hourField.min; h <= hourField.max; h++ {
+			if s.hour&(1<<h) == 0 {
+				continue
+			}
+			for m := minuteField.min; m <= minuteField.max; m++ {
+				if s.minute&(1<<m) == 0 {
+					continue
+				}
+				if next := wallClock(date, h, m); next.After(after) {
+					return next
+				}
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// wallClock returns the moment of the local time h:m on the date. The local time falling into a DST gap
+// is interpreted with the offset before the gap (i.e. shifted forward), as time.Date does not guarantee the direction.
+func wallClock(date time.Time, h, m int) time.Time {
+	t := time.Date(date.Year(), date.Month(), date.Day(), h, m, 0, 0, date.Location())
+	if t.Hour() == h && t.Minute() == m {
+		return t
+	}
+
+	_, offsetBefore := t.Add(-12 * time.Hour).Zone()
+	wall := time.Date(date.Year(), date.Month(), date.Day(), h, m, 0, 0, time.UTC)
+
+	return wall.Add(-time.Duration(offsetBefore) * time.Second).In(date.Location())
+}
+
+// matchesDay follows the cron rule: if both day of month and day of week are restricted,
+// the day matches either of them
+func (s *Schedule) matchesDay(date time.Time) bool {
+	if s.month&(1<<uint(date.Month())) == 0 {
+		return false
+	}
+
+	domMatches := s.dom&(1<<uint(date.Day())) != 0
+	dowMatches := s.dow&(1<<uint(date.Weekday())) != 0
+	if s.domRestricted && s.dowRestricted {
+		return domMatches || dowMatches
+	}
+
+	return domMatches && dowMatches
+}
+
+func parseField(raw string, f field) (uint64, error) {
+	var result uint64
+	for _, part := range strings.Split(raw, ",") {
+		bitset, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		result |= bitset
+	}
+
+	return result, nil
+}
+
+// parseRange parses "*", "v", "a-b" with the optional "/step" suffix
+func parseRange(raw string, f field) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(raw, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+			return 0, fmt.Errorf("%w: invalid %s step %q", ErrInvalidSchedule, f.name, stepPart)
+		}
+	}
+
+	var (
+		from, to int
+		err      error
+	)
+	switch {
+	case rangePart == "*":
+		from, to = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		fromPart, toPart, _ := strings.Cut(rangePart, "-")
+		if from, err = parseValue(fromPart, f); err != nil {
+			return 0, err
+		}
+		if to, err = parseValue(toPart, f); err != nil {
+			return 0, err
+		}
+		if from > to {
+			return 0, fmt.Errorf("%w: invalid %s range %q", ErrInvalidSchedule, f.name, rangePart)
+		}
+	default:
+		if from, err = parseValue(rangePart, f); err != nil {
+			return 0, err
+		}
+		to = from
+		// "v/step" means "from v to the end with the step"
+		if hasStep {
+			to = f.max
+		}
+	}
+
+	var bitset uint64
+	for v := from; v <= to; v += step {
+		bitset |= 1 << v
+	}
+
+	return bitset, nil
+}
+
+func parseValue(raw string, f field) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(raw, name) {
+			return i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: invalid %s value %q", ErrInvalidSchedule, f.name, raw)
+	}
+
+	return v, nil
+}
@@ -0,0 +1,106 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	testCases := map[string]struct {
+		expr        string
+		expectedErr error
+		expected    string
+	}{
+		"must parse weekdays":          {expr: "0 7 * * MON-FRI", expected: "0 7 * * MON-FRI"},
+		"must parse steps":             {expr: " 0  */3 * * * ", expected: "0 */3 * * *"},
+		"must parse macro":             {expr: "@weekly", expected: "0 0 * * 0"},
+		"must fail (too few fields)":   {expr: "0 7 * *", expectedErr: ErrInvalidSchedule},
+		"must fail (out of range)":     {expr: "0 24 * * *", expectedErr: ErrInvalidSchedule},
+		"must fail (reversed range)":   {expr: "0 7 * * 5-1", expectedErr: ErrInvalidSchedule},
+		"must fail (zero step)":        {expr: "0 */0 * * *", expectedErr: ErrInvalidSchedule},
+		"must fail (unknown name)":     {expr: "0 7 * * MONDAY", expectedErr: ErrInvalidSchedule},
+		"must fail (never fires)":      {expr: "0 0 30 2 *", expectedErr: ErrInvalidSchedule},
+		"must fail (empty expression)": {expr: "", expectedErr: ErrInvalidSchedule},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			if !errors.Is(err, tc.expectedErr) {
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			} else if err == nil && s.String() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, s.String())
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	testCases := map[string]struct {
+		expr     string
+		after    time.Time
+		expected time.Time
+	}{
+		"every 3 hours": {
+			expr:     "0 */3 * * *",
+			after:    time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+		},
+		"is strictly after": {
+			expr:     "0 12 * * *",
+			after:    time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+		},
+		"weekdays skip the weekend": {
+			expr: "0 7 * * MON-FRI",
+			// Friday
+			after:    time.Date(2025, 6, 6, 8, 0, 0, 0, newYork),
+			expected: time.Date(2025, 6, 9, 7, 0, 0, 0, newYork),
+		},
+		"weekly on sunday": {
+			expr:     "0 9 * * 7",
+			after:    time.Date(2025, 6, 2, 0, 0, 0, 0, newYork),
+			expected: time.Date(2025, 6, 8, 9, 0, 0, 0, newYork),
+		},
+		"day of month or day of week": {
+			expr: "0 9 1 * SUN",
+			// Monday, so the 1st of July comes before the next Sunday
+			after:    time.Date(2025, 6, 30, 10, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC),
+		},
+		"keeps the local hour across dst": {
+			expr:     "0 7 * * *",
+			after:    time.Date(2025, 3, 8, 7, 0, 0, 0, newYork),
+			expected: time.Date(2025, 3, 9, 7, 0, 0, 0, newYork),
+		},
+		"shifts forward the time in the dst gap": {
+			expr:     "30 2 * * *",
+			after:    time.Date(2025, 3, 9, 0, 0, 0, 0, newYork),
+			expected: time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC), // 03:30 EDT
+		},
+		"fires once in the repeated hour": {
+			expr:     "30 1 * * *",
+			after:    time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC).In(newYork), // 01:30 EDT
+			expected: time.Date(2025, 11, 3, 1, 30, 0, 0, newYork),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse(tc.expr)
+			if err != nil {
+				t.Fatalf("failed to parse: %v", err)
+			}
+
+			if next := s.Next(tc.after); !next.Equal(tc.expected) {
+				t.Fatalf("expected %s, got %s", tc.expected, next)
+			}
+		})
+	}
+}
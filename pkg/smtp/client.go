@@ -0,0 +1,326 @@
+package smtp
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	netsmtp "net/smtp"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPoolSize    = 2
+	defaultIdleTimeout = 30 * time.Second
+	defaultDialTimeout = 10 * time.Second
+	defaultSendTimeout = time.Minute
+)
+
+// Security is the way the connection is protected
+type Security string
+
+const (
+	// SecurityStartTLS upgrades the plain connection with the STARTTLS command, port 587 by default
+	SecurityStartTLS Security = "starttls"
+	// SecurityTLS is the implicit TLS from the start of the connection, port 465 by default
+	SecurityTLS Security = "tls"
+	// SecurityNone is the plain connection, it is meant for the local relays only
+	SecurityNone Security = "none"
+)
+
+// AuthMechanism is the SASL mechanism of the authentication
+type AuthMechanism string
+
+const (
+	AuthPlain AuthMechanism = "plain"
+	AuthLogin AuthMechanism = "login"
+)
+
+var ErrClientClosed = errors.New("smtp client is closed")
+
+type From struct {
+	Email string
+	Name  string
+}
+
+// ClientOpts configures the server connection, zero values fall back to defaults
+type ClientOpts struct {
+	Host string
+	// Port defaults to the standard port of the Security
+	Port     int
+	Security Security
+	// Username and Password are optional, the authentication is skipped without the username
+	Username string
+	Password string
+	Auth     AuthMechanism
+	// TLSConfig overrides the default config verifying the Host certificate
+	TLSConfig *tls.Config
+	// PoolSize bounds both the open connections and the concurrent deliveries
+	PoolSize int
+	// IdleTimeout is the time the idle connection is kept open, servers usually drop them after a minute or so
+	IdleTimeout time.Duration
+	// DialTimeout bounds the connection establishment, including the handshake and authentication
+	DialTimeout time.Duration
+	// SendTimeout bounds the delivery of one message over the established connection,
+	// so the unresponsive server does not hold the connection slot forever
+	SendTimeout time.Duration
+}
+
+// Client delivers the messages through the SMTP server, reusing the authenticated connections
+type Client struct {
+	from From
+	opts ClientOpts
+
+	// slots holds a token per connection that may be open
+	slots chan struct{}
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+type conn struct {
+	client   *netsmtp.Client
+	netConn  net.Conn
+	lastUsed time.Time
+}
+
+func NewClient(from From, opts ClientOpts) *Client {
+	if opts.Security == "" {
+		opts.Security = SecurityStartTLS
+	}
+	if opts.Port == 0 {
+		opts.Port = defaultPort(opts.Security)
+	}
+	if opts.Auth == "" {
+		opts.Auth = AuthPlain
+	}
+	if opts.TLSConfig == nil {
+		opts.TLSConfig = &tls.Config{ServerName: opts.Host, MinVersion: tls.VersionTLS12}
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = defaultPoolSize
+	}
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = defaultIdleTimeout
+	}
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = defaultDialTimeout
+	}
+	if opts.SendTimeout <= 0 {
+		opts.SendTimeout = defaultSendTimeout
+	}
+
+	return &Client{
+		from:  from,
+		opts:  opts,
+		slots: make(chan struct{}, opts.PoolSize),
+	}
+}
+
+func defaultPort(security Security) int {
+	switch security {
+	case SecurityTLS:
+		return 465
+	case SecurityNone:
+		return 25
+	default:
+		return 587
+	}
+}
+
+// Send delivers the message, a broken pooled connection is replaced with a new one once
+func (c *Client) Send(message Message) error {
+	data, err := message.compose(c.from, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to compose message: %w", err)
+	}
+
+	c.slots <- struct{}{}
+	defer func() { <-c.slots }()
+
+	cn, reused, err := c.acquire()
+	if err != nil {
+		return err
+	}
+
+	err = c.deliver(cn, message.To, data)
+	var replyErr *textproto.Error
+	if err != nil && reused && !errors.As(err, &replyErr) {
+		// the server may have dropped the idle connection, so it is not the message to blame
+		cn.client.Close()
+		if cn, err = c.dial(); err != nil {
+			return err
+		}
+		err = c.deliver(cn, message.To, data)
+	}
+	if err != nil {
+		// the rejected message leaves the connection usable once the transaction is reset,
+		// the I/O errors (including the timeouts) leave it in an unknown state
+		if errors.As(err, &replyErr) && cn.client.Reset() == nil {
+			c.release(cn)
+		} else {
+			cn.client.Close()
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	c.release(cn)
+	return nil
+}
+
+// Close closes the idle connections, the ones in use are closed once released
+func (c *Client) Close() error {
+	c.mu.Lock()
+	idle := c.idle
+	c.idle, c.closed = nil, true
+	c.mu.Unlock()
+
+	for _, cn := range idle {
+		cn.client.Quit()
+	}
+
+	return nil
+}
+
+// deliver runs the mail transaction within the SendTimeout, the deadline is cleared by release
+func (c *Client) deliver(cn *conn, to string, data []byte) error {
+	if err := cn.netConn.SetDeadline(time.Now().Add(c.opts.SendTimeout)); err != nil {
+		return err
+	}
+
+	if err := cn.client.Mail(c.from.Email); err != nil {
+		return err
+	}
+	if err := cn.client.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := cn.client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// acquire takes the most recently used idle connection, dialing a new one if there is none
+func (c *Client) acquire() (cn *conn, reused bool, err error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, false, ErrClientClosed
+	}
+
+	now := time.Now()
+	var stale []*conn
+	for len(c.idle) > 0 && cn == nil {
+		last := c.idle[len(c.idle)-1]
+		c.idle = c.idle[:len(c.idle)-1]
+		if now.Sub(last.lastUsed) > c.opts.IdleTimeout {
+			stale = append(stale, last)
+		} else {
+			cn = last
+		}
+	}
+	c.mu.Unlock()
+
+	for _, s := range stale {
+		s.client.Close()
+	}
+	if cn != nil {
+		return cn, true, nil
+	}
+
+	cn, err = c.dial()
+	return cn, false, err
+}
+
+func (c *Client) release(cn *conn) {
+	cn.lastUsed = time.Now()
+	// the idle connection is dropped by the IdleTimeout instead
+	cn.netConn.SetDeadline(time.Time{})
+
+	c.mu.Lock()
+	if !c.closed {
+		c.idle = append(c.idle, cn)
+		cn = nil
+	}
+	c.mu.Unlock()
+
+	if cn != nil {
+		cn.client.Quit()
+	}
+}
+
+// dial opens the connection and makes it ready for the transactions: secured and authenticated
+func (c *Client) dial() (*conn, error) {
+	addr := net.JoinHostPort(c.opts.Host, strconv.Itoa(c.opts.Port))
+	dialer := &net.Dialer{Timeout: c.opts.DialTimeout}
+
+	var (
+		netConn net.Conn
+		err     error
+	)
+	if c.opts.Security == SecurityTLS {
+		netConn, err = tls.DialWithDialer(dialer, "tcp", addr, c.opts.TLSConfig)
+	} else {
+		netConn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+
+	// the deadline covers the handshake only, every delivery sets its own one
+	netConn.SetDeadline(time.Now().Add(c.opts.DialTimeout))
+	client, err := c.handshake(netConn)
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	netConn.SetDeadline(time.Time{})
+
+	return &conn{client: client, netConn: netConn}, nil
+}
+
+func (c *Client) handshake(netConn net.Conn) (*netsmtp.Client, error) {
+	client, err := netsmtp.NewClient(netConn, c.opts.Host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to greet server: %w", err)
+	}
+
+	if c.opts.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return nil, errors.New("server does not support STARTTLS")
+		}
+		if err = client.StartTLS(c.opts.TLSConfig); err != nil {
+			return nil, fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+
+	if c.opts.Username == "" {
+		return client, nil
+	}
+
+	var auth netsmtp.Auth
+	switch c.opts.Auth {
+	case AuthLogin:
+		auth = &loginAuth{username: c.opts.Username, password: c.opts.Password, host: c.opts.Host}
+	case AuthPlain:
+		auth = netsmtp.PlainAuth("", c.opts.Username, c.opts.Password, c.opts.Host)
+	default:
+		return nil, fmt.Errorf("unsupported auth mechanism %s", c.opts.Auth)
+	}
+	if err = client.Auth(auth); err != nil {
+		return nil, fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	return client, nil
+}
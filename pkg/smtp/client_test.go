@@ -0,0 +1,458 @@
+package smtp
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	stubUsername = "max"
+	stubPassword = "secret"
+	// stubRejected is refused by the stub server, while stubUnresponsive makes it stop responding after DATA
+	stubRejected     = "rejected@example.com"
+	stubUnresponsive = "unresponsive@example.com"
+)
+
+// stubServer is the minimal in-process SMTP server recording the delivered messages
+type stubServer struct {
+	listener net.Listener
+	tls      *tls.Config
+	// startTLS advertises STARTTLS, otherwise the listener is the implicit TLS one if tls is set
+	startTLS bool
+	// dropAfterMessage closes the connection after every message, like the servers dropping the idle ones
+	dropAfterMessage bool
+
+	mu          sync.Mutex
+	connections int
+	auths       []string
+	messages    []string
+}
+
+func newStubServer(t *testing.T, security Security, dropAfterMessage bool) (*stubServer, *tls.Config) {
+	serverTLS, clientTLS := newTestTLS(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	s := &stubServer{listener: listener, dropAfterMessage: dropAfterMessage}
+	switch security {
+	case SecurityTLS:
+		s.listener = tls.NewListener(listener, serverTLS)
+		s.tls = serverTLS
+	case SecurityStartTLS:
+		s.tls, s.startTLS = serverTLS, true
+	}
+	t.Cleanup(func() { s.listener.Close() })
+
+	go s.serve()
+	return s, clientTLS
+}
+
+func (s *stubServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *stubServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.connections++
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+func (s *stubServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	secured := !s.startTLS && s.tls != nil
+	tp.PrintfLine("220 stub ESMTP")
+
+	var recipient string
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		command, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			if s.startTLS && !secured {
+				tp.PrintfLine("250-stub")
+				tp.PrintfLine("250 STARTTLS")
+			} else {
+				tp.PrintfLine("250-stub")
+				tp.PrintfLine("250 AUTH PLAIN LOGIN")
+			}
+		case "STARTTLS":
+			tp.PrintfLine("220 ready")
+			tlsConn := tls.Server(conn, s.tls)
+			if err = tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn, secured = tlsConn, true
+			tp = textproto.NewConn(conn)
+		case "AUTH":
+			mechanism, username, password := s.readAuth(tp, arg)
+			if username != stubUsername || password != stubPassword {
+				tp.PrintfLine("535 authentication failed")
+				continue
+			}
+			s.mu.Lock()
+			s.auths = append(s.auths, mechanism)
+			s.mu.Unlock()
+			tp.PrintfLine("235 authenticated")
+		case "RCPT":
+			if strings.Contains(arg, stubRejected) {
+				tp.PrintfLine("550 mailbox unavailable")
+				continue
+			}
+			recipient = arg
+			tp.PrintfLine("250 ok")
+		case "MAIL", "RSET", "NOOP":
+			tp.PrintfLine("250 ok")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			if strings.Contains(recipient, stubUnresponsive) {
+				// waits for the client to give up
+				io.Copy(io.Discard, conn)
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			tp.PrintfLine("250 queued")
+			if s.dropAfterMessage {
+				return
+			}
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func (s *stubServer) readAuth(tp *textproto.Conn, arg string) (mechanism, username, password string) {
+	mechanism, initial, _ := strings.Cut(arg, " ")
+	switch strings.ToUpper(mechanism) {
+	case "PLAIN":
+		decoded, _ := base64.StdEncoding.DecodeString(initial)
+		fields := strings.Split(string(decoded), "\x00")
+		if len(fields) == 3 {
+			username, password = fields[1], fields[2]
+		}
+	case "LOGIN":
+		readLine := func(prompt string) string {
+			tp.PrintfLine("334 %s", base64.StdEncoding.EncodeToString([]byte(prompt)))
+			line, _ := tp.ReadLine()
+			decoded, _ := base64.StdEncoding.DecodeString(line)
+			return string(decoded)
+		}
+		username, password = readLine("Username:"), readLine("Password:")
+	}
+
+	return strings.ToLower(mechanism), username, password
+}
+
+func (s *stubServer) snapshot() (connections int, auths, messages []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.connections, append([]string(nil), s.auths...), append([]string(nil), s.messages...)
+}
+
+// newTestTLS generates the self-signed certificate of 127.0.0.1, returning the server and the client configs
+func newTestTLS(t *testing.T) (server, client *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: %v", err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+		&tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+}
+
+func TestClient_Send(t *testing.T) {
+	message := Message{
+		To:       "ann@gmail.com",
+		Subject:  "Погода",
+		HTMLPart: "<p>Sunny</p>",
+		TextPart: "Sunny",
+		Headers:  map[string]string{"List-Unsubscribe-Post": "List-Unsubscribe=One-Click"},
+	}
+
+	testCases := map[string]struct {
+		security         Security
+		auth             AuthMechanism
+		username         string
+		dropAfterMessage bool
+		sends            int
+		expectErr        bool
+		connections      int
+		auths            []string
+	}{
+		"must send over STARTTLS with PLAIN auth reusing the connection": {
+			security:    SecurityStartTLS,
+			auth:        AuthPlain,
+			username:    stubUsername,
+			sends:       3,
+			connections: 1,
+			auths:       []string{"plain"},
+		},
+		"must send over implicit TLS with LOGIN auth": {
+			security:    SecurityTLS,
+			auth:        AuthLogin,
+			username:    stubUsername,
+			sends:       2,
+			connections: 1,
+			auths:       []string{"login"},
+		},
+		"must send without auth over plain connection": {
+			security:    SecurityNone,
+			sends:       1,
+			connections: 1,
+		},
+		"must reconnect if the pooled connection is dropped": {
+			security:         SecurityStartTLS,
+			auth:             AuthPlain,
+			username:         stubUsername,
+			dropAfterMessage: true,
+			sends:            2,
+			connections:      2,
+			auths:            []string{"plain", "plain"},
+		},
+		"must fail with invalid credentials": {
+			security:    SecurityStartTLS,
+			auth:        AuthLogin,
+			username:    "ann",
+			sends:       1,
+			expectErr:   true,
+			connections: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server, clientTLS := newStubServer(t, tc.security, tc.dropAfterMessage)
+			client := NewClient(From{Email: "weather@example.com", Name: "Weather App"}, ClientOpts{
+				Host:      "127.0.0.1",
+				Port:      server.port(),
+				Security:  tc.security,
+				Username:  tc.username,
+				Password:  stubPassword,
+				Auth:      tc.auth,
+				TLSConfig: clientTLS,
+			})
+			defer client.Close()
+
+			for i := 0; i < tc.sends; i++ {
+				err := client.Send(message)
+				if tc.expectErr != (err != nil) {
+					t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+				}
+				if tc.dropAfterMessage {
+					// lets the server drop the connection before the next message
+					time.Sleep(50 * time.Millisecond)
+				}
+			}
+
+			connections, auths, messages := server.snapshot()
+			if connections != tc.connections {
+				t.Fatalf("expected %d connections, got %d", tc.connections, connections)
+			}
+			if strings.Join(auths, ",") != strings.Join(tc.auths, ",") {
+				t.Fatalf("expected auths %v, got %v", tc.auths, auths)
+			}
+			if tc.expectErr {
+				return
+			}
+			if len(messages) != tc.sends {
+				t.Fatalf("expected %d messages, got %d", tc.sends, len(messages))
+			}
+		})
+	}
+}
+
+func TestClient_SendFailures(t *testing.T) {
+	testCases := map[string]struct {
+		to          string
+		connections int
+	}{
+		"must keep the connection after the rejected recipient": {
+			to:          stubRejected,
+			connections: 1,
+		},
+		"must give up on the unresponsive server and replace the connection": {
+			to:          stubUnresponsive,
+			connections: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			server, _ := newStubServer(t, SecurityNone, false)
+			client := NewClient(From{Email: "weather@example.com"}, ClientOpts{
+				Host:        "127.0.0.1",
+				Port:        server.port(),
+				Security:    SecurityNone,
+				PoolSize:    1,
+				SendTimeout: 200 * time.Millisecond,
+			})
+			defer client.Close()
+
+			done := make(chan error)
+			go func() { done <- client.Send(Message{To: tc.to, Subject: "Weather", HTMLPart: "<p>Sunny</p>"}) }()
+			select {
+			case err := <-done:
+				if err == nil {
+					t.Fatal("expected the send to fail")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("expected the send to time out")
+			}
+
+			// the only pool slot is released, so the next message is delivered
+			if err := client.Send(Message{To: "ann@gmail.com", Subject: "Weather", HTMLPart: "<p>Sunny</p>"}); err != nil {
+				t.Fatalf("failed to send: %v", err)
+			}
+
+			connections, _, messages := server.snapshot()
+			if connections != tc.connections {
+				t.Fatalf("expected %d connections, got %d", tc.connections, connections)
+			}
+			if len(messages) != 1 {
+				t.Fatalf("expected 1 message, got %d", len(messages))
+			}
+		})
+	}
+}
+
+func TestMessage_Compose(t *testing.T) {
+	message := Message{
+		To:       "ann@gmail.com",
+		Subject:  "Погода",
+		HTMLPart: "<p>Sunny</p>",
+		TextPart: "Sunny",
+		Headers:  map[string]string{"List-Unsubscribe": "<https://example.com/u>\r\nBcc: evil@example.com"},
+	}
+
+	data, err := message.compose(From{Email: "weather@example.com", Name: "Weather App"}, time.Now())
+	if err != nil {
+		t.Fatalf("failed to compose: %v", err)
+	}
+
+	tp := textproto.NewReader(bufio.NewReader(strings.NewReader(string(data))))
+	header, err := tp.ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("failed to read header: %v", err)
+	}
+
+	if header.Get("Bcc") != "" {
+		t.Fatalf("expected the header value line breaks to be stripped, got Bcc %s", header.Get("Bcc"))
+	}
+	if !strings.HasPrefix(header.Get("Content-Type"), "multipart/alternative;") {
+		t.Fatalf("expected multipart/alternative, got %s", header.Get("Content-Type"))
+	}
+	if header.Get("Subject") != "=?utf-8?q?=D0=9F=D0=BE=D0=B3=D0=BE=D0=B4=D0=B0?=" {
+		t.Fatalf("expected encoded subject, got %s", header.Get("Subject"))
+	}
+
+	body := string(data)
+	textAt, htmlAt := strings.Index(body, "text/plain"), strings.Index(body, "text/html")
+	if textAt < 0 || htmlAt < 0 || textAt > htmlAt {
+		t.Fatalf("expected the text part followed by the html one, got %s", body)
+	}
+}
+
+func TestMessage_ComposeInlineImages(t *testing.T) {
+	message := Message{
+		To:           "ann@gmail.com",
+		Subject:      "Weather",
+		HTMLPart:     `<img src="cid:rain.png">`,
+		TextPart:     "Rain",
+		InlineImages: []InlineImage{{ContentID: "rain.png", ContentType: "image/png", Filename: "rain.png", Data: []byte("png")}},
+	}
+
+	data, err := message.compose(From{Email: "weather@example.com"}, time.Now())
+	if err != nil {
+		t.Fatalf("failed to compose: %v", err)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("failed to read message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/related" || params["type"] != "multipart/alternative" {
+		t.Fatalf("expected multipart/related of multipart/alternative, got %s", msg.Header.Get("Content-Type"))
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	content, err := parts.NextPart()
+	if err != nil || !strings.HasPrefix(content.Header.Get("Content-Type"), "multipart/alternative;") {
+		t.Fatalf("expected the alternative part first, got %v", err)
+	}
+	image, err := parts.NextRawPart()
+	if err != nil {
+		t.Fatalf("failed to read the image part: %v", err)
+	}
+	if image.Header.Get("Content-Id") != "<rain.png>" || !strings.HasPrefix(image.Header.Get("Content-Disposition"), "inline") {
+		t.Fatalf("expected the inline image, got %v", image.Header)
+	}
+	encoded, _ := io.ReadAll(image)
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded))); err != nil || string(decoded) != "png" {
+		t.Fatalf("expected the base64 encoded image, got %s", encoded)
+	}
+}
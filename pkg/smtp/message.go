@@ -0,0 +1,209 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	To       string
+	Subject  string
+	HTMLPart string
+	// TextPart is optional, the message is multipart/alternative along with it
+	TextPart string
+	// Headers are added to the message as is, e.g. List-Unsubscribe
+	Headers map[string]string
+	// InlineImages make the message multipart/related, the HTMLPart refers to them by their "cid:" URLs
+	InlineImages []InlineImage
+}
+
+type InlineImage struct {
+	ContentID   string
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// compose renders the RFC 5322 message, the text parts are quoted-printable encoded and the images are base64 encoded
+func (m Message) compose(from From, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", (&mail.Address{Name: from.Name, Address: from.Email}).String())
+	header.Set("To", (&mail.Address{Address: m.To}).String())
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header.Set("Date", now.Format(time.RFC1123Z))
+	header.Set("Message-ID", messageId(from.Email))
+	header.Set("MIME-Version", "1.0")
+	for name, value := range m.Headers {
+		header.Set(name, value)
+	}
+
+	contentHeader, content, err := m.composeContent()
+	if err != nil {
+		return nil, err
+	}
+	if len(m.InlineImages) > 0 {
+		if contentHeader, content, err = m.relate(contentHeader, content); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, values := range contentHeader {
+		header[name] = values
+	}
+	writeHeader(&buf, header)
+	buf.Write(content)
+
+	return buf.Bytes(), nil
+}
+
+// composeContent renders the HTML part, along with its plain-text alternative if there is one
+func (m Message) composeContent() (textproto.MIMEHeader, []byte, error) {
+	var body bytes.Buffer
+	if m.TextPart == "" {
+		header := textproto.MIMEHeader{
+			"Content-Type":              {`text/html; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		}
+
+		return header, body.Bytes(), writeQuotedPrintable(&body, m.HTMLPart)
+	}
+
+	parts := multipart.NewWriter(&body)
+	// the preferred part goes last
+	for _, part := range []struct{ contentType, content string }{
+		{contentType: "text/plain", content: m.TextPart},
+		{contentType: "text/html", content: m.HTMLPart},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + `; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = writeQuotedPrintable(w, part.content); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	header := textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf(`multipart/alternative; boundary="%s"`, parts.Boundary())},
+	}
+
+	return header, body.Bytes(), nil
+}
+
+// relate wraps the content into multipart/related along with the inline images, so the HTML part can refer to them
+func (m Message) relate(contentHeader textproto.MIMEHeader, content []byte) (textproto.MIMEHeader, []byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	w, err := parts.CreatePart(contentHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err = w.Write(content); err != nil {
+		return nil, nil, err
+	}
+
+	for _, image := range m.InlineImages {
+		w, err = parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(image.ContentType, map[string]string{"name": image.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Id":                {"<" + image.ContentID + ">"},
+			"Content-Disposition":       {mime.FormatMediaType("inline", map[string]string{"filename": image.Filename})},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = writeBase64(w, image.Data); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err = parts.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	header := textproto.MIMEHeader{
+		"Content-Type": {fmt.Sprintf(`multipart/related; type="%s"; boundary="%s"`, mediaType(contentHeader), parts.Boundary())},
+	}
+
+	return header, body.Bytes(), nil
+}
+
+// writeHeader writes the header in the stable order, the values are stripped of the line breaks
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	replacer := strings.NewReplacer("\r", "", "\n", "")
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(buf, "%s: %s\r\n", name, replacer.Replace(value))
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+
+	return qp.Close()
+}
+
+// writeBase64 writes the data base64 encoded, in lines of 76 characters at most
+func writeBase64(w io.Writer, data []byte) error {
+	const lineLength = 76
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(lineLength, len(encoded))
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+
+	return nil
+}
+
+// mediaType returns the media type of the part without the parameters, e.g. multipart/alternative
+func mediaType(header textproto.MIMEHeader) string {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mediaType
+}
+
+func messageId(fromEmail string) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	domain := "localhost"
+	if at := strings.LastIndex(fromEmail, "@"); at >= 0 {
+		domain = fromEmail[at+1:]
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
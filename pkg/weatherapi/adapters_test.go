@@ -0,0 +1,136 @@
+package weatherapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenMeteoClient_GetForecast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			if r.URL.Query().Get("name") != "Kyiv" {
+				_, _ = w.Write([]byte(`{}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[{"name":"Kyiv","latitude":50.45,"longitude":30.52}]}`))
+		case "/forecast":
+			_, _ = w.Write([]byte(`{
+				"utc_offset_seconds": 10800,
+				"current": {
+					"temperature_2m": 18.5, "relative_humidity_2m": 55, "weather_code": 2, "apparent_temperature": 17.9,
+					"wind_speed_10m": 14.4, "wind_direction_10m": 200, "pressure_msl": 1012, "visibility": 24140, "is_day": 1
+				},
+				"daily": {
+					"time": ["2025-06-01"],
+					"temperature_2m_max": [24.1], "temperature_2m_min": [14.3],
+					"precipitation_sum": [1.2], "precipitation_probability_max": [65],
+					"sunrise": ["2025-06-01T04:48"], "sunset": ["2025-06-01T21:02"],
+					"weather_code": [61]
+				},
+				"hourly": {
+					"time": ["2025-06-01T00:00", "2025-06-01T01:00"],
+					"temperature_2m": [15.0, 14.5], "relative_humidity_2m": [80, 90],
+					"precipitation": [0, 0.4], "precipitation_probability": [10, 40],
+					"weather_code": [0, 61]
+				}
+			}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := NewOpenMeteoClient(ClientOpts{MaxRetries: -1})
+	client.geocodingUrl, client.forecastUrl = srv.URL, srv.URL
+
+	forecast, err := client.GetForecast(context.Background(), "kyiv", 1)
+	if !errors.Is(err, ErrCityNotFound) {
+		t.Fatalf("expected city not found for unknown name, got %v", err)
+	}
+
+	forecast, err = client.GetForecast(context.Background(), "Kyiv", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if forecast.CurrentWeather.Condition.Text != "Partly cloudy" || forecast.CurrentWeather.Humidity != 55 {
+		t.Fatalf("unexpected current weather %+v", forecast.CurrentWeather)
+	}
+	if current := forecast.CurrentWeather; current.Condition.Code != 1003 || current.WindDirection != "SSW" ||
+		current.WindMph != 8.9 || current.VisibilityKm != 24.1 || current.FeelsLikeF != 64.2 || current.PressureIn != 29.88 {
+		t.Fatalf("unexpected current conditions %+v", current)
+	}
+
+	if len(forecast.Forecast.Days) != 1 {
+		t.Fatalf("expected 1 day, got %d", len(forecast.Forecast.Days))
+	}
+	day := forecast.Forecast.Days[0]
+	if day.Day.MaxTemperature != 24.1 || day.Day.ChanceOfRain != 65 || day.Day.AvgHumidity != 85 {
+		t.Fatalf("unexpected day %+v", day.Day)
+	}
+	if day.Day.MaxTemperatureF != 75.4 || day.Hours[0].TemperatureF != 59 {
+		t.Fatalf("expected the derived fahrenheit values, got %+v", day)
+	}
+	if day.Astro.Sunrise != "04:48 AM" || day.Astro.Sunset != "09:02 PM" {
+		t.Fatalf("unexpected astro %+v", day.Astro)
+	}
+	if len(day.Hours) != 2 || day.Hours[1].Time != "2025-06-01 01:00" || day.Hours[1].Condition.Text != "Slight rain" {
+		t.Fatalf("unexpected hours %+v", day.Hours)
+	}
+}
+
+func TestOpenWeatherMapClient_GetForecast(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "London" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		// 2025-06-01 09:00, 12:00 and 2025-06-02 00:00 UTC (London is UTC+1 in June)
+		_, _ = w.Write([]byte(`{
+			"list": [
+				{"dt": 1748768400, "main": {"temp": 15, "temp_min": 14, "temp_max": 16, "humidity": 70}, "weather": [{"description": "light rain"}], "pop": 0.4, "rain": {"3h": 0.5}},
+				{"dt": 1748779200, "main": {"temp": 19, "temp_min": 18, "temp_max": 20, "humidity": 50}, "weather": [{"description": "clear sky"}], "pop": 0.1},
+				{"dt": 1748822400, "main": {"temp": 12, "temp_min": 11, "temp_max": 13, "humidity": 90}, "weather": [{"description": "mist"}], "pop": 0}
+			],
+			"city": {"name": "London", "timezone": 3600, "sunrise": 1748749500, "sunset": 1748808900}
+		}`))
+	}))
+	defer srv.Close()
+
+	client := NewOpenWeatherMapClient("key", ClientOpts{MaxRetries: -1})
+	client.baseUrl = srv.URL
+
+	if _, err := client.GetForecast(context.Background(), "Nowhere", 1); !errors.Is(err, ErrCityNotFound) {
+		t.Fatalf("expected city not found, got %v", err)
+	}
+
+	forecast, err := client.GetForecast(context.Background(), "London", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(forecast.Forecast.Days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(forecast.Forecast.Days))
+	}
+
+	first := forecast.Forecast.Days[0]
+	if first.Date != "2025-06-01" || len(first.Hours) != 2 {
+		t.Fatalf("unexpected first day %+v", first)
+	}
+	if first.Day.MinTemperature != 14 || first.Day.MaxTemperature != 20 ||
+		first.Day.ChanceOfRain != 40 || first.Day.AvgTemperature != 17 || first.Day.AvgHumidity != 60 {
+		t.Fatalf("unexpected day aggregation %+v", first.Day)
+	}
+	if first.Day.MinTemperatureF != 57.2 || first.Day.MaxTemperatureF != 68 {
+		t.Fatalf("expected the derived fahrenheit values, got %+v", first.Day)
+	}
+	if first.Hours[0].Time != "2025-06-01 10:00" || first.Hours[0].Condition.Text != "Light rain" {
+		t.Fatalf("unexpected hour %+v", first.Hours[0])
+	}
+	if first.Astro.Sunrise == "" || forecast.Forecast.Days[1].Astro.Sunrise != "" {
+		t.Fatalf("expected astronomy for the first day only")
+	}
+}
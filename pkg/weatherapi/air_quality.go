@@ -0,0 +1,17 @@
+package weatherapi
+
+import "context"
+
+type airQualityCtxKey struct{}
+
+// WithAirQuality requests the air quality of the current conditions (see CurrentWeather.AirQuality),
+// the providers not supporting it (Open-Meteo, OpenWeatherMap) leave it empty
+func WithAirQuality(ctx context.Context) context.Context {
+	return context.WithValue(ctx, airQualityCtxKey{}, true)
+}
+
+// AirQualityFrom reports whether the air quality is requested
+func AirQualityFrom(ctx context.Context) bool {
+	requested, _ := ctx.Value(airQualityCtxKey{}).(bool)
+	return requested
+}
@@ -0,0 +1,220 @@
+package weatherapi
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultCacheTTL         = 10 * time.Minute
+	defaultCacheNegativeTTL = time.Minute
+	defaultCacheMaxEntries  = 1000
+)
+
+type CacheOpts struct {
+	TTL time.Duration
+	// NegativeTTL is applied to ErrCityNotFound results
+	NegativeTTL time.Duration
+	// MaxEntries bounds the cache size, the least recently used entries are evicted first
+	MaxEntries int
+}
+
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+}
+
+// CachedProvider is a process-wide caching decorator, concurrent lookups
+// of the same key are coalesced into a single upstream request
+type CachedProvider struct {
+	provider    WeatherProvider
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxEntries  int
+	now         func() time.Time
+
+	flight singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front is the most recently used
+
+	hits, misses, evictions atomic.Uint64
+}
+
+type cacheEntry struct {
+	key       string
+	value     any
+	err       error
+	expiresAt time.Time
+}
+
+func NewCachedProvider(provider WeatherProvider, opts CacheOpts) *CachedProvider {
+	if opts.TTL <= 0 {
+		opts.TTL = defaultCacheTTL
+	}
+	if opts.NegativeTTL <= 0 {
+		opts.NegativeTTL = defaultCacheNegativeTTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultCacheMaxEntries
+	}
+
+	return &CachedProvider{
+		provider:    provider,
+		ttl:         opts.TTL,
+		negativeTTL: opts.NegativeTTL,
+		maxEntries:  opts.MaxEntries,
+		now:         time.Now,
+		entries:     make(map[string]*list.Element),
+		lru:         list.New(),
+	}
+}
+
+func (c *CachedProvider) GetCurrentWeather(ctx context.Context, city string) (*WeatherCurrentResponse, error) {
+	return cached(ctx, c, optionsKey(ctx, "current:"+normalizeCity(city)), func(ctx context.Context) (*WeatherCurrentResponse, error) {
+		return c.provider.GetCurrentWeather(ctx, city)
+	})
+}
+
+func (c *CachedProvider) GetForecast(ctx context.Context, city string, days int) (*WeatherForecastResponse, error) {
+	return cached(ctx, c, optionsKey(ctx, fmt.Sprintf("forecast:%d:%s", days, normalizeCity(city))), func(ctx context.Context) (*WeatherForecastResponse, error) {
+		return c.provider.GetForecast(ctx, city, days)
+	})
+}
+
+func (c *CachedProvider) ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error) {
+	return cached(ctx, c, "location:"+normalizeCity(query.String()), func(ctx context.Context) (*Location, error) {
+		return c.provider.ResolveLocation(ctx, query)
+	})
+}
+
+func (c *CachedProvider) SearchLocations(ctx context.Context, query string, limit int) ([]Location, error) {
+	return cached(ctx, c, fmt.Sprintf("search:%d:%s", limit, normalizeCity(query)), func(ctx context.Context) ([]Location, error) {
+		return c.provider.SearchLocations(ctx, query, limit)
+	})
+}
+
+func (c *CachedProvider) Stats() CacheStats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   entries,
+	}
+}
+
+// optionsKey separates the entries of the responses requested with the different options,
+// e.g. the localized ones differ in the condition texts
+func optionsKey(ctx context.Context, key string) string {
+	if AirQualityFrom(ctx) {
+		key = "aqi:" + key
+	}
+	if lang := LanguageFrom(ctx); lang != "" {
+		key = lang + ":" + key
+	}
+
+	return key
+}
+
+func cached[T any](ctx context.Context, c *CachedProvider, key string, fetch func(context.Context) (T, error)) (T, error) {
+	var zero T
+
+	if entry, ok := c.get(key); ok {
+		c.hits.Add(1)
+		if entry.err != nil {
+			return zero, entry.err
+		}
+		return entry.value.(T), nil
+	}
+	c.misses.Add(1)
+
+	// the shared fetch must not be cancelled by the caller that happened to start it,
+	// every caller still stops waiting on its own ctx
+	flightCtx := context.WithoutCancel(ctx)
+	results := c.flight.DoChan(key, func() (any, error) {
+		value, err := fetch(flightCtx)
+		switch {
+		case err == nil:
+			c.set(key, value, nil, c.ttl)
+		case errors.Is(err, ErrCityNotFound):
+			c.set(key, nil, err, c.negativeTTL)
+		}
+
+		return value, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case result := <-results:
+		if result.Err != nil {
+			return zero, result.Err
+		}
+		return result.Val.(T), nil
+	}
+}
+
+func (c *CachedProvider) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	return entry, true
+}
+
+func (c *CachedProvider) set(key string, value any, err error, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{
+		key:       key,
+		value:     value,
+		err:       err,
+		expiresAt: c.now().Add(ttl),
+	}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions.Add(1)
+	}
+}
+
+func normalizeCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
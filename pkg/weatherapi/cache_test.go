@@ -0,0 +1,171 @@
+package weatherapi
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingProvider struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (p *countingProvider) GetCurrentWeather(_ context.Context, city string) (*WeatherCurrentResponse, error) {
+	p.calls.Add(1)
+	if p.release != nil {
+		<-p.release
+	}
+	if city == "Nowhere" {
+		return nil, ErrCityNotFound
+	}
+
+	return &WeatherCurrentResponse{Location: Location{Name: city}}, nil
+}
+
+func (p *countingProvider) GetForecast(ctx context.Context, city string, _ int) (*WeatherForecastResponse, error) {
+	current, err := p.GetCurrentWeather(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WeatherForecastResponse{Location: current.Location}, nil
+}
+
+func (p *countingProvider) ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error) {
+	current, err := p.GetCurrentWeather(ctx, query.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &current.Location, nil
+}
+
+func (p *countingProvider) SearchLocations(ctx context.Context, query string, _ int) ([]Location, error) {
+	location, err := p.ResolveLocation(ctx, LocationQuery{Name: query})
+	if err != nil {
+		return nil, err
+	}
+
+	return []Location{*location}, nil
+}
+
+func TestCachedProvider_TTL(t *testing.T) {
+	var (
+		upstream = &countingProvider{}
+		cache    = NewCachedProvider(upstream, CacheOpts{TTL: time.Minute, NegativeTTL: time.Second})
+		now      = time.Now()
+		ctx      = context.Background()
+	)
+	cache.now = func() time.Time { return now }
+
+	for _, city := range []string{"Kyiv", "kyiv ", "KYIV"} {
+		if _, err := cache.GetCurrentWeather(ctx, city); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := cache.GetCurrentWeather(ctx, "Nowhere"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, err := cache.GetCurrentWeather(ctx, "Nowhere"); err == nil {
+		t.Fatal("expected cached error, got nil")
+	}
+	if calls := upstream.calls.Load(); calls != 2 {
+		t.Fatalf("expected 2 upstream calls, got %d", calls)
+	}
+
+	// negative entry expires sooner than the positive one
+	now = now.Add(2 * time.Second)
+	_, _ = cache.GetCurrentWeather(ctx, "Nowhere")
+	_, _ = cache.GetCurrentWeather(ctx, "Kyiv")
+	if calls := upstream.calls.Load(); calls != 3 {
+		t.Fatalf("expected 3 upstream calls, got %d", calls)
+	}
+
+	// forecast is cached independently of the current weather
+	_, _ = cache.GetForecast(ctx, "Kyiv", 1)
+	_, _ = cache.GetForecast(ctx, "Kyiv", 1)
+	if calls := upstream.calls.Load(); calls != 4 {
+		t.Fatalf("expected 4 upstream calls, got %d", calls)
+	}
+
+	// localized responses are cached apart from the default ones
+	_, _ = cache.GetCurrentWeather(WithLanguage(ctx, "uk"), "Kyiv")
+	_, _ = cache.GetCurrentWeather(WithLanguage(ctx, "uk"), "Kyiv")
+	if calls := upstream.calls.Load(); calls != 5 {
+		t.Fatalf("expected 5 upstream calls, got %d", calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 6 || stats.Misses != 5 || stats.Entries != 4 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestCachedProvider_Eviction(t *testing.T) {
+	upstream := &countingProvider{}
+	cache := NewCachedProvider(upstream, CacheOpts{MaxEntries: 2})
+	ctx := context.Background()
+
+	_, _ = cache.GetCurrentWeather(ctx, "Kyiv")
+	_, _ = cache.GetCurrentWeather(ctx, "Lviv")
+	_, _ = cache.GetCurrentWeather(ctx, "Kyiv") // Lviv becomes the least recently used
+	_, _ = cache.GetCurrentWeather(ctx, "Odesa")
+	_, _ = cache.GetCurrentWeather(ctx, "Kyiv")
+
+	if calls := upstream.calls.Load(); calls != 3 {
+		t.Fatalf("expected 3 upstream calls, got %d", calls)
+	}
+	if stats := cache.Stats(); stats.Evictions != 1 || stats.Entries != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestCachedProvider_Coalescing(t *testing.T) {
+	upstream := &countingProvider{release: make(chan struct{})}
+	cache := NewCachedProvider(upstream, CacheOpts{})
+
+	const callers = 10
+	wg := new(sync.WaitGroup)
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := cache.GetCurrentWeather(context.Background(), "Kyiv"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	// waiting for the first caller to reach the upstream, the rest join the flight
+	for upstream.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(upstream.release)
+	wg.Wait()
+
+	if calls := upstream.calls.Load(); calls != 1 {
+		t.Fatalf("expected 1 upstream call, got %d", calls)
+	}
+}
+
+func TestCachedProvider_CallerCancellation(t *testing.T) {
+	upstream := &countingProvider{release: make(chan struct{})}
+	cache := NewCachedProvider(upstream, CacheOpts{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := cache.GetCurrentWeather(ctx, "Kyiv"); err == nil {
+		t.Fatal("expected ctx error, got nil")
+	}
+
+	// the fetch started by the cancelled caller still completes and fills the cache
+	close(upstream.release)
+	if _, err := cache.GetCurrentWeather(context.Background(), "Kyiv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -1,56 +1,161 @@
 package weatherapi
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
-	"strings"
+	"net/url"
+	"strconv"
 )
 
-const baseUrl = "https://api.weatherapi.com/v1"
+const (
+	baseUrl = "https://api.weatherapi.com/v1"
 
+	// MaxForecastDays is the longest forecast the provider is able to return
+	MaxForecastDays = 14
+)
+
+// WeatherProvider accepts either a city name or coordinates (see CoordinatesQuery) as the city
 type WeatherProvider interface {
-	GetCurrentWeather(city string) (*WeatherCurrentResponse, error)
+	GetCurrentWeather(ctx context.Context, city string) (*WeatherCurrentResponse, error)
+	GetForecast(ctx context.Context, city string, days int) (*WeatherForecastResponse, error)
+	// ResolveLocation returns the canonical location, including its id and timezone
+	ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error)
+	// SearchLocations returns up to limit ranked candidates for the partial name, no matches is not an error
+	SearchLocations(ctx context.Context, query string, limit int) ([]Location, error)
 }
 
 type Client struct {
-	apiKey string
+	apiKey    string
+	baseUrl   string
+	transport transport
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{apiKey}
+func NewClient(apiKey string, opts ClientOpts) *Client {
+	return &Client{
+		apiKey:    apiKey,
+		baseUrl:   baseUrl,
+		transport: newTransport(opts),
+	}
 }
 
-func (c *Client) GetCurrentWeather(city string) (*WeatherCurrentResponse, error) {
-	url := baseUrl + "/current.json?key=" + c.apiKey + "&q=" + city
-	resp, err := http.Get(url)
-	if err != nil {
+func (c *Client) GetCurrentWeather(ctx context.Context, city string) (*WeatherCurrentResponse, error) {
+	query := url.Values{"q": {city}}
+	if AirQualityFrom(ctx) {
+		query.Set("aqi", "yes")
+	}
+
+	var weatherResponse WeatherCurrentResponse
+	if err := c.get(ctx, "/current.json", query, &weatherResponse); err != nil {
 		return nil, fmt.Errorf("could not get current weather: %w", err)
 	}
 
-	defer func() {
-		_ = resp.Body.Close()
-	}()
+	if !matchesCity(weatherResponse.Location, city) {
+		return nil, ErrCityNotFound
+	}
+
+	return &weatherResponse, nil
+}
+
+func (c *Client) GetForecast(ctx context.Context, city string, days int) (*WeatherForecastResponse, error) {
+	if days < 1 || days > MaxForecastDays {
+		return nil, fmt.Errorf("invalid forecast days number: %d", days)
+	}
+
+	query := url.Values{
+		"q":    {city},
+		"days": {strconv.Itoa(days)},
+	}
+	if AirQualityFrom(ctx) {
+		query.Set("aqi", "yes")
+	}
+
+	var forecastResponse WeatherForecastResponse
+	if err := c.get(ctx, "/forecast.json", query, &forecastResponse); err != nil {
+		return nil, fmt.Errorf("could not get weather forecast: %w", err)
+	}
+
+	if !matchesCity(forecastResponse.Location, city) {
+		return nil, ErrCityNotFound
+	}
 
-	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
-		case http.StatusBadRequest:
-			// assuming 400 means city not found
+	return &forecastResponse, nil
+}
+
+func (c *Client) ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error) {
+	var id int64
+	if query.Id != "" {
+		var err error
+		if id, err = parseLocationId(ProviderWeatherAPI, query.Id); err != nil {
+			return nil, err
+		}
+	} else {
+		var candidates []weatherApiSearchResult
+		if err := c.get(ctx, "/search.json", url.Values{"q": {query.String()}}, &candidates); err != nil {
+			return nil, fmt.Errorf("could not search locations: %w", err)
+		} else if len(candidates) == 0 {
 			return nil, ErrCityNotFound
-		default:
-			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		// exact name wins, otherwise the provider's best guess (e.g. "Kiev" -> "Kyiv") is taken
+		id = candidates[0].Id
+		for _, candidate := range candidates {
+			if matchesCity(Location{Name: candidate.Name}, query.Name) {
+				id = candidate.Id
+				break
+			}
 		}
 	}
 
-	var weatherResponse WeatherCurrentResponse
-	if err = json.NewDecoder(resp.Body).Decode(&weatherResponse); err != nil {
-		return nil, err
+	// search results have no timezone, so the canonical location is taken from the weather endpoint
+	var response WeatherCurrentResponse
+	if err := c.get(ctx, "/current.json", url.Values{"q": {"id:" + strconv.FormatInt(id, 10)}}, &response); err != nil {
+		return nil, fmt.Errorf("could not get location: %w", err)
 	}
 
-	// weather api can return a different city name than requested (especially when auto-completing something)
-	if strings.ToLower(weatherResponse.Location.Name) != strings.ToLower(city) {
-		return nil, ErrCityNotFound
+	location := response.Location
+	location.Id = locationId(ProviderWeatherAPI, id)
+
+	return &location, nil
+}
+
+func (c *Client) SearchLocations(ctx context.Context, query string, limit int) ([]Location, error) {
+	var results []weatherApiSearchResult
+	if err := c.get(ctx, "/search.json", url.Values{"q": {query}}, &results); err != nil {
+		return nil, fmt.Errorf("could not search locations: %w", err)
 	}
 
-	return &weatherResponse, nil
+	locations := make([]Location, len(results))
+	for i, result := range results {
+		locations[i] = Location{
+			Id:        locationId(ProviderWeatherAPI, result.Id),
+			Name:      result.Name,
+			Region:    result.Region,
+			Country:   result.Country,
+			Latitude:  result.Latitude,
+			Longitude: result.Longitude,
+		}
+	}
+
+	return rankLocations(query, locations, limit), nil
+}
+
+type weatherApiSearchResult struct {
+	Id        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// get performs a GET request to the given endpoint and decodes a successful response body into dst
+func (c *Client) get(ctx context.Context, endpoint string, query url.Values, dst any) error {
+	query.Set("key", c.apiKey)
+	if lang := LanguageFrom(ctx); lang != "" {
+		query.Set("lang", lang)
+	}
+
+	// assuming 400 means city not found
+	return c.transport.getJSON(ctx, c.baseUrl+endpoint+"?"+query.Encode(), http.StatusBadRequest, dst)
 }
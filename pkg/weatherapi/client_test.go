@@ -0,0 +1,281 @@
+package weatherapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(handler http.HandlerFunc) (*Client, func()) {
+	srv := httptest.NewServer(handler)
+	client := NewClient("key", ClientOpts{
+		RequestTimeout: time.Second,
+		MaxRetries:     3,
+		BackoffBase:    time.Millisecond,
+		BackoffMax:     5 * time.Millisecond,
+	})
+	client.baseUrl = srv.URL
+
+	return client, srv.Close
+}
+
+func TestClient_GetCurrentWeather(t *testing.T) {
+	const okBody = `{"location":{"name":"New York"},"current":{"temp_c":10.5,"humidity":40,"condition":{"text":"Cloudy"}}}`
+
+	testCases := map[string]struct {
+		handler          func(attempt int32, w http.ResponseWriter, r *http.Request)
+		expectErr        bool
+		expectedErr      error
+		expectedAttempts int32
+	}{
+		"must succeed at first attempt": {
+			handler: func(_ int32, w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("q") != "New York" || r.URL.Query().Get("key") != "key" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				_, _ = w.Write([]byte(okBody))
+			},
+			expectedAttempts: 1,
+		},
+		"must retry 5xx and 429 responses": {
+			handler: func(attempt int32, w http.ResponseWriter, _ *http.Request) {
+				switch attempt {
+				case 1:
+					w.WriteHeader(http.StatusServiceUnavailable)
+				case 2:
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+				default:
+					_, _ = w.Write([]byte(okBody))
+				}
+			},
+			expectedAttempts: 3,
+		},
+		"must give up after max retries": {
+			handler: func(_ int32, w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			expectErr:        true,
+			expectedAttempts: 4,
+		},
+		"must not retry city not found": {
+			handler: func(_ int32, w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+			},
+			expectErr:        true,
+			expectedErr:      ErrCityNotFound,
+			expectedAttempts: 1,
+		},
+		"must not retry other 4xx": {
+			handler: func(_ int32, w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+			},
+			expectErr:        true,
+			expectedAttempts: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			attempts := new(atomic.Int32)
+			client, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+				tc.handler(attempts.Add(1), w, r)
+			})
+			defer closeFn()
+
+			weather, err := client.GetCurrentWeather(context.Background(), "New York")
+			switch {
+			case !tc.expectErr && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.expectErr && err == nil:
+				t.Fatal("expected error, got nil")
+			case tc.expectedErr != nil && !errors.Is(err, tc.expectedErr):
+				t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+			case !tc.expectErr && weather.CurrentWeather.Temperature != 10.5:
+				t.Fatalf("unexpected weather %+v", weather)
+			}
+
+			if got := attempts.Load(); got != tc.expectedAttempts {
+				t.Fatalf("expected %d attempts, got %d", tc.expectedAttempts, got)
+			}
+		})
+	}
+}
+
+func TestClient_GetCurrentWeather_Language(t *testing.T) {
+	client, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("lang") != "uk" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"location":{"name":"Kyiv"},"current":{"condition":{"text":"Хмарно"}}}`))
+	})
+	defer closeFn()
+
+	weather, err := client.GetCurrentWeather(WithLanguage(context.Background(), "uk"), "Kyiv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weather.CurrentWeather.Condition.Text != "Хмарно" {
+		t.Fatalf("unexpected condition %s", weather.CurrentWeather.Condition.Text)
+	}
+}
+
+func TestClient_GetCurrentWeather_AirQuality(t *testing.T) {
+	client, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("aqi") != "yes" {
+			_, _ = w.Write([]byte(`{"location":{"name":"Kyiv"},"current":{"temp_c":10.5}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"location":{"name":"Kyiv"},"current":{"temp_c":10.5,"air_quality":{"pm2_5":12.3,"us-epa-index":2}}}`))
+	})
+	defer closeFn()
+
+	weather, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if weather.CurrentWeather.AirQuality != nil {
+		t.Fatalf("expected no air quality unless requested, got %+v", weather.CurrentWeather.AirQuality)
+	}
+
+	weather, err = client.GetCurrentWeather(WithAirQuality(context.Background()), "Kyiv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if aqi := weather.CurrentWeather.AirQuality; aqi == nil || aqi.PM25 != 12.3 || aqi.USEPAIndex != 2 {
+		t.Fatalf("unexpected air quality %+v", aqi)
+	}
+}
+
+func TestClient_GetCurrentWeather_Cancellation(t *testing.T) {
+	client, closeFn := newTestClient(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer closeFn()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetCurrentWeather(ctx, "New York")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("retry-after wait was not interrupted by ctx, took %s", elapsed)
+	}
+}
+
+func TestClient_ResolveLocation(t *testing.T) {
+	client, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search.json":
+			switch r.URL.Query().Get("q") {
+			case "Kiev":
+				_, _ = w.Write([]byte(`[{"id":2,"name":"Kyiv"},{"id":3,"name":"Kievka"}]`))
+			case "Kyiv":
+				_, _ = w.Write([]byte(`[{"id":3,"name":"Kyivska"},{"id":2,"name":"Kyiv"}]`))
+			default:
+				_, _ = w.Write([]byte(`[]`))
+			}
+		case "/current.json":
+			if r.URL.Query().Get("q") != "id:2" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"location":{"name":"Kyiv","country":"Ukraine","lat":50.43,"lon":30.52,"tz_id":"Europe/Kyiv"}}`))
+		}
+	})
+	defer closeFn()
+
+	testCases := map[string]struct {
+		query       LocationQuery
+		expectedErr error
+	}{
+		"must resolve the best guess":   {query: LocationQuery{Name: "Kiev"}},
+		"must prefer the exact name":    {query: LocationQuery{Name: "Kyiv"}},
+		"must resolve by id":            {query: LocationQuery{Id: "weatherapi:2"}},
+		"must not find unknown city":    {query: LocationQuery{Name: "Nowhere"}, expectedErr: ErrCityNotFound},
+		"must not find foreign id":      {query: LocationQuery{Id: "openmeteo:2"}, expectedErr: ErrCityNotFound},
+		"must not find not existing id": {query: LocationQuery{Id: "weatherapi:5"}, expectedErr: ErrCityNotFound},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			location, err := client.ResolveLocation(context.Background(), tc.query)
+			if tc.expectedErr != nil {
+				if !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("expected error %v, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if location.Id != "weatherapi:2" || location.Name != "Kyiv" || location.Timezone != "Europe/Kyiv" {
+				t.Fatalf("unexpected location %+v", location)
+			}
+		})
+	}
+}
+
+func TestClient_SearchLocations(t *testing.T) {
+	client, closeFn := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[
+			{"id":1,"name":"Londonderry","country":"United Kingdom"},
+			{"id":2,"name":"New London","country":"United States of America"},
+			{"id":3,"name":"London","country":"United Kingdom"},
+			{"id":4,"name":"London","country":"Canada"}
+		]`))
+	})
+	defer closeFn()
+
+	locations, err := client.SearchLocations(context.Background(), "london", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, location := range locations {
+		ids = append(ids, location.Id)
+	}
+	// exact matches first, then prefix ones, the provider order is kept within the groups
+	if expected := []string{"weatherapi:3", "weatherapi:4", "weatherapi:1"}; !slices.Equal(ids, expected) {
+		t.Fatalf("expected %v, got %v", expected, ids)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		expected time.Duration
+	}{
+		"empty":        {value: "", expected: 0},
+		"seconds":      {value: "3", expected: 3 * time.Second},
+		"negative":     {value: "-3", expected: 0},
+		"garbage":      {value: "soon", expected: 0},
+		"date in past": {value: "Mon, 02 Jan 2006 15:04:05 GMT", expected: 0},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if got := parseRetryAfter(tc.value); got != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+
+	future := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(future); got <= 0 || got > time.Minute {
+		t.Fatalf("expected positive delay up to a minute, got %s", got)
+	}
+}
@@ -0,0 +1,201 @@
+package weatherapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 3
+	defaultCooldown         = time.Minute
+)
+
+type FailoverOpts struct {
+	// FailureThreshold is the number of consecutive failures after which the provider is considered unhealthy
+	FailureThreshold int
+	// Cooldown is the time the unhealthy provider is skipped for
+	Cooldown time.Duration
+}
+
+type NamedProvider struct {
+	Name     string
+	Provider WeatherProvider
+}
+
+type ProviderHealth struct {
+	Name                string
+	Healthy             bool
+	ConsecutiveFailures int
+	UnhealthyUntil      time.Time
+	LastError           error
+}
+
+// FailoverProvider tries the providers in priority order until one of them answers.
+// Unhealthy providers are moved to the end of the queue until their cooldown passes,
+// so they are still used as the last resort when everything else is failing.
+type FailoverProvider struct {
+	providers []*trackedProvider
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+type trackedProvider struct {
+	NamedProvider
+
+	mu             sync.Mutex
+	failures       int
+	unhealthyUntil time.Time
+	lastErr        error
+}
+
+func NewFailoverProvider(opts FailoverOpts, providers ...NamedProvider) *FailoverProvider {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaultFailureThreshold
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = defaultCooldown
+	}
+
+	tracked := make([]*trackedProvider, len(providers))
+	for i, provider := range providers {
+		tracked[i] = &trackedProvider{NamedProvider: provider}
+	}
+
+	return &FailoverProvider{
+		providers: tracked,
+		threshold: opts.FailureThreshold,
+		cooldown:  opts.Cooldown,
+		now:       time.Now,
+	}
+}
+
+func (f *FailoverProvider) GetCurrentWeather(ctx context.Context, city string) (*WeatherCurrentResponse, error) {
+	return failover(ctx, f, func(provider WeatherProvider) (*WeatherCurrentResponse, error) {
+		return provider.GetCurrentWeather(ctx, city)
+	})
+}
+
+func (f *FailoverProvider) GetForecast(ctx context.Context, city string, days int) (*WeatherForecastResponse, error) {
+	return failover(ctx, f, func(provider WeatherProvider) (*WeatherForecastResponse, error) {
+		return provider.GetForecast(ctx, city, days)
+	})
+}
+
+// ResolveLocation routes id queries to the provider that issued the id, as ids are not portable between providers
+func (f *FailoverProvider) ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error) {
+	if query.Id != "" {
+		for _, provider := range f.providers {
+			if provider.Name == LocationProvider(query.Id) {
+				return provider.Provider.ResolveLocation(ctx, query)
+			}
+		}
+
+		return nil, ErrCityNotFound
+	}
+
+	return failover(ctx, f, func(provider WeatherProvider) (*Location, error) {
+		return provider.ResolveLocation(ctx, query)
+	})
+}
+
+func (f *FailoverProvider) SearchLocations(ctx context.Context, query string, limit int) ([]Location, error) {
+	return failover(ctx, f, func(provider WeatherProvider) ([]Location, error) {
+		return provider.SearchLocations(ctx, query, limit)
+	})
+}
+
+// Health returns a snapshot of the providers state in priority order
+func (f *FailoverProvider) Health() []ProviderHealth {
+	now := f.now()
+	health := make([]ProviderHealth, len(f.providers))
+	for i, provider := range f.providers {
+		provider.mu.Lock()
+		health[i] = ProviderHealth{
+			Name:                provider.Name,
+			Healthy:             !now.Before(provider.unhealthyUntil),
+			ConsecutiveFailures: provider.failures,
+			UnhealthyUntil:      provider.unhealthyUntil,
+			LastError:           provider.lastErr,
+		}
+		provider.mu.Unlock()
+	}
+
+	return health
+}
+
+func failover[T any](ctx context.Context, f *FailoverProvider, call func(WeatherProvider) (T, error)) (T, error) {
+	var (
+		zero T
+		errs []error
+	)
+
+	for _, provider := range f.ordered() {
+		result, err := call(provider.Provider)
+		switch {
+		case err == nil:
+			provider.succeeded()
+			return result, nil
+		case errors.Is(err, ErrCityNotFound):
+			// a valid answer rather than an outage, other providers will most likely agree
+			provider.succeeded()
+			return zero, err
+		case ctx.Err() != nil:
+			// caller gave up, it says nothing about the provider health
+			return zero, ctx.Err()
+		}
+
+		provider.failed(err, f.threshold, f.now().Add(f.cooldown))
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+	}
+
+	if len(errs) == 0 {
+		return zero, errors.New("no weather providers configured")
+	}
+
+	return zero, fmt.Errorf("all weather providers failed: %w", errors.Join(errs...))
+}
+
+// ordered returns healthy providers first, both groups keep the priority order
+func (f *FailoverProvider) ordered() []*trackedProvider {
+	now := f.now()
+	healthy := make([]*trackedProvider, 0, len(f.providers))
+	var unhealthy []*trackedProvider
+
+	for _, provider := range f.providers {
+		provider.mu.Lock()
+		isHealthy := !now.Before(provider.unhealthyUntil)
+		provider.mu.Unlock()
+
+		if isHealthy {
+			healthy = append(healthy, provider)
+		} else {
+			unhealthy = append(unhealthy, provider)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (p *trackedProvider) succeeded() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failures = 0
+	p.unhealthyUntil = time.Time{}
+	p.lastErr = nil
+}
+
+func (p *trackedProvider) failed(err error, threshold int, unhealthyUntil time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failures++
+	p.lastErr = err
+	if p.failures >= threshold {
+		p.unhealthyUntil = unhealthyUntil
+	}
+}
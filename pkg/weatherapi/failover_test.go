@@ -0,0 +1,125 @@
+package weatherapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubProvider struct {
+	calls int
+	err   error
+}
+
+func (s *stubProvider) GetCurrentWeather(_ context.Context, city string) (*WeatherCurrentResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return &WeatherCurrentResponse{Location: Location{Name: city}}, nil
+}
+
+func (s *stubProvider) GetForecast(ctx context.Context, city string, _ int) (*WeatherForecastResponse, error) {
+	current, err := s.GetCurrentWeather(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	return &WeatherForecastResponse{Location: current.Location}, nil
+}
+
+func (s *stubProvider) ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error) {
+	current, err := s.GetCurrentWeather(ctx, query.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &current.Location, nil
+}
+
+func (s *stubProvider) SearchLocations(ctx context.Context, query string, _ int) ([]Location, error) {
+	location, err := s.ResolveLocation(ctx, LocationQuery{Name: query})
+	if err != nil {
+		return nil, err
+	}
+
+	return []Location{*location}, nil
+}
+
+func TestFailoverProvider(t *testing.T) {
+	var (
+		primary   = &stubProvider{err: errors.New("unavailable")}
+		secondary = &stubProvider{}
+		now       = time.Now()
+	)
+
+	provider := NewFailoverProvider(FailoverOpts{FailureThreshold: 2, Cooldown: time.Minute},
+		NamedProvider{Name: "primary", Provider: primary},
+		NamedProvider{Name: "secondary", Provider: secondary},
+	)
+	provider.now = func() time.Time { return now }
+
+	// primary fails below the threshold and is still tried first
+	for i := 0; i < 2; i++ {
+		if _, err := provider.GetCurrentWeather(context.Background(), "Kyiv"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if primary.calls != 2 || secondary.calls != 2 {
+		t.Fatalf("expected both providers to be called twice, got %d and %d", primary.calls, secondary.calls)
+	}
+
+	// primary reached the threshold and is skipped during the cooldown
+	if _, err := provider.GetForecast(context.Background(), "Kyiv", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if primary.calls != 2 || secondary.calls != 3 {
+		t.Fatalf("expected primary to be skipped, got %d and %d calls", primary.calls, secondary.calls)
+	}
+	if health := provider.Health(); health[0].Healthy || !health[1].Healthy {
+		t.Fatalf("unexpected health %+v", health)
+	}
+
+	// after the cooldown primary is tried again and recovers
+	now = now.Add(2 * time.Minute)
+	primary.err = nil
+	if _, err := provider.GetCurrentWeather(context.Background(), "Kyiv"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if primary.calls != 3 || secondary.calls != 3 {
+		t.Fatalf("expected primary to be used again, got %d and %d calls", primary.calls, secondary.calls)
+	}
+	if health := provider.Health(); !health[0].Healthy || health[0].ConsecutiveFailures != 0 {
+		t.Fatalf("unexpected health %+v", health)
+	}
+}
+
+func TestFailoverProvider_Errors(t *testing.T) {
+	t.Run("city not found is not failed over", func(t *testing.T) {
+		secondary := &stubProvider{}
+		provider := NewFailoverProvider(FailoverOpts{},
+			NamedProvider{Name: "primary", Provider: &stubProvider{err: ErrCityNotFound}},
+			NamedProvider{Name: "secondary", Provider: secondary},
+		)
+
+		if _, err := provider.GetCurrentWeather(context.Background(), "Nowhere"); !errors.Is(err, ErrCityNotFound) {
+			t.Fatalf("expected city not found, got %v", err)
+		}
+		if secondary.calls != 0 {
+			t.Fatalf("expected secondary not to be called")
+		}
+	})
+
+	t.Run("all providers failed", func(t *testing.T) {
+		provider := NewFailoverProvider(FailoverOpts{},
+			NamedProvider{Name: "primary", Provider: &stubProvider{err: errors.New("boom")}},
+			NamedProvider{Name: "secondary", Provider: &stubProvider{err: errors.New("bang")}},
+		)
+
+		if _, err := provider.GetCurrentWeather(context.Background(), "Kyiv"); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
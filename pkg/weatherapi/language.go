@@ -0,0 +1,17 @@
+package weatherapi
+
+import "context"
+
+type languageCtxKey struct{}
+
+// WithLanguage requests the condition texts in the language (ISO 639-1 code, e.g. "uk"),
+// the providers not supporting it (Open-Meteo) keep returning English ones
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageCtxKey{}, lang)
+}
+
+// LanguageFrom returns the requested language, empty means the provider default (English)
+func LanguageFrom(ctx context.Context) string {
+	lang, _ := ctx.Value(languageCtxKey{}).(string)
+	return lang
+}
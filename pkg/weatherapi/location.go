@@ -0,0 +1,123 @@
+package weatherapi
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxSearchResults is the upper bound of the location search limit
+const MaxSearchResults = 10
+
+// LocationQuery identifies a location by exactly one of its fields
+type LocationQuery struct {
+	// Name is a free-text city name
+	Name string
+	// Id is a canonical location id as returned by the location resolution
+	Id          string
+	Coordinates *Coordinates
+}
+
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func (q LocationQuery) String() string {
+	switch {
+	case q.Id != "":
+		return "id:" + q.Id
+	case q.Coordinates != nil:
+		return CoordinatesQuery(q.Coordinates.Latitude, q.Coordinates.Longitude)
+	default:
+		return q.Name
+	}
+}
+
+// CoordinatesQuery builds a weather query that every provider accepts in place of the city name,
+// such queries skip the name matching and are not affected by ambiguous or renamed cities
+func CoordinatesQuery(latitude, longitude float64) string {
+	return strconv.FormatFloat(latitude, 'f', 4, 64) + "," + strconv.FormatFloat(longitude, 'f', 4, 64)
+}
+
+func parseCoordinatesQuery(query string) (Coordinates, bool) {
+	rawLat, rawLon, found := strings.Cut(query, ",")
+	if !found {
+		return Coordinates{}, false
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(rawLat), 64)
+	if err != nil || latitude < -90 || latitude > 90 {
+		return Coordinates{}, false
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(rawLon), 64)
+	if err != nil || longitude < -180 || longitude > 180 {
+		return Coordinates{}, false
+	}
+
+	return Coordinates{Latitude: latitude, Longitude: longitude}, true
+}
+
+func locationId(provider string, id int64) string {
+	return provider + ":" + strconv.FormatInt(id, 10)
+}
+
+// parseLocationId returns the provider specific id, ids of other providers are treated as unknown locations
+func parseLocationId(provider, id string) (int64, error) {
+	rawId, found := strings.CutPrefix(id, provider+":")
+	if !found {
+		return 0, ErrCityNotFound
+	}
+
+	parsed, err := strconv.ParseInt(rawId, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid location id %q: %w", id, ErrCityNotFound)
+	}
+
+	return parsed, nil
+}
+
+// LocationProvider returns the name of the provider that issued the location id
+func LocationProvider(id string) string {
+	provider, _, _ := strings.Cut(id, ":")
+	return provider
+}
+
+// matchesCity reports whether the resolved location is the requested one,
+// weather api can return a different city name than requested (especially when auto-completing something)
+func matchesCity(location Location, city string) bool {
+	if _, ok := parseCoordinatesQuery(city); ok {
+		return true
+	}
+
+	return strings.ToLower(location.Name) == strings.ToLower(city)
+}
+
+// rankLocations puts exact name matches first and name prefix matches second, keeping the provider
+// relevance order within the groups, and trims the result to the limit
+func rankLocations(query string, locations []Location, limit int) []Location {
+	query = strings.ToLower(strings.TrimSpace(query))
+	rank := func(location Location) int {
+		name := strings.ToLower(location.Name)
+		switch {
+		case name == query:
+			return 0
+		case strings.HasPrefix(name, query):
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	slices.SortStableFunc(locations, func(a, b Location) int {
+		return cmp.Compare(rank(a), rank(b))
+	})
+
+	if limit > 0 && len(locations) > limit {
+		locations = locations[:limit]
+	}
+
+	return locations
+}
@@ -1,26 +1,137 @@
 package weatherapi
 
+import (
+	"context"
+	"time"
+)
+
 type MockWeatherProvider struct{}
 
 func NewMockWeatherProvider() WeatherProvider {
 	return &MockWeatherProvider{}
 }
 
-func (m *MockWeatherProvider) GetCurrentWeather(city string) (*WeatherCurrentResponse, error) {
+func (m *MockWeatherProvider) GetCurrentWeather(_ context.Context, city string) (*WeatherCurrentResponse, error) {
 	if city == "" {
 		return nil, ErrCityNotFound
 	}
 
+	current := CurrentWeather{
+		Temperature:   20.1,
+		FeelsLike:     19.4,
+		Humidity:      60,
+		IsDay:         1,
+		WindKph:       11.2,
+		WindDegree:    315,
+		WindDirection: "NW",
+		GustKph:       16.9,
+		PressureMb:    1016,
+		Cloud:         10,
+		UV:            5,
+		VisibilityKm:  10,
+		Condition: WeatherCondition{
+			Text: "Sunny",
+			Code: 1000,
+		},
+	}
+	current.deriveImperial()
+
 	return &WeatherCurrentResponse{
 		Location: Location{
 			Name: city,
 		},
-		CurrentWeather: CurrentWeather{
-			Temperature: 20.1,
-			Humidity:    60,
-			Condition: WeatherCondition{
-				Text: "Sunny",
+		CurrentWeather: current,
+	}, nil
+}
+
+func (m *MockWeatherProvider) ResolveLocation(_ context.Context, query LocationQuery) (*Location, error) {
+	location := &Location{
+		Id:        "mock:1",
+		Name:      query.Name,
+		Country:   "Mockland",
+		Latitude:  50.45,
+		Longitude: 30.52,
+		Timezone:  "Europe/Kyiv",
+	}
+
+	switch {
+	case query.Id != "" && query.Id != location.Id:
+		return nil, ErrCityNotFound
+	case query.Coordinates != nil:
+		location.Name = query.String()
+		location.Latitude, location.Longitude = query.Coordinates.Latitude, query.Coordinates.Longitude
+	case query.Id == "" && query.Name == "":
+		return nil, ErrCityNotFound
+	case query.Id != "":
+		location.Name = "Mock City"
+	}
+
+	return location, nil
+}
+
+func (m *MockWeatherProvider) SearchLocations(_ context.Context, query string, limit int) ([]Location, error) {
+	locations := []Location{
+		{Id: "mock:1", Name: query, Region: "Mock Region", Country: "Mockland", Latitude: 50.45, Longitude: 30.52},
+		{Id: "mock:2", Name: query + " Mockovo", Region: "Other Mock Region", Country: "Mockland", Latitude: 48.92, Longitude: 24.71},
+	}
+	if limit < len(locations) {
+		locations = locations[:limit]
+	}
+
+	return locations, nil
+}
+
+func (m *MockWeatherProvider) GetForecast(_ context.Context, city string, days int) (*WeatherForecastResponse, error) {
+	if city == "" {
+		return nil, ErrCityNotFound
+	}
+
+	forecast := make([]ForecastDay, days)
+	start := time.Now().Truncate(24 * time.Hour)
+	for i := range forecast {
+		date := start.AddDate(0, 0, i)
+
+		hours := make([]HourForecast, 24)
+		for h := range hours {
+			at := date.Add(time.Duration(h) * time.Hour)
+			hours[h] = HourForecast{
+				TimeEpoch:    at.Unix(),
+				Time:         at.Format(HourTimeLayout),
+				Temperature:  15 + float32(h)/4,
+				Humidity:     60,
+				ChanceOfRain: 10,
+				Condition: WeatherCondition{
+					Text: "Sunny",
+				},
+			}
+		}
+
+		forecast[i] = ForecastDay{
+			Date: date.Format(dateLayout),
+			Day: DayForecast{
+				MaxTemperature: 21,
+				MinTemperature: 15,
+				AvgTemperature: 18,
+				AvgHumidity:    60,
+				ChanceOfRain:   10,
+				MaxWind:        15,
+				Condition: WeatherCondition{
+					Text: "Sunny",
+				},
 			},
-		},
+			Astro: Astro{
+				Sunrise: "05:30 AM",
+				Sunset:  "09:10 PM",
+			},
+			Hours: hours,
+		}
+	}
+
+	current, _ := m.GetCurrentWeather(context.Background(), city)
+
+	return &WeatherForecastResponse{
+		Location:       current.Location,
+		CurrentWeather: current.CurrentWeather,
+		Forecast:       Forecast{Days: forecast},
 	}, nil
 }
@@ -5,6 +5,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/slbmax/ses-weather-app/pkg/weatherapi"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -37,8 +39,8 @@ func (_m *MockWeatherProvider) EXPECT() *MockWeatherProvider_Expecter {
 }
 
 // GetCurrentWeather provides a mock function for the type MockWeatherProvider
-func (_mock *MockWeatherProvider) GetCurrentWeather(city string) (*weatherapi.WeatherCurrentResponse, error) {
-	ret := _mock.Called(city)
+func (_mock *MockWeatherProvider) GetCurrentWeather(ctx context.Context, city string) (*weatherapi.WeatherCurrentResponse, error) {
+	ret := _mock.Called(ctx, city)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetCurrentWeather")
@@ -46,18 +48,18 @@ func (_mock *MockWeatherProvider) GetCurrentWeather(city string) (*weatherapi.We
 
 	var r0 *weatherapi.WeatherCurrentResponse
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (*weatherapi.WeatherCurrentResponse, error)); ok {
-		return returnFunc(city)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*weatherapi.WeatherCurrentResponse, error)); ok {
+		return returnFunc(ctx, city)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) *weatherapi.WeatherCurrentResponse); ok {
-		r0 = returnFunc(city)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *weatherapi.WeatherCurrentResponse); ok {
+		r0 = returnFunc(ctx, city)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*weatherapi.WeatherCurrentResponse)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
-		r1 = returnFunc(city)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, city)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,14 +72,15 @@ type MockWeatherProvider_GetCurrentWeather_Call struct {
 }
 
 // GetCurrentWeather is a helper method to define mock.On call
+//   - ctx
 //   - city
-func (_e *MockWeatherProvider_Expecter) GetCurrentWeather(city interface{}) *MockWeatherProvider_GetCurrentWeather_Call {
-	return &MockWeatherProvider_GetCurrentWeather_Call{Call: _e.mock.On("GetCurrentWeather", city)}
+func (_e *MockWeatherProvider_Expecter) GetCurrentWeather(ctx interface{}, city interface{}) *MockWeatherProvider_GetCurrentWeather_Call {
+	return &MockWeatherProvider_GetCurrentWeather_Call{Call: _e.mock.On("GetCurrentWeather", ctx, city)}
 }
 
-func (_c *MockWeatherProvider_GetCurrentWeather_Call) Run(run func(city string)) *MockWeatherProvider_GetCurrentWeather_Call {
+func (_c *MockWeatherProvider_GetCurrentWeather_Call) Run(run func(ctx context.Context, city string)) *MockWeatherProvider_GetCurrentWeather_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
@@ -87,7 +90,180 @@ func (_c *MockWeatherProvider_GetCurrentWeather_Call) Return(weatherCurrentRespo
 	return _c
 }
 
-func (_c *MockWeatherProvider_GetCurrentWeather_Call) RunAndReturn(run func(city string) (*weatherapi.WeatherCurrentResponse, error)) *MockWeatherProvider_GetCurrentWeather_Call {
+func (_c *MockWeatherProvider_GetCurrentWeather_Call) RunAndReturn(run func(ctx context.Context, city string) (*weatherapi.WeatherCurrentResponse, error)) *MockWeatherProvider_GetCurrentWeather_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetForecast provides a mock function for the type MockWeatherProvider
+func (_mock *MockWeatherProvider) GetForecast(ctx context.Context, city string, days int) (*weatherapi.WeatherForecastResponse, error) {
+	ret := _mock.Called(ctx, city, days)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForecast")
+	}
+
+	var r0 *weatherapi.WeatherForecastResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) (*weatherapi.WeatherForecastResponse, error)); ok {
+		return returnFunc(ctx, city, days)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) *weatherapi.WeatherForecastResponse); ok {
+		r0 = returnFunc(ctx, city, days)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*weatherapi.WeatherForecastResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, city, days)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockWeatherProvider_GetForecast_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetForecast'
+type MockWeatherProvider_GetForecast_Call struct {
+	*mock.Call
+}
+
+// GetForecast is a helper method to define mock.On call
+//   - ctx
+//   - city
+//   - days
+func (_e *MockWeatherProvider_Expecter) GetForecast(ctx interface{}, city interface{}, days interface{}) *MockWeatherProvider_GetForecast_Call {
+	return &MockWeatherProvider_GetForecast_Call{Call: _e.mock.On("GetForecast", ctx, city, days)}
+}
+
+func (_c *MockWeatherProvider_GetForecast_Call) Run(run func(ctx context.Context, city string, days int)) *MockWeatherProvider_GetForecast_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockWeatherProvider_GetForecast_Call) Return(weatherForecastResponse *weatherapi.WeatherForecastResponse, err error) *MockWeatherProvider_GetForecast_Call {
+	_c.Call.Return(weatherForecastResponse, err)
+	return _c
+}
+
+func (_c *MockWeatherProvider_GetForecast_Call) RunAndReturn(run func(ctx context.Context, city string, days int) (*weatherapi.WeatherForecastResponse, error)) *MockWeatherProvider_GetForecast_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveLocation provides a mock function for the type MockWeatherProvider
+func (_mock *MockWeatherProvider) ResolveLocation(ctx context.Context, query weatherapi.LocationQuery) (*weatherapi.Location, error) {
+	ret := _mock.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveLocation")
+	}
+
+	var r0 *weatherapi.Location
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, weatherapi.LocationQuery) (*weatherapi.Location, error)); ok {
+		return returnFunc(ctx, query)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, weatherapi.LocationQuery) *weatherapi.Location); ok {
+		r0 = returnFunc(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*weatherapi.Location)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, weatherapi.LocationQuery) error); ok {
+		r1 = returnFunc(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockWeatherProvider_ResolveLocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveLocation'
+type MockWeatherProvider_ResolveLocation_Call struct {
+	*mock.Call
+}
+
+// ResolveLocation is a helper method to define mock.On call
+//   - ctx
+//   - query
+func (_e *MockWeatherProvider_Expecter) ResolveLocation(ctx interface{}, query interface{}) *MockWeatherProvider_ResolveLocation_Call {
+	return &MockWeatherProvider_ResolveLocation_Call{Call: _e.mock.On("ResolveLocation", ctx, query)}
+}
+
+func (_c *MockWeatherProvider_ResolveLocation_Call) Run(run func(ctx context.Context, query weatherapi.LocationQuery)) *MockWeatherProvider_ResolveLocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(weatherapi.LocationQuery))
+	})
+	return _c
+}
+
+func (_c *MockWeatherProvider_ResolveLocation_Call) Return(location *weatherapi.Location, err error) *MockWeatherProvider_ResolveLocation_Call {
+	_c.Call.Return(location, err)
+	return _c
+}
+
+func (_c *MockWeatherProvider_ResolveLocation_Call) RunAndReturn(run func(ctx context.Context, query weatherapi.LocationQuery) (*weatherapi.Location, error)) *MockWeatherProvider_ResolveLocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SearchLocations provides a mock function for the type MockWeatherProvider
+func (_mock *MockWeatherProvider) SearchLocations(ctx context.Context, query string, limit int) ([]weatherapi.Location, error) {
+	ret := _mock.Called(ctx, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchLocations")
+	}
+
+	var r0 []weatherapi.Location
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) ([]weatherapi.Location, error)); ok {
+		return returnFunc(ctx, query, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int) []weatherapi.Location); ok {
+		r0 = returnFunc(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]weatherapi.Location)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = returnFunc(ctx, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockWeatherProvider_SearchLocations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SearchLocations'
+type MockWeatherProvider_SearchLocations_Call struct {
+	*mock.Call
+}
+
+// SearchLocations is a helper method to define mock.On call
+//   - ctx
+//   - query
+//   - limit
+func (_e *MockWeatherProvider_Expecter) SearchLocations(ctx interface{}, query interface{}, limit interface{}) *MockWeatherProvider_SearchLocations_Call {
+	return &MockWeatherProvider_SearchLocations_Call{Call: _e.mock.On("SearchLocations", ctx, query, limit)}
+}
+
+func (_c *MockWeatherProvider_SearchLocations_Call) Run(run func(ctx context.Context, query string, limit int)) *MockWeatherProvider_SearchLocations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockWeatherProvider_SearchLocations_Call) Return(locations []weatherapi.Location, err error) *MockWeatherProvider_SearchLocations_Call {
+	_c.Call.Return(locations, err)
+	return _c
+}
+
+func (_c *MockWeatherProvider_SearchLocations_Call) RunAndReturn(run func(ctx context.Context, query string, limit int) ([]weatherapi.Location, error)) *MockWeatherProvider_SearchLocations_Call {
 	_c.Call.Return(run)
 	return _c
 }
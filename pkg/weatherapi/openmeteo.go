@@ -0,0 +1,432 @@
+package weatherapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	openMeteoGeocodingUrl = "https://geocoding-api.open-meteo.com/v1"
+	openMeteoForecastUrl  = "https://api.open-meteo.com/v1"
+
+	// open-meteo returns local times in ISO 8601 without seconds and zone
+	openMeteoTimeLayout = "2006-01-02T15:04"
+
+	openMeteoMaxForecastDays = 16
+)
+
+// OpenMeteoClient is a keyless provider backed by https://open-meteo.com,
+// the city is resolved to coordinates with its geocoding API first
+type OpenMeteoClient struct {
+	geocodingUrl string
+	forecastUrl  string
+	transport    transport
+}
+
+func NewOpenMeteoClient(opts ClientOpts) *OpenMeteoClient {
+	return &OpenMeteoClient{
+		geocodingUrl: openMeteoGeocodingUrl,
+		forecastUrl:  openMeteoForecastUrl,
+		transport:    newTransport(opts),
+	}
+}
+
+type openMeteoGeocodingResult struct {
+	Id        int64   `json:"id"`
+	Name      string  `json:"name"`
+	Region    string  `json:"admin1"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+}
+
+func (r openMeteoGeocodingResult) toLocation() *Location {
+	return &Location{
+		Id:        locationId(ProviderOpenMeteo, r.Id),
+		Name:      r.Name,
+		Region:    r.Region,
+		Country:   r.Country,
+		Latitude:  r.Latitude,
+		Longitude: r.Longitude,
+		Timezone:  r.Timezone,
+	}
+}
+
+type openMeteoGeocodingResponse struct {
+	Results []openMeteoGeocodingResult `json:"results"`
+}
+
+// openMeteoCurrentParams are the variables of the current conditions, see CurrentWeather
+const openMeteoCurrentParams = "temperature_2m,apparent_temperature,relative_humidity_2m,is_day,precipitation,cloud_cover," +
+	"pressure_msl,wind_speed_10m,wind_direction_10m,wind_gusts_10m,uv_index,visibility,weather_code"
+
+type openMeteoForecastResponse struct {
+	Timezone         string `json:"timezone"`
+	UtcOffsetSeconds int    `json:"utc_offset_seconds"`
+	Current          struct {
+		Temperature   float32 `json:"temperature_2m"`
+		FeelsLike     float32 `json:"apparent_temperature"`
+		Humidity      uint8   `json:"relative_humidity_2m"`
+		IsDay         uint8   `json:"is_day"`
+		Precipitation float32 `json:"precipitation"`
+		CloudCover    uint8   `json:"cloud_cover"`
+		Pressure      float32 `json:"pressure_msl"`
+		WindSpeed     float32 `json:"wind_speed_10m"`
+		WindDirection uint16  `json:"wind_direction_10m"`
+		WindGusts     float32 `json:"wind_gusts_10m"`
+		UVIndex       float32 `json:"uv_index"`
+		Visibility    float32 `json:"visibility"` // meters
+		WeatherCode   int     `json:"weather_code"`
+	} `json:"current"`
+	Daily struct {
+		Time               []string  `json:"time"`
+		MaxTemperature     []float32 `json:"temperature_2m_max"`
+		MinTemperature     []float32 `json:"temperature_2m_min"`
+		Precipitation      []float32 `json:"precipitation_sum"`
+		PrecipitationProba []uint8   `json:"precipitation_probability_max"`
+		MaxWind            []float32 `json:"wind_speed_10m_max"`
+		Sunrise            []string  `json:"sunrise"`
+		Sunset             []string  `json:"sunset"`
+		WeatherCode        []int     `json:"weather_code"`
+	} `json:"daily"`
+	Hourly struct {
+		Time               []string  `json:"time"`
+		Temperature        []float32 `json:"temperature_2m"`
+		Humidity           []uint8   `json:"relative_humidity_2m"`
+		Precipitation      []float32 `json:"precipitation"`
+		PrecipitationProba []uint8   `json:"precipitation_probability"`
+		WindSpeed          []float32 `json:"wind_speed_10m"`
+		WeatherCode        []int     `json:"weather_code"`
+	} `json:"hourly"`
+}
+
+func (c *OpenMeteoClient) GetCurrentWeather(ctx context.Context, city string) (*WeatherCurrentResponse, error) {
+	location, err := c.resolve(ctx, city)
+	if err != nil {
+		return nil, fmt.Errorf("could not get current weather: %w", err)
+	}
+
+	query := location.query()
+	query.Set("current", openMeteoCurrentParams)
+
+	var response openMeteoForecastResponse
+	if err = c.transport.getJSON(ctx, c.forecastUrl+"/forecast?"+query.Encode(), 0, &response); err != nil {
+		return nil, fmt.Errorf("could not get current weather: %w", err)
+	}
+
+	return &WeatherCurrentResponse{
+		Location:       Location{Name: location.name},
+		CurrentWeather: response.current(),
+	}, nil
+}
+
+func (c *OpenMeteoClient) GetForecast(ctx context.Context, city string, days int) (*WeatherForecastResponse, error) {
+	if days < 1 || days > MaxForecastDays || days > openMeteoMaxForecastDays {
+		return nil, fmt.Errorf("invalid forecast days number: %d", days)
+	}
+
+	location, err := c.resolve(ctx, city)
+	if err != nil {
+		return nil, fmt.Errorf("could not get weather forecast: %w", err)
+	}
+
+	query := location.query()
+	query.Set("forecast_days", strconv.Itoa(days))
+	query.Set("current", openMeteoCurrentParams)
+	query.Set("daily", "temperature_2m_max,temperature_2m_min,precipitation_sum,precipitation_probability_max,wind_speed_10m_max,sunrise,sunset,weather_code")
+	query.Set("hourly", "temperature_2m,relative_humidity_2m,precipitation,precipitation_probability,wind_speed_10m,weather_code")
+
+	var response openMeteoForecastResponse
+	if err = c.transport.getJSON(ctx, c.forecastUrl+"/forecast?"+query.Encode(), 0, &response); err != nil {
+		return nil, fmt.Errorf("could not get weather forecast: %w", err)
+	}
+
+	forecast, err := response.toForecast()
+	if err != nil {
+		return nil, fmt.Errorf("could not map weather forecast: %w", err)
+	}
+
+	return &WeatherForecastResponse{
+		Location:       Location{Name: location.name},
+		CurrentWeather: response.current(),
+		Forecast:       forecast,
+	}, nil
+}
+
+// ResolveLocation uses the geocoding API for names and ids. There is no reverse geocoding,
+// so a coordinates query results in a location without id, named after the coordinates.
+func (c *OpenMeteoClient) ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error) {
+	switch {
+	case query.Id != "":
+		id, err := parseLocationId(ProviderOpenMeteo, query.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		var result openMeteoGeocodingResult
+		requestUrl := c.geocodingUrl + "/get?" + url.Values{"id": {strconv.FormatInt(id, 10)}}.Encode()
+		if err = c.transport.getJSON(ctx, requestUrl, http.StatusNotFound, &result); err != nil {
+			return nil, fmt.Errorf("could not get location: %w", err)
+		}
+
+		return result.toLocation(), nil
+	case query.Coordinates != nil:
+		location := openMeteoLocation{
+			name:      query.String(),
+			latitude:  query.Coordinates.Latitude,
+			longitude: query.Coordinates.Longitude,
+		}
+
+		var response openMeteoForecastResponse
+		if err := c.transport.getJSON(ctx, c.forecastUrl+"/forecast?"+location.query().Encode(), 0, &response); err != nil {
+			return nil, fmt.Errorf("could not get location timezone: %w", err)
+		}
+
+		return &Location{
+			Name:      location.name,
+			Latitude:  location.latitude,
+			Longitude: location.longitude,
+			Timezone:  response.Timezone,
+		}, nil
+	default:
+		results, err := c.search(ctx, query.Name, 10)
+		if err != nil {
+			return nil, err
+		}
+
+		// exact name wins, otherwise the provider's best guess is taken
+		for _, result := range results {
+			if matchesCity(Location{Name: result.Name}, query.Name) {
+				return result.toLocation(), nil
+			}
+		}
+
+		return results[0].toLocation(), nil
+	}
+}
+
+func (c *OpenMeteoClient) SearchLocations(ctx context.Context, query string, limit int) ([]Location, error) {
+	results, err := c.search(ctx, query, limit)
+	switch {
+	case errors.Is(err, ErrCityNotFound):
+		return []Location{}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	locations := make([]Location, len(results))
+	for i, result := range results {
+		locations[i] = *result.toLocation()
+	}
+
+	return rankLocations(query, locations, limit), nil
+}
+
+type openMeteoLocation struct {
+	name      string
+	latitude  float64
+	longitude float64
+}
+
+func (l openMeteoLocation) query() url.Values {
+	return url.Values{
+		"latitude":  {strconv.FormatFloat(l.latitude, 'f', 4, 64)},
+		"longitude": {strconv.FormatFloat(l.longitude, 'f', 4, 64)},
+		"timezone":  {"auto"},
+	}
+}
+
+// resolve turns the weather query into coordinates, skipping geocoding for coordinates queries
+func (c *OpenMeteoClient) resolve(ctx context.Context, city string) (openMeteoLocation, error) {
+	if coordinates, ok := parseCoordinatesQuery(city); ok {
+		return openMeteoLocation{
+			name:      city,
+			latitude:  coordinates.Latitude,
+			longitude: coordinates.Longitude,
+		}, nil
+	}
+
+	results, err := c.search(ctx, city, 1)
+	if err != nil {
+		return openMeteoLocation{}, err
+	}
+
+	// keeping the same strictness as the weatherapi.com client
+	if !matchesCity(Location{Name: results[0].Name}, city) {
+		return openMeteoLocation{}, ErrCityNotFound
+	}
+
+	return openMeteoLocation{
+		name:      results[0].Name,
+		latitude:  results[0].Latitude,
+		longitude: results[0].Longitude,
+	}, nil
+}
+
+// search returns at least one geocoding result or ErrCityNotFound
+func (c *OpenMeteoClient) search(ctx context.Context, name string, count int) ([]openMeteoGeocodingResult, error) {
+	query := url.Values{
+		"name":  {name},
+		"count": {strconv.Itoa(count)},
+	}
+
+	var response openMeteoGeocodingResponse
+	if err := c.transport.getJSON(ctx, c.geocodingUrl+"/search?"+query.Encode(), http.StatusNotFound, &response); err != nil {
+		return nil, fmt.Errorf("failed to search locations: %w", err)
+	} else if len(response.Results) == 0 {
+		return nil, ErrCityNotFound
+	}
+
+	return response.Results, nil
+}
+
+func (r openMeteoForecastResponse) current() CurrentWeather {
+	current := CurrentWeather{
+		Temperature:     r.Current.Temperature,
+		FeelsLike:       r.Current.FeelsLike,
+		Humidity:        r.Current.Humidity,
+		IsDay:           r.Current.IsDay,
+		WindKph:         r.Current.WindSpeed,
+		WindDegree:      r.Current.WindDirection,
+		WindDirection:   compassDirection(r.Current.WindDirection),
+		GustKph:         r.Current.WindGusts,
+		PressureMb:      r.Current.Pressure,
+		PrecipitationMm: r.Current.Precipitation,
+		Cloud:           r.Current.CloudCover,
+		UV:              r.Current.UVIndex,
+		VisibilityKm:    roundTenth(r.Current.Visibility / 1000),
+		Condition:       wmoCondition(r.Current.WeatherCode),
+	}
+	current.deriveImperial()
+
+	return current
+}
+
+func (r openMeteoForecastResponse) toForecast() (Forecast, error) {
+	zone := time.FixedZone("", r.UtcOffsetSeconds)
+	daily, hourly := r.Daily, r.Hourly
+
+	days := make([]ForecastDay, len(daily.Time))
+	for i, date := range daily.Time {
+		days[i] = ForecastDay{
+			Date: date,
+			Day: DayForecast{
+				MaxTemperature: at(daily.MaxTemperature, i),
+				MinTemperature: at(daily.MinTemperature, i),
+				// the api has no daily average, the mean of extremes is good enough
+				AvgTemperature:     (at(daily.MaxTemperature, i) + at(daily.MinTemperature, i)) / 2,
+				TotalPrecipitation: at(daily.Precipitation, i),
+				ChanceOfRain:       at(daily.PrecipitationProba, i),
+				MaxWind:            at(daily.MaxWind, i),
+				Condition:          wmoCondition(at(daily.WeatherCode, i)),
+			},
+			Astro: Astro{
+				Sunrise: clockTime(at(daily.Sunrise, i)),
+				Sunset:  clockTime(at(daily.Sunset, i)),
+			},
+		}
+	}
+
+	var humiditySum = make([]float32, len(days))
+	for i, rawTime := range hourly.Time {
+		localTime, err := time.ParseInLocation(openMeteoTimeLayout, rawTime, zone)
+		if err != nil {
+			return Forecast{}, fmt.Errorf("invalid hourly time %q: %w", rawTime, err)
+		}
+
+		day := i / 24
+		if day >= len(days) {
+			break
+		}
+
+		days[day].Hours = append(days[day].Hours, HourForecast{
+			TimeEpoch:     localTime.Unix(),
+			Time:          localTime.Format(HourTimeLayout),
+			Temperature:   at(hourly.Temperature, i),
+			Humidity:      at(hourly.Humidity, i),
+			Precipitation: at(hourly.Precipitation, i),
+			ChanceOfRain:  at(hourly.PrecipitationProba, i),
+			WindKph:       at(hourly.WindSpeed, i),
+			Condition:     wmoCondition(at(hourly.WeatherCode, i)),
+		})
+		humiditySum[day] += float32(at(hourly.Humidity, i))
+	}
+
+	for i := range days {
+		if hours := len(days[i].Hours); hours > 0 {
+			days[i].Day.AvgHumidity = humiditySum[i] / float32(hours)
+		}
+	}
+
+	forecast := Forecast{Days: days}
+	forecast.deriveImperial()
+
+	return forecast, nil
+}
+
+// clockTime converts an ISO local time into the "hh:mm AM/PM" format used by Astro
+func clockTime(isoTime string) string {
+	parsed, err := time.Parse(openMeteoTimeLayout, isoTime)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Format(astroTimeLayout)
+}
+
+// at is a bounds-safe accessor for the columnar open-meteo arrays
+func at[T any](values []T, i int) T {
+	var zero T
+	if i >= len(values) {
+		return zero
+	}
+
+	return values[i]
+}
+
+// wmoConditions maps WMO weather interpretation codes used by open-meteo to a human-readable text
+// and the closest weatherapi.com condition code
+var wmoConditions = map[int]WeatherCondition{
+	0:  {Text: "Clear sky", Code: 1000},
+	1:  {Text: "Mainly clear", Code: 1003},
+	2:  {Text: "Partly cloudy", Code: 1003},
+	3:  {Text: "Overcast", Code: 1009},
+	45: {Text: "Fog", Code: 1135},
+	48: {Text: "Fog", Code: 1147},
+	51: {Text: "Drizzle", Code: 1153},
+	53: {Text: "Drizzle", Code: 1153},
+	55: {Text: "Drizzle", Code: 1153},
+	56: {Text: "Freezing drizzle", Code: 1168},
+	57: {Text: "Freezing drizzle", Code: 1171},
+	61: {Text: "Slight rain", Code: 1183},
+	63: {Text: "Moderate rain", Code: 1189},
+	65: {Text: "Heavy rain", Code: 1195},
+	66: {Text: "Freezing rain", Code: 1198},
+	67: {Text: "Freezing rain", Code: 1201},
+	71: {Text: "Slight snow fall", Code: 1213},
+	73: {Text: "Moderate snow fall", Code: 1219},
+	75: {Text: "Heavy snow fall", Code: 1225},
+	77: {Text: "Snow grains", Code: 1237},
+	80: {Text: "Rain showers", Code: 1240},
+	81: {Text: "Rain showers", Code: 1243},
+	82: {Text: "Rain showers", Code: 1246},
+	85: {Text: "Snow showers", Code: 1255},
+	86: {Text: "Snow showers", Code: 1258},
+	95: {Text: "Thunderstorm", Code: 1276},
+	96: {Text: "Thunderstorm with hail", Code: 1276},
+	99: {Text: "Thunderstorm with hail", Code: 1276},
+}
+
+func wmoCondition(code int) WeatherCondition {
+	if condition, ok := wmoConditions[code]; ok {
+		return condition
+	}
+
+	return WeatherCondition{Text: "Unknown"}
+}
@@ -0,0 +1,421 @@
+package weatherapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	openWeatherMapUrl          = "https://api.openweathermap.org/data/2.5"
+	openWeatherMapGeocodingUrl = "https://api.openweathermap.org/geo/1.0"
+
+	// free plan forecast covers 5 days with 3-hour steps
+	openWeatherMapMaxForecastDays = 5
+	openWeatherMapStepsPerDay     = 8
+)
+
+// OpenWeatherMapClient is a provider backed by https://openweathermap.org
+type OpenWeatherMapClient struct {
+	apiKey       string
+	baseUrl      string
+	geocodingUrl string
+	transport    transport
+}
+
+func NewOpenWeatherMapClient(apiKey string, opts ClientOpts) *OpenWeatherMapClient {
+	return &OpenWeatherMapClient{
+		apiKey:       apiKey,
+		baseUrl:      openWeatherMapUrl,
+		geocodingUrl: openWeatherMapGeocodingUrl,
+		transport:    newTransport(opts),
+	}
+}
+
+type openWeatherMapMain struct {
+	Temperature    float32 `json:"temp"`
+	FeelsLike      float32 `json:"feels_like"`
+	MinTemperature float32 `json:"temp_min"`
+	MaxTemperature float32 `json:"temp_max"`
+	Pressure       float32 `json:"pressure"` // hPa, the same as mb
+	Humidity       uint8   `json:"humidity"`
+}
+
+type openWeatherMapWind struct {
+	Speed float32 `json:"speed"` // m/s for the metric units
+	Deg   uint16  `json:"deg"`
+	Gust  float32 `json:"gust"`
+}
+
+// kph converts the vendor metric wind speed
+func (w openWeatherMapWind) kph() float32 {
+	return roundTenth(w.Speed * 3.6)
+}
+
+type openWeatherMapCondition struct {
+	Id          int    `json:"id"`
+	Description string `json:"description"`
+	// Icon is the vendor icon id, e.g. "10d", the "d" and "n" suffixes are the day and night variants
+	Icon string `json:"icon"`
+}
+
+type openWeatherMapCurrentResponse struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Coord struct {
+		Latitude  float64 `json:"lat"`
+		Longitude float64 `json:"lon"`
+	} `json:"coord"`
+	Sys struct {
+		Country string `json:"country"`
+	} `json:"sys"`
+	Main       openWeatherMapMain        `json:"main"`
+	Weather    []openWeatherMapCondition `json:"weather"`
+	Wind       openWeatherMapWind        `json:"wind"`
+	Visibility float32                   `json:"visibility"` // meters
+	Clouds     struct {
+		All uint8 `json:"all"`
+	} `json:"clouds"`
+	Rain struct {
+		OneHour float32 `json:"1h"`
+	} `json:"rain"`
+}
+
+type openWeatherMapForecastResponse struct {
+	List []struct {
+		Dt      int64                     `json:"dt"`
+		Main    openWeatherMapMain        `json:"main"`
+		Weather []openWeatherMapCondition `json:"weather"`
+		Pop     float32                   `json:"pop"` // probability of precipitation, 0..1
+		Wind    openWeatherMapWind        `json:"wind"`
+		Rain    struct {
+			ThreeHours float32 `json:"3h"`
+		} `json:"rain"`
+	} `json:"list"`
+	City struct {
+		Name     string `json:"name"`
+		Timezone int    `json:"timezone"` // shift from UTC in seconds
+		Sunrise  int64  `json:"sunrise"`
+		Sunset   int64  `json:"sunset"`
+	} `json:"city"`
+}
+
+func (c *OpenWeatherMapClient) GetCurrentWeather(ctx context.Context, city string) (*WeatherCurrentResponse, error) {
+	var response openWeatherMapCurrentResponse
+	if err := c.get(ctx, "/weather", locationParams(city), &response); err != nil {
+		return nil, fmt.Errorf("could not get current weather: %w", err)
+	}
+
+	location := Location{Name: response.Name}
+	if !matchesCity(location, city) {
+		return nil, ErrCityNotFound
+	}
+
+	condition := openWeatherMapConditionOf(response.Weather)
+	current := CurrentWeather{
+		Temperature:     response.Main.Temperature,
+		FeelsLike:       response.Main.FeelsLike,
+		Humidity:        response.Main.Humidity,
+		IsDay:           openWeatherMapIsDay(response.Weather),
+		WindKph:         response.Wind.kph(),
+		WindDegree:      response.Wind.Deg,
+		WindDirection:   compassDirection(response.Wind.Deg),
+		GustKph:         roundTenth(response.Wind.Gust * 3.6),
+		PressureMb:      response.Main.Pressure,
+		PrecipitationMm: response.Rain.OneHour,
+		Cloud:           response.Clouds.All,
+		VisibilityKm:    roundTenth(response.Visibility / 1000),
+		Condition:       condition,
+	}
+	current.deriveImperial()
+
+	return &WeatherCurrentResponse{
+		Location:       location,
+		CurrentWeather: current,
+	}, nil
+}
+
+// GetForecast aggregates 3-hour steps into days, so hourly breakdown has 3-hour granularity
+// and forecast is limited to 5 days by the vendor
+func (c *OpenWeatherMapClient) GetForecast(ctx context.Context, city string, days int) (*WeatherForecastResponse, error) {
+	if days < 1 || days > MaxForecastDays {
+		return nil, fmt.Errorf("invalid forecast days number: %d", days)
+	}
+	days = min(days, openWeatherMapMaxForecastDays)
+
+	query := locationParams(city)
+	query.Set("cnt", strconv.Itoa(days*openWeatherMapStepsPerDay))
+
+	var response openWeatherMapForecastResponse
+	if err := c.get(ctx, "/forecast", query, &response); err != nil {
+		return nil, fmt.Errorf("could not get weather forecast: %w", err)
+	}
+
+	location := Location{Name: response.City.Name}
+	if !matchesCity(location, city) {
+		return nil, ErrCityNotFound
+	}
+
+	forecast := response.toForecast()
+
+	var current CurrentWeather
+	if len(forecast.Days) > 0 && len(forecast.Days[0].Hours) > 0 {
+		// closest step is the best approximation of the current conditions we have here
+		step := forecast.Days[0].Hours[0]
+		current = CurrentWeather{
+			Temperature:  step.Temperature,
+			TemperatureF: step.TemperatureF,
+			Humidity:     step.Humidity,
+			WindKph:      step.WindKph,
+			WindMph:      step.WindMph,
+			Condition:    step.Condition,
+		}
+	}
+
+	return &WeatherForecastResponse{
+		Location:       location,
+		CurrentWeather: current,
+		Forecast:       forecast,
+	}, nil
+}
+
+// ResolveLocation returns locations without timezone, as the vendor exposes only the UTC offset
+func (c *OpenWeatherMapClient) ResolveLocation(ctx context.Context, query LocationQuery) (*Location, error) {
+	params := locationParams(query.String())
+	if query.Id != "" {
+		id, err := parseLocationId(ProviderOpenWeatherMap, query.Id)
+		if err != nil {
+			return nil, err
+		}
+		params = url.Values{"id": {strconv.FormatInt(id, 10)}}
+	}
+
+	var response openWeatherMapCurrentResponse
+	if err := c.get(ctx, "/weather", params, &response); err != nil {
+		return nil, fmt.Errorf("could not resolve location: %w", err)
+	}
+
+	return &Location{
+		Id:        locationId(ProviderOpenWeatherMap, response.Id),
+		Name:      response.Name,
+		Country:   response.Sys.Country,
+		Latitude:  response.Coord.Latitude,
+		Longitude: response.Coord.Longitude,
+	}, nil
+}
+
+// SearchLocations uses the geocoding API, its results have no ids, so they are
+// meant to be resolved again by name or coordinates
+func (c *OpenWeatherMapClient) SearchLocations(ctx context.Context, query string, limit int) ([]Location, error) {
+	params := url.Values{
+		"q":     {query},
+		"limit": {strconv.Itoa(limit)},
+		"appid": {c.apiKey},
+	}
+
+	var results []openWeatherMapGeocodingResult
+	if err := c.transport.getJSON(ctx, c.geocodingUrl+"/direct?"+params.Encode(), http.StatusNotFound, &results); err != nil {
+		return nil, fmt.Errorf("could not search locations: %w", err)
+	}
+
+	locations := make([]Location, len(results))
+	for i, result := range results {
+		locations[i] = Location{
+			Name:      result.Name,
+			Region:    result.State,
+			Country:   result.Country,
+			Latitude:  result.Latitude,
+			Longitude: result.Longitude,
+		}
+	}
+
+	return rankLocations(query, locations, limit), nil
+}
+
+type openWeatherMapGeocodingResult struct {
+	Name      string  `json:"name"`
+	State     string  `json:"state"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+// locationParams maps the weather query either to the city name or to the coordinates params
+func locationParams(city string) url.Values {
+	if coordinates, ok := parseCoordinatesQuery(city); ok {
+		return url.Values{
+			"lat": {strconv.FormatFloat(coordinates.Latitude, 'f', 4, 64)},
+			"lon": {strconv.FormatFloat(coordinates.Longitude, 'f', 4, 64)},
+		}
+	}
+
+	return url.Values{"q": {city}}
+}
+
+func (c *OpenWeatherMapClient) get(ctx context.Context, endpoint string, query url.Values, dst any) error {
+	query.Set("appid", c.apiKey)
+	query.Set("units", "metric")
+	if lang := LanguageFrom(ctx); lang != "" {
+		query.Set("lang", lang)
+	}
+
+	return c.transport.getJSON(ctx, c.baseUrl+endpoint+"?"+query.Encode(), http.StatusNotFound, dst)
+}
+
+func (r openWeatherMapForecastResponse) toForecast() Forecast {
+	var (
+		zone        = time.FixedZone("", r.City.Timezone)
+		sunrise     = time.Unix(r.City.Sunrise, 0).In(zone)
+		sunset      = time.Unix(r.City.Sunset, 0).In(zone)
+		days        []ForecastDay
+		humiditySum float32
+	)
+
+	for _, step := range r.List {
+		localTime := time.Unix(step.Dt, 0).In(zone)
+		date := localTime.Format(dateLayout)
+
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, ForecastDay{
+				Date: date,
+				Day: DayForecast{
+					MinTemperature: step.Main.MinTemperature,
+					MaxTemperature: step.Main.MaxTemperature,
+				},
+			})
+			humiditySum = 0
+
+			// vendor returns astronomy for the current day only
+			if sunrise.Format(dateLayout) == date {
+				days[len(days)-1].Astro = Astro{
+					Sunrise: sunrise.Format(astroTimeLayout),
+					Sunset:  sunset.Format(astroTimeLayout),
+				}
+			}
+		}
+
+		day := &days[len(days)-1]
+		chanceOfRain := uint8(step.Pop * 100)
+
+		day.Hours = append(day.Hours, HourForecast{
+			TimeEpoch:     step.Dt,
+			Time:          localTime.Format(HourTimeLayout),
+			Temperature:   step.Main.Temperature,
+			Humidity:      step.Main.Humidity,
+			Precipitation: step.Rain.ThreeHours,
+			ChanceOfRain:  chanceOfRain,
+			WindKph:       step.Wind.kph(),
+			Condition:     openWeatherMapConditionOf(step.Weather),
+		})
+
+		day.Day.MinTemperature = min(day.Day.MinTemperature, step.Main.MinTemperature)
+		day.Day.MaxTemperature = max(day.Day.MaxTemperature, step.Main.MaxTemperature)
+		day.Day.TotalPrecipitation += step.Rain.ThreeHours
+		day.Day.ChanceOfRain = max(day.Day.ChanceOfRain, chanceOfRain)
+		day.Day.MaxWind = max(day.Day.MaxWind, step.Wind.kph())
+		humiditySum += float32(step.Main.Humidity)
+
+		steps := float32(len(day.Hours))
+		day.Day.AvgHumidity = humiditySum / steps
+		day.Day.AvgTemperature += (step.Main.Temperature - day.Day.AvgTemperature) / steps
+	}
+
+	// there is no daily condition in the vendor response, so the middle step describes the day
+	for i := range days {
+		hours := days[i].Hours
+		days[i].Day.Condition = hours[len(hours)/2].Condition
+	}
+
+	forecast := Forecast{Days: days}
+	forecast.deriveImperial()
+
+	return forecast
+}
+
+// openWeatherMapConditionOf takes the primary condition, mapping its id onto the weatherapi.com code
+func openWeatherMapConditionOf(conditions []openWeatherMapCondition) WeatherCondition {
+	if len(conditions) == 0 {
+		return WeatherCondition{}
+	}
+	primary := conditions[0]
+
+	// vendor returns descriptions in lower case ("light rain")
+	description := []rune(primary.Description)
+	if len(description) > 0 {
+		description[0] = unicode.ToUpper(description[0])
+	}
+
+	condition := WeatherCondition{Text: string(description), Code: openWeatherMapCode(primary.Id)}
+	if primary.Icon != "" {
+		condition.Icon = "https://openweathermap.org/img/wn/" + primary.Icon + "@2x.png"
+	}
+
+	return condition
+}
+
+// openWeatherMapIsDay tells the day by the icon variant, as the vendor has no such flag
+func openWeatherMapIsDay(conditions []openWeatherMapCondition) uint8 {
+	if len(conditions) > 0 && strings.HasSuffix(conditions[0].Icon, "n") {
+		return 0
+	}
+
+	return 1
+}
+
+// openWeatherMapCode maps the vendor condition id (https://openweathermap.org/weather-conditions)
+// onto the closest weatherapi.com condition code
+func openWeatherMapCode(id int) int {
+	switch {
+	case id >= 210 && id < 230:
+		return 1087 // thunder without precipitation
+	case id >= 200 && id < 300:
+		return 1276
+	case id >= 300 && id < 400:
+		return 1153
+	case id == 500:
+		return 1183
+	case id == 501:
+		return 1189
+	case id >= 502 && id <= 504:
+		return 1195
+	case id == 511:
+		return 1201
+	case id == 520:
+		return 1240
+	case id == 522:
+		return 1246
+	case id >= 520 && id < 600:
+		return 1243
+	case id == 600:
+		return 1213
+	case id == 601:
+		return 1219
+	case id == 602:
+		return 1225
+	case id >= 611 && id <= 616:
+		return 1204
+	case id == 620:
+		return 1255
+	case id >= 621 && id < 700:
+		return 1258
+	case id == 741:
+		return 1135
+	case id >= 700 && id < 800:
+		return 1030
+	case id == 800:
+		return 1000
+	case id == 801:
+		return 1003
+	case id == 802:
+		return 1006
+	case id == 803, id == 804:
+		return 1009
+	default:
+		return 0
+	}
+}
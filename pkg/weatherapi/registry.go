@@ -0,0 +1,71 @@
+package weatherapi
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	ProviderWeatherAPI     = "weatherapi"
+	ProviderOpenMeteo      = "openmeteo"
+	ProviderOpenWeatherMap = "openweathermap"
+)
+
+// ProviderFactory builds a provider on demand, so only the selected ones have to be configured
+type ProviderFactory func() (WeatherProvider, error)
+
+type Registry struct {
+	mu        sync.Mutex
+	factories map[string]ProviderFactory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		factories: make(map[string]ProviderFactory),
+	}
+}
+
+func (r *Registry) Register(name string, factory ProviderFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.factories[name] = factory
+}
+
+// Build creates the providers listed in priority order. A single provider
+// is returned as is, several ones are wrapped into the FailoverProvider.
+func (r *Registry) Build(priority []string, opts FailoverOpts) (WeatherProvider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(priority) == 0 {
+		return nil, fmt.Errorf("no weather providers selected")
+	}
+
+	providers := make([]NamedProvider, 0, len(priority))
+	seen := make(map[string]bool, len(priority))
+	for _, name := range priority {
+		if seen[name] {
+			return nil, fmt.Errorf("weather provider %q is listed twice", name)
+		}
+		seen[name] = true
+
+		factory, ok := r.factories[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown weather provider %q", name)
+		}
+
+		provider, err := factory()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build weather provider %q: %w", name, err)
+		}
+
+		providers = append(providers, NamedProvider{Name: name, Provider: provider})
+	}
+
+	if len(providers) == 1 {
+		return providers[0].Provider, nil
+	}
+
+	return NewFailoverProvider(opts, providers...), nil
+}
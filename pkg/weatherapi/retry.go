@@ -0,0 +1,99 @@
+package weatherapi
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// permanentError marks an attempt failure that must not be retried
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error {
+	return &permanentError{err}
+}
+
+type retrier struct {
+	maxRetries int
+	base       time.Duration
+	max        time.Duration
+}
+
+// do runs attempt until it succeeds, returns a permanent error, the retries are exhausted
+// or ctx is done. Attempt may return a server-requested delay (Retry-After) that takes
+// precedence over the computed backoff when it is longer.
+func (r retrier) do(ctx context.Context, attempt func() (retryAfter time.Duration, err error)) error {
+	for i := 0; ; i++ {
+		retryAfter, err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		var permErr *permanentError
+		if errors.As(err, &permErr) {
+			return permErr.err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if i >= r.maxRetries {
+			return err
+		}
+
+		delay := r.backoff(i)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns an exponential delay for the given attempt with "equal jitter":
+// half of the delay is fixed and the other half is random, so retries of
+// concurrent callers spread out while still growing with every attempt
+func (r retrier) backoff(attempt int) time.Duration {
+	delay := r.base << attempt
+	if delay <= 0 || delay > r.max {
+		delay = r.max
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter supports both forms of the header: delay-seconds and HTTP-date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,104 @@
+package weatherapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRequestTimeout = 5 * time.Second
+	defaultMaxRetries     = 3
+	defaultBackoffBase    = 200 * time.Millisecond
+	defaultBackoffMax     = 5 * time.Second
+)
+
+// ClientOpts configures the transport behaviour of the provider clients, zero values fall back to defaults
+type ClientOpts struct {
+	HTTPClient *http.Client
+	// RequestTimeout bounds every single attempt, not the whole retry sequence
+	RequestTimeout time.Duration
+	// MaxRetries of zero means default, negative value disables retries
+	MaxRetries  int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// transport executes GET requests with per-attempt deadlines and retries of transient failures
+type transport struct {
+	http    *http.Client
+	timeout time.Duration
+	retrier retrier
+}
+
+func newTransport(opts ClientOpts) transport {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{}
+	}
+	if opts.RequestTimeout <= 0 {
+		opts.RequestTimeout = defaultRequestTimeout
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	} else if opts.MaxRetries == 0 {
+		opts.MaxRetries = defaultMaxRetries
+	}
+	if opts.BackoffBase <= 0 {
+		opts.BackoffBase = defaultBackoffBase
+	}
+	if opts.BackoffMax <= 0 {
+		opts.BackoffMax = defaultBackoffMax
+	}
+
+	return transport{
+		http:    opts.HTTPClient,
+		timeout: opts.RequestTimeout,
+		retrier: retrier{
+			maxRetries: opts.MaxRetries,
+			base:       opts.BackoffBase,
+			max:        opts.BackoffMax,
+		},
+	}
+}
+
+// getJSON decodes a successful response body into dst. The notFoundStatus is
+// the vendor-specific status code that means the requested location is unknown.
+func (t transport) getJSON(ctx context.Context, requestUrl string, notFoundStatus int, dst any) error {
+	return t.retrier.do(ctx, func() (time.Duration, error) {
+		attemptCtx, cancel := context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(attemptCtx, http.MethodGet, requestUrl, nil)
+		if err != nil {
+			return 0, permanent(fmt.Errorf("failed to build request: %w", err))
+		}
+
+		resp, err := t.http.Do(req)
+		if err != nil {
+			// transport errors (including per-attempt timeouts) are worth another try
+			return 0, err
+		}
+
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+		case resp.StatusCode == notFoundStatus:
+			return 0, permanent(ErrCityNotFound)
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError:
+			return parseRetryAfter(resp.Header.Get("Retry-After")), fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		default:
+			return 0, permanent(fmt.Errorf("unexpected status code: %d", resp.StatusCode))
+		}
+
+		if err = json.NewDecoder(resp.Body).Decode(dst); err != nil {
+			return 0, permanent(fmt.Errorf("failed to decode response: %w", err))
+		}
+
+		return 0, nil
+	})
+}
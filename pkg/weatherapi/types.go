@@ -1,21 +1,133 @@
 package weatherapi
 
+// layouts of the local date and times in the shared model, matching the weatherapi.com format
+const (
+	dateLayout = "2006-01-02"
+	// HourTimeLayout is the local time of the HourForecast
+	HourTimeLayout  = "2006-01-02 15:04"
+	astroTimeLayout = "03:04 PM"
+)
+
+// WeatherCondition Code is the weatherapi.com condition code (e.g. 1000 for clear),
+// the other providers map their own codes onto it. Icon is the provider icon URL, if any.
 type WeatherCondition struct {
 	Text string `json:"text"`
+	Icon string `json:"icon"`
+	Code int    `json:"code"`
 }
 
 type Location struct {
-	Name string `json:"name"`
+	// Id is the canonical "<provider>:<provider id>" identifier, set by the location resolution only
+	Id        string  `json:"-"`
+	Name      string  `json:"name"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	// Timezone is an IANA name (e.g. "Europe/Kyiv"), can be empty if the provider doesn't know it
+	Timezone string `json:"tz_id"`
 }
 
+// CurrentWeather is the current conditions payload, the values are given in both unit systems
 type CurrentWeather struct {
-	Temperature float32          `json:"temp_c"`
-	Humidity    uint8            `json:"humidity"`
-	Condition   WeatherCondition `json:"condition"`
+	Temperature  float32 `json:"temp_c"`
+	TemperatureF float32 `json:"temp_f"`
+	FeelsLike    float32 `json:"feelslike_c"`
+	FeelsLikeF   float32 `json:"feelslike_f"`
+	Humidity     uint8   `json:"humidity"`
+	// IsDay is 1 in the daytime and 0 at night
+	IsDay uint8 `json:"is_day"`
+
+	WindKph float32 `json:"wind_kph"`
+	WindMph float32 `json:"wind_mph"`
+	// WindDegree is the direction the wind blows from, WindDirection is its 16-point compass name (e.g. "NNW")
+	WindDegree    uint16  `json:"wind_degree"`
+	WindDirection string  `json:"wind_dir"`
+	GustKph       float32 `json:"gust_kph"`
+	GustMph       float32 `json:"gust_mph"`
+
+	PressureMb      float32 `json:"pressure_mb"`
+	PressureIn      float32 `json:"pressure_in"`
+	PrecipitationMm float32 `json:"precip_mm"`
+	PrecipitationIn float32 `json:"precip_in"`
+	// Cloud is the cloud cover in percent
+	Cloud           uint8   `json:"cloud"`
+	UV              float32 `json:"uv"`
+	VisibilityKm    float32 `json:"vis_km"`
+	VisibilityMiles float32 `json:"vis_miles"`
+
+	Condition WeatherCondition `json:"condition"`
+	// AirQuality is returned only if requested (see WithAirQuality) and supported by the provider
+	AirQuality *AirQuality `json:"air_quality,omitempty"`
+}
+
+// AirQuality has the pollutant concentrations in μg/m3 and the indexes of them
+type AirQuality struct {
+	CO   float32 `json:"co"`
+	NO2  float32 `json:"no2"`
+	O3   float32 `json:"o3"`
+	SO2  float32 `json:"so2"`
+	PM25 float32 `json:"pm2_5"`
+	PM10 float32 `json:"pm10"`
+	// USEPAIndex is 1 (good) to 6 (hazardous), GBDefraIndex is 1 (low) to 10 (very high)
+	USEPAIndex   uint8 `json:"us-epa-index"`
+	GBDefraIndex uint8 `json:"gb-defra-index"`
 }
 
 type WeatherCurrentResponse struct {
-	// adding only necessary fields for brevity
 	CurrentWeather CurrentWeather `json:"current"`
 	Location       Location       `json:"location"`
 }
+
+type DayForecast struct {
+	MaxTemperature     float32          `json:"maxtemp_c"`
+	MaxTemperatureF    float32          `json:"maxtemp_f"`
+	MinTemperature     float32          `json:"mintemp_c"`
+	MinTemperatureF    float32          `json:"mintemp_f"`
+	AvgTemperature     float32          `json:"avgtemp_c"`
+	AvgTemperatureF    float32          `json:"avgtemp_f"`
+	AvgHumidity        float32          `json:"avghumidity"`
+	TotalPrecipitation float32          `json:"totalprecip_mm"`
+	ChanceOfRain       uint8            `json:"daily_chance_of_rain"`
+	ChanceOfSnow       uint8            `json:"daily_chance_of_snow"`
+	MaxWind            float32          `json:"maxwind_kph"`
+	MaxWindMph         float32          `json:"maxwind_mph"`
+	Condition          WeatherCondition `json:"condition"`
+}
+
+type Astro struct {
+	// local time in "hh:mm AM/PM" format, as returned by the provider
+	Sunrise string `json:"sunrise"`
+	Sunset  string `json:"sunset"`
+}
+
+type HourForecast struct {
+	TimeEpoch     int64            `json:"time_epoch"`
+	Time          string           `json:"time"` // local time in "yyyy-mm-dd hh:mm" format
+	Temperature   float32          `json:"temp_c"`
+	TemperatureF  float32          `json:"temp_f"`
+	Humidity      uint8            `json:"humidity"`
+	Precipitation float32          `json:"precip_mm"`
+	ChanceOfRain  uint8            `json:"chance_of_rain"`
+	ChanceOfSnow  uint8            `json:"chance_of_snow"`
+	WindKph       float32          `json:"wind_kph"`
+	WindMph       float32          `json:"wind_mph"`
+	Condition     WeatherCondition `json:"condition"`
+}
+
+type ForecastDay struct {
+	Date  string         `json:"date"` // local date in "yyyy-mm-dd" format
+	Day   DayForecast    `json:"day"`
+	Astro Astro          `json:"astro"`
+	Hours []HourForecast `json:"hour"`
+}
+
+type Forecast struct {
+	Days []ForecastDay `json:"forecastday"`
+}
+
+type WeatherForecastResponse struct {
+	CurrentWeather CurrentWeather `json:"current"`
+	Location       Location       `json:"location"`
+	Forecast       Forecast       `json:"forecast"`
+}
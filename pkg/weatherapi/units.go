@@ -0,0 +1,113 @@
+package weatherapi
+
+import "math"
+
+// Units is the unit system the values are presented in, the model carries both of them
+type Units string
+
+const (
+	// UnitsMetric is Celsius and km/h
+	UnitsMetric Units = "metric"
+	// UnitsImperial is Fahrenheit and mph
+	UnitsImperial Units = "imperial"
+)
+
+func (u Units) Valid() bool {
+	switch u {
+	case UnitsMetric, UnitsImperial:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrDefault replaces the unknown (e.g. empty) units with the metric ones
+func (u Units) OrDefault() Units {
+	if u.Valid() {
+		return u
+	}
+
+	return UnitsMetric
+}
+
+// Temperature returns the Celsius or Fahrenheit value according to the units
+func (u Units) Temperature(celsius, fahrenheit float32) float32 {
+	if u == UnitsImperial {
+		return fahrenheit
+	}
+
+	return celsius
+}
+
+// Speed returns the km/h or mph value according to the units
+func (u Units) Speed(kph, mph float32) float32 {
+	if u == UnitsImperial {
+		return mph
+	}
+
+	return kph
+}
+
+// Value returns the metric or imperial value according to the units, e.g. of the pressure or visibility
+func (u Units) Value(metric, imperial float32) float32 {
+	if u == UnitsImperial {
+		return imperial
+	}
+
+	return metric
+}
+
+// deriveImperial fills the imperial values of the providers returning the metric ones only
+func (w *CurrentWeather) deriveImperial() {
+	w.TemperatureF = fahrenheit(w.Temperature)
+	w.FeelsLikeF = fahrenheit(w.FeelsLike)
+	w.WindMph = mph(w.WindKph)
+	w.GustMph = mph(w.GustKph)
+	w.PressureIn = roundHundredth(w.PressureMb * 0.02953)
+	w.PrecipitationIn = roundHundredth(w.PrecipitationMm / 25.4)
+	w.VisibilityMiles = miles(w.VisibilityKm)
+}
+
+func (f *Forecast) deriveImperial() {
+	for i := range f.Days {
+		day := &f.Days[i].Day
+		day.MaxTemperatureF = fahrenheit(day.MaxTemperature)
+		day.MinTemperatureF = fahrenheit(day.MinTemperature)
+		day.AvgTemperatureF = fahrenheit(day.AvgTemperature)
+		day.MaxWindMph = mph(day.MaxWind)
+
+		for j := range f.Days[i].Hours {
+			hour := &f.Days[i].Hours[j]
+			hour.TemperatureF = fahrenheit(hour.Temperature)
+			hour.WindMph = mph(hour.WindKph)
+		}
+	}
+}
+
+// the converted values are rounded to the precision of the weatherapi.com ones
+func fahrenheit(celsius float32) float32 {
+	return roundTenth(celsius*9/5 + 32)
+}
+
+func mph(kph float32) float32 {
+	return miles(kph)
+}
+
+func miles(km float32) float32 {
+	return roundTenth(km / 1.609344)
+}
+
+func roundTenth(value float32) float32 {
+	return float32(math.Round(float64(value)*10) / 10)
+}
+
+func roundHundredth(value float32) float32 {
+	return float32(math.Round(float64(value)*100) / 100)
+}
+
+var compassPoints = []string{"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE", "S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW"}
+
+// compassDirection names the wind direction in degrees the weatherapi.com way, e.g. 337 is "NNW"
+func compassDirection(degree uint16) string {
+	return compassPoints[int(math.Round(float64(degree%360)/22.5))%len(compassPoints)]
+}